@@ -0,0 +1,245 @@
+package rikka
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const blocklistKey = "blocklist"
+
+const blocklistIDKey = "blocklist:id"
+
+// The Redis set that excluded users were stored in before the blocklist.
+const legacyExcludeKey = "exclude"
+
+// BlockEntry is a single entry on the blocklist.
+// Entries without a guild apply everywhere, and entries without a plugin apply to every plugin.
+type BlockEntry struct {
+	ID          int64
+	UserID      string
+	UserName    string
+	GuildID     string
+	Plugin      string
+	Reason      string
+	AddedBy     string
+	AddedByName string
+	Added       time.Time
+	Expires     time.Time
+}
+
+// Expired returns whether the entry has expired, entries without an expiry never expire.
+func (e *BlockEntry) Expired() bool {
+	return !e.Expires.IsZero() && time.Now().After(e.Expires)
+}
+
+// Matches returns whether the entry blocks a user from a plugin in a guild.
+// An empty plugin name only matches entries for every plugin.
+func (e *BlockEntry) Matches(userID, guildID, plugin string) bool {
+	if e.UserID != userID || e.Expired() {
+		return false
+	}
+	if e.GuildID != "" && e.GuildID != guildID {
+		return false
+	}
+	return e.Plugin == "" || strings.EqualFold(e.Plugin, plugin)
+}
+
+// blocklist is the in-memory cache of the blocklist, which is stored in Redis so it is shared by every process.
+type blocklist struct {
+	sync.RWMutex
+
+	entries map[int64]*BlockEntry
+	users   map[string][]*BlockEntry
+}
+
+func newBlocklist() *blocklist {
+	return &blocklist{
+		entries: map[int64]*BlockEntry{},
+		users:   map[string][]*BlockEntry{},
+	}
+}
+
+// load reads the blocklist from Redis, replacing the cache.
+func (l *blocklist) load() error {
+	values, err := client.HGetAll(blocklistKey).Result()
+	if err != nil {
+		return err
+	}
+
+	entries := map[int64]*BlockEntry{}
+	users := map[string][]*BlockEntry{}
+	for _, v := range values {
+		e := &BlockEntry{}
+		if err := json.Unmarshal([]byte(v), e); err != nil {
+			log.Println("Error loading blocklist entry", err)
+			continue
+		}
+		entries[e.ID] = e
+		users[e.UserID] = append(users[e.UserID], e)
+	}
+
+	l.Lock()
+	l.entries = entries
+	l.users = users
+	l.Unlock()
+	return nil
+}
+
+// migrate moves users from the old exclude set onto the global blocklist.
+func (l *blocklist) migrate() {
+	ids, err := client.SMembers(legacyExcludeKey).Result()
+	if err != nil || len(ids) == 0 {
+		return
+	}
+
+	for _, id := range ids {
+		if _, err := l.add(&BlockEntry{
+			UserID: id,
+			Reason: "Imported from the exclude list",
+			Added:  time.Now(),
+		}); err != nil {
+			log.Println("Error migrating excluded user", err)
+			return
+		}
+	}
+	client.Del(legacyExcludeKey)
+}
+
+func (l *blocklist) add(e *BlockEntry) (*BlockEntry, error) {
+	id, err := client.Incr(blocklistIDKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	e.ID = id
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.HSet(blocklistKey, strconv.FormatInt(id, 10), string(b)).Err(); err != nil {
+		return nil, err
+	}
+
+	l.Lock()
+	l.entries[e.ID] = e
+	l.users[e.UserID] = append(l.users[e.UserID], e)
+	l.Unlock()
+	return e, nil
+}
+
+func (l *blocklist) remove(id int64) error {
+	if err := client.HDel(blocklistKey, strconv.FormatInt(id, 10)).Err(); err != nil {
+		return err
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	e := l.entries[id]
+	if e == nil {
+		return nil
+	}
+	delete(l.entries, id)
+
+	users := l.users[e.UserID][:0]
+	for _, ue := range l.users[e.UserID] {
+		if ue.ID != id {
+			users = append(users, ue)
+		}
+	}
+	if len(users) == 0 {
+		delete(l.users, e.UserID)
+	} else {
+		l.users[e.UserID] = users
+	}
+	return nil
+}
+
+func (l *blocklist) get(id int64) *BlockEntry {
+	l.RLock()
+	defer l.RUnlock()
+	return l.entries[id]
+}
+
+// blocked returns whether a user is blocked from a plugin in a guild.
+func (l *blocklist) blocked(userID, guildID, plugin string) bool {
+	l.RLock()
+	defer l.RUnlock()
+
+	for _, e := range l.users[userID] {
+		if e.Matches(userID, guildID, plugin) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasEntries returns whether a user has any entries, so that most messages can be let through without looking up their guild.
+func (l *blocklist) hasEntries(userID string) bool {
+	l.RLock()
+	defer l.RUnlock()
+	return len(l.users[userID]) > 0
+}
+
+// filter returns the entries that haven't expired and match a function, ordered by ID.
+func (l *blocklist) filter(f func(e *BlockEntry) bool) []*BlockEntry {
+	l.RLock()
+	defer l.RUnlock()
+
+	entries := []*BlockEntry{}
+	for _, e := range l.entries {
+		if !e.Expired() && f(e) {
+			entries = append(entries, e)
+		}
+	}
+	sort.Sort(byBlockID(entries))
+	return entries
+}
+
+// prune removes expired entries.
+func (l *blocklist) prune() {
+	l.RLock()
+	expired := []int64{}
+	for id, e := range l.entries {
+		if e.Expired() {
+			expired = append(expired, id)
+		}
+	}
+	l.RUnlock()
+
+	for _, id := range expired {
+		if err := l.remove(id); err != nil {
+			log.Println("Error removing expired blocklist entry", err)
+		}
+	}
+}
+
+type byBlockID []*BlockEntry
+
+func (e byBlockID) Len() int           { return len(e) }
+func (e byBlockID) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+func (e byBlockID) Less(i, j int) bool { return e[i].ID < e[j].ID }
+
+// IsBlocked returns whether the sender of a message is blocked from using a plugin.
+// An empty plugin name checks whether they are blocked from using the bot at all.
+// The bot owner is never blocked.
+func (b *Bot) IsBlocked(service Service, message Message, plugin string) bool {
+	s := b.Services[service.Name()]
+	if s == nil || s.blocklist == nil || !s.blocklist.hasEntries(message.UserID()) {
+		return false
+	}
+	if service.IsBotOwner(message) {
+		return false
+	}
+
+	guildID := ""
+	if !service.IsPrivate(message) {
+		guildID = message.GuildID()
+	}
+	return s.blocklist.blocked(message.UserID(), guildID, plugin)
+}
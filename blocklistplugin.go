@@ -0,0 +1,371 @@
+package rikka
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var blockUserRegex = regexp.MustCompile(`^(?:<@!?(\d+)>|(\d+))$`)
+
+var blockDurationRegex = regexp.MustCompile(`^(\d+)([dw])$`)
+
+type blocklistPlugin struct {
+	list *blocklist
+}
+
+// Name returns the name of the plugin.
+func (p *blocklistPlugin) Name() string {
+	return "Blocklist"
+}
+
+// Help returns a list of help strings that are printed when the user requests them.
+func (p *blocklistPlugin) Help(bot *Bot, service Service, message Message, detailed bool) []string {
+	if !service.IsModerator(message) {
+		return nil
+	}
+
+	help := CommandHelp(service, "blocklist", "", bot.Translate(service, message, "blocklist.help"))
+	if detailed {
+		help = append(help, []string{
+			CommandHelp(service, "block", "<@user|id> [plugin:<name>] [duration] [reason]", bot.Translate(service, message, "blocklist.help.add"))[0],
+			CommandHelp(service, "unblock", "<@user|id|#entry>", bot.Translate(service, message, "blocklist.help.remove"))[0],
+			CommandHelp(service, "blocklist", "search <query>", bot.Translate(service, message, "blocklist.help.search"))[0],
+		}...)
+		if service.IsBotOwner(message) {
+			help = append(help, bot.Translate(service, message, "blocklist.help.global"))
+		}
+	}
+	return help
+}
+
+// parseBlockDuration parses a duration, in addition to Go durations days and weeks are supported, eg. "7d" or "2w".
+func parseBlockDuration(s string) (time.Duration, bool) {
+	if m := blockDurationRegex.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		d := time.Duration(n) * 24 * time.Hour
+		if m[2] == "w" {
+			d *= 7
+		}
+		return d, true
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return d, true
+	}
+	return 0, false
+}
+
+func blockUserName(message Message, userID string) string {
+	for _, u := range message.Mentions() {
+		if u.ID == userID {
+			return u.Username
+		}
+	}
+	return ""
+}
+
+// canManage returns whether the sender of a message can add or remove an entry.
+// Entries that apply everywhere can only be managed by the bot owner, guild entries by the moderators of that guild.
+func (p *blocklistPlugin) canManage(service Service, message Message, e *BlockEntry) bool {
+	if service.IsBotOwner(message) {
+		return true
+	}
+	if e.GuildID == "" || service.IsPrivate(message) {
+		return false
+	}
+	return e.GuildID == message.GuildID() && service.IsModerator(message)
+}
+
+func (p *blocklistPlugin) scope(bot *Bot, service Service, message Message, e *BlockEntry) string {
+	switch {
+	case e.GuildID == "" && e.Plugin == "":
+		return bot.Translate(service, message, "blocklist.scope.global")
+	case e.GuildID == "":
+		return bot.Translate(service, message, "blocklist.scope.plugin", e.Plugin)
+	case e.Plugin == "":
+		return bot.Translate(service, message, "blocklist.scope.guild", e.GuildID)
+	}
+	return bot.Translate(service, message, "blocklist.scope.guildplugin", e.Plugin, e.GuildID)
+}
+
+func (p *blocklistPlugin) format(bot *Bot, service Service, message Message, entries []*BlockEntry) string {
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		user := fmt.Sprintf("<@%s>", e.UserID)
+		if e.UserName != "" {
+			user = fmt.Sprintf("%s (`%s`)", e.UserName, e.UserID)
+		}
+
+		reason := e.Reason
+		if reason == "" {
+			reason = bot.Translate(service, message, "blocklist.noreason")
+		}
+
+		addedBy := e.AddedByName
+		if addedBy == "" {
+			addedBy = e.AddedBy
+		}
+
+		lines[i] = bot.Translate(service, message, "blocklist.entry", e.ID, user, p.scope(bot, service, message, e), reason, addedBy, e.Added.UTC().Format("2006-01-02"))
+		if !e.Expires.IsZero() {
+			lines[i] += " " + bot.Translate(service, message, "blocklist.expires", e.Expires.UTC().Format("2006-01-02 15:04 MST"))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// visible returns whether an entry is listed for the sender of a message.
+// Moderators see the entries of their guild, the owner sees the global entries in private messages.
+func (p *blocklistPlugin) visible(service Service, message Message, e *BlockEntry) bool {
+	if service.IsPrivate(message) {
+		return e.GuildID == ""
+	}
+	return e.GuildID == message.GuildID()
+}
+
+func (p *blocklistPlugin) changed(service Service) {
+	if d := DiscordService(service); d != nil {
+		d.Broadcast("blocklist", "")
+	}
+}
+
+func (p *blocklistPlugin) add(bot *Bot, service Service, message Message, parts []string) {
+	if len(parts) == 0 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "blocklist.usage.add", service.CommandPrefix()))
+		return
+	}
+
+	m := blockUserRegex.FindStringSubmatch(parts[0])
+	if m == nil {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "blocklist.usage.add", service.CommandPrefix()))
+		return
+	}
+	userID := m[1] + m[2]
+
+	e := &BlockEntry{
+		UserID:      userID,
+		UserName:    blockUserName(message, userID),
+		AddedBy:     message.UserID(),
+		AddedByName: message.UserName(),
+		Added:       time.Now(),
+	}
+
+	global := service.IsPrivate(message)
+	parts = parts[1:]
+	for len(parts) > 0 {
+		lower := strings.ToLower(parts[0])
+		if lower == "global" {
+			global = true
+		} else if strings.HasPrefix(lower, "plugin:") {
+			name := lower[len("plugin:"):]
+			for _, plugin := range bot.Services[service.Name()].Plugins {
+				if strings.ToLower(plugin.Name()) == name {
+					e.Plugin = plugin.Name()
+				}
+			}
+			if e.Plugin == "" {
+				service.SendMessage(message.Channel(), bot.Translate(service, message, "blocklist.unknownplugin", name))
+				return
+			}
+		} else if d, ok := parseBlockDuration(lower); ok {
+			e.Expires = e.Added.Add(d)
+		} else {
+			break
+		}
+		parts = parts[1:]
+	}
+	e.Reason = strings.Join(parts, " ")
+
+	if !global {
+		e.GuildID = message.GuildID()
+	}
+
+	if !p.canManage(service, message, e) {
+		if global {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "error.owner"))
+		} else {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "error.moderator"))
+		}
+		return
+	}
+
+	if _, err := p.list.add(e); err != nil {
+		log.Println("Error adding blocklist entry", err)
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+		return
+	}
+	p.changed(service)
+
+	service.SendMessage(message.Channel(), bot.Translate(service, message, "blocklist.added", fmt.Sprintf("<@%s>", userID), p.scope(bot, service, message, e), e.ID))
+}
+
+func (p *blocklistPlugin) remove(bot *Bot, service Service, message Message, parts []string) {
+	if len(parts) != 1 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "blocklist.usage.remove", service.CommandPrefix()))
+		return
+	}
+
+	entries := []*BlockEntry{}
+	if strings.HasPrefix(parts[0], "#") {
+		id, err := strconv.ParseInt(parts[0][1:], 10, 64)
+		if err != nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "blocklist.usage.remove", service.CommandPrefix()))
+			return
+		}
+		if e := p.list.get(id); e != nil {
+			if !p.canManage(service, message, e) {
+				service.SendMessage(message.Channel(), bot.Translate(service, message, "error.moderator"))
+				return
+			}
+			entries = append(entries, e)
+		}
+	} else {
+		m := blockUserRegex.FindStringSubmatch(parts[0])
+		if m == nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "blocklist.usage.remove", service.CommandPrefix()))
+			return
+		}
+		userID := m[1] + m[2]
+		entries = p.list.filter(func(e *BlockEntry) bool {
+			return e.UserID == userID && p.visible(service, message, e) && p.canManage(service, message, e)
+		})
+	}
+
+	if len(entries) == 0 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "blocklist.notfound"))
+		return
+	}
+
+	for _, e := range entries {
+		if err := p.list.remove(e.ID); err != nil {
+			log.Println("Error removing blocklist entry", err)
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+			return
+		}
+	}
+	p.changed(service)
+
+	service.SendMessage(message.Channel(), bot.TranslatePlural(service, message, "blocklist.removed", len(entries), len(entries)))
+}
+
+func (p *blocklistPlugin) search(bot *Bot, service Service, message Message, query string) {
+	if query == "" {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "blocklist.usage.search", service.CommandPrefix()))
+		return
+	}
+
+	owner := service.IsBotOwner(message)
+	lower := strings.ToLower(query)
+	entries := p.list.filter(func(e *BlockEntry) bool {
+		if !owner && !p.visible(service, message, e) {
+			return false
+		}
+		for _, field := range []string{e.UserID, e.UserName, e.Plugin, e.Reason, e.AddedBy, e.AddedByName, e.GuildID} {
+			if field != "" && strings.Contains(strings.ToLower(field), lower) {
+				return true
+			}
+		}
+		return false
+	})
+
+	if len(entries) == 0 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "blocklist.noresults", query))
+		return
+	}
+	service.SendMessage(message.Channel(), p.format(bot, service, message, entries))
+}
+
+func (p *blocklistPlugin) listEntries(bot *Bot, service Service, message Message) {
+	entries := p.list.filter(func(e *BlockEntry) bool {
+		return p.visible(service, message, e)
+	})
+
+	if len(entries) == 0 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "blocklist.empty"))
+		return
+	}
+	service.SendMessage(message.Channel(), p.format(bot, service, message, entries))
+}
+
+// Message handler.
+func (p *blocklistPlugin) Message(bot *Bot, service Service, message Message) {
+	defer MessageRecover()
+
+	if service.IsMe(message) {
+		return
+	}
+
+	block := MatchesCommand(service, "block", message)
+	unblock := MatchesCommand(service, "unblock", message)
+	if !block && !unblock && !MatchesCommand(service, "blocklist", message) {
+		return
+	}
+
+	if service.IsPrivate(message) && !service.IsBotOwner(message) {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.private"))
+		return
+	}
+	if !service.IsModerator(message) {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.moderator"))
+		return
+	}
+
+	p.list.prune()
+
+	// Mentions are replaced in the parsed message, the raw message is used to find the user IDs.
+	_, parts := ParseCommandString(service, message.RawMessage())
+
+	switch {
+	case block:
+		p.add(bot, service, message, parts)
+	case unblock:
+		p.remove(bot, service, message, parts)
+	case len(parts) == 0 || strings.ToLower(parts[0]) == "list":
+		p.listEntries(bot, service, message)
+	case strings.ToLower(parts[0]) == "add":
+		p.add(bot, service, message, parts[1:])
+	case strings.ToLower(parts[0]) == "remove":
+		p.remove(bot, service, message, parts[1:])
+	case strings.ToLower(parts[0]) == "search":
+		p.search(bot, service, message, strings.Join(parts[1:], " "))
+	default:
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "blocklist.usage.add", service.CommandPrefix()))
+	}
+}
+
+// Load will load plugin state from a byte array.
+// The blocklist is stored in Redis rather than the plugin state, so that every process shares it.
+func (p *blocklistPlugin) Load(bot *Bot, service Service, data []byte) error {
+	if err := p.list.load(); err != nil {
+		log.Println("Error loading blocklist", err)
+	}
+	p.list.migrate()
+
+	if d := DiscordService(service); d != nil {
+		d.OnBroadcast("blocklist", func(string) {
+			if err := p.list.load(); err != nil {
+				log.Println("Error loading blocklist", err)
+			}
+		})
+	}
+	return nil
+}
+
+// Save will save plugin state to a byte array.
+func (p *blocklistPlugin) Save() ([]byte, error) {
+	return nil, nil
+}
+
+// Stats will return the stats for a plugin.
+func (p *blocklistPlugin) Stats(bot *Bot, service Service, message Message) []string {
+	return nil
+}
+
+func newBlocklistPlugin(list *blocklist) *blocklistPlugin {
+	return &blocklistPlugin{
+		list: list,
+	}
+}
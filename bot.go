@@ -12,6 +12,7 @@ import (
 	"os"
 	"runtime/debug"
 	"sync"
+	"time"
 )
 
 // VersionString is the current version of the bot
@@ -24,6 +25,11 @@ type serviceEntry struct {
 	Plugins         map[string]Plugin
 	callbacks       map[string]chan Message
 	messageChannels []chan Message
+	language        *languagePlugin
+	timezone        *timezonePlugin
+	scheduler       *Scheduler
+	blocklist       *blocklist
+	replies         *replyTracker
 }
 
 // Bot enables registering of Services and Plugins.
@@ -32,6 +38,8 @@ type Bot struct {
 	ImgurID     string
 	ImgurAlbum  string
 	MashableKey string
+	// ReplyWindow is how long a message can be edited or deleted for its replies to be edited or deleted with it.
+	ReplyWindow time.Duration
 }
 
 // MessageRecover is the default panic handler for rikka.
@@ -44,12 +52,27 @@ func MessageRecover() {
 // NewBot will create a new bot.
 func NewBot() *Bot {
 	return &Bot{
-		Services: make(map[string]*serviceEntry, 0),
+		Services:    make(map[string]*serviceEntry, 0),
+		ReplyWindow: DefaultReplyWindow,
 	}
 }
 
+// dataDir returns the directory the plugin state of a service is saved in.
+//
+// Guilds are only handled by the process that runs their shard, so each process of a cluster saves the state of its guilds
+// in a directory of its own, eg. "Discord-4-7". The process that runs shard 0 also receives private messages, so it keeps
+// using the same directory as a single process, "Discord", along with the state of private conversations such as reminders set in them.
+// The state of users that isn't tied to a guild, such as their language, time zone, playlists and feedback bans, is stored
+// in Redis and shared by every process, so it is kept when the shards are split differently.
+func dataDir(service Service) string {
+	if d := DiscordService(service); d != nil && d.Clustered() && !d.RunsShard(0) {
+		return service.Name() + "-" + d.ShardRange()
+	}
+	return service.Name()
+}
+
 func (b *Bot) getData(service Service, plugin Plugin) []byte {
-	if b, err := ioutil.ReadFile(service.Name() + "/" + plugin.Name()); err == nil {
+	if b, err := ioutil.ReadFile(dataDir(service) + "/" + plugin.Name()); err == nil {
 		return b
 	}
 	return nil
@@ -65,8 +88,17 @@ func (b *Bot) RegisterService(service Service) {
 		Service:   service,
 		Plugins:   make(map[string]Plugin, 0),
 		callbacks: make(map[string]chan Message, 0),
+		language:  newLanguagePlugin(),
+		timezone:  newTimezonePlugin(),
+		scheduler: NewScheduler(),
+		blocklist: newBlocklist(),
+		replies:   newReplyTracker(),
 	}
 	b.RegisterPlugin(service, NewHelpPlugin())
+	b.RegisterPlugin(service, b.Services[serviceName].language)
+	b.RegisterPlugin(service, b.Services[serviceName].timezone)
+	b.RegisterPlugin(service, b.Services[serviceName].scheduler)
+	b.RegisterPlugin(service, newBlocklistPlugin(b.Services[serviceName].blocklist))
 }
 
 // RegisterPlugin registers a plugin on a service.
@@ -82,13 +114,43 @@ func (b *Bot) listen(service Service, messageChan <-chan Message) {
 	serviceName := service.Name()
 	for {
 		message := <-messageChan
-		if message.IsExcluded() {
+		if b.IsBlocked(service, message, "") {
 			continue
 		}
-		plugins := b.Services[serviceName].Plugins
-		for _, plugin := range plugins {
-			go plugin.Message(b, service, message)
+		s := b.Services[serviceName]
+
+		switch message.Type() {
+		case MessageTypeDelete:
+			go s.replies.deleteReplies(service, message)
+			for _, plugin := range s.Plugins {
+				go plugin.Message(b, service, message)
+			}
+			go b.callbacks(service, message)
+			continue
+		case MessageTypeUpdate:
+			// Edits that don't change the content, such as embeds being resolved, don't run commands again.
+			if e := s.replies.get(message.MessageID()); e != nil && e.content == message.RawMessage() {
+				continue
+			}
 		}
+
+		// Replies are recorded so that they can be edited when the message is edited.
+		rs := newReplyService(service, s.replies, message, b.ReplyWindow)
+		wg := sync.WaitGroup{}
+		for _, plugin := range s.Plugins {
+			if b.IsBlocked(service, message, plugin.Name()) {
+				continue
+			}
+			wg.Add(1)
+			go func(plugin Plugin) {
+				defer wg.Done()
+				plugin.Message(b, rs, message)
+			}(plugin)
+		}
+		go func() {
+			wg.Wait()
+			rs.finish()
+		}()
 		go b.callbacks(service, message)
 	}
 }
@@ -136,9 +198,14 @@ func (b *Bot) CloseCallback(service Service, uID string) {
 func (b *Bot) Open() {
 	for _, service := range b.Services {
 		if messageChan, err := service.Open(); err == nil {
+			// Jobs are loaded before plugins, so plugins can look up the jobs they scheduled before a restart.
+			service.scheduler.Load(b, service.Service, b.getData(service.Service, service.scheduler))
 			for _, plugin := range service.Plugins {
-				plugin.Load(b, service.Service, b.getData(service, plugin))
+				if plugin != Plugin(service.scheduler) {
+					plugin.Load(b, service.Service, b.getData(service.Service, plugin))
+				}
 			}
+			go service.scheduler.Run()
 			go b.listen(service.Service, messageChan)
 		} else {
 			log.Printf("Error creating service %s: %v\n", service.Name(), err)
@@ -150,7 +217,8 @@ func (b *Bot) Open() {
 func (b *Bot) Save() {
 	for _, service := range b.Services {
 		serviceName := service.Name()
-		if err := os.Mkdir(serviceName, os.ModePerm); err != nil {
+		dir := dataDir(service.Service)
+		if err := os.Mkdir(dir, os.ModePerm); err != nil {
 			if !os.IsExist(err) {
 				log.Println("Error creating service directory.")
 			}
@@ -159,7 +227,7 @@ func (b *Bot) Save() {
 			if data, err := plugin.Save(); err != nil {
 				log.Printf("Error saving plugin %s %s. %v", serviceName, plugin.Name(), err)
 			} else if data != nil {
-				if err := ioutil.WriteFile(serviceName+"/"+plugin.Name(), data, os.ModePerm); err != nil {
+				if err := ioutil.WriteFile(dir+"/"+plugin.Name(), data, os.ModePerm); err != nil {
 					log.Printf("Error saving plugin %s %s. %v", serviceName, plugin.Name(), err)
 				}
 			}
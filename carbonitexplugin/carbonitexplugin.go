@@ -19,19 +19,28 @@ func (p *carbonitexPlugin) carbonitexPluginLoadFunc(bot *rikka.Bot, service rikk
 		panic("Carbonitex Plugin only supports Discord.")
 	}
 
-	go p.Run(bot, service)
-	return nil
-}
-
-func (p *carbonitexPlugin) Run(bot *rikka.Bot, service rikka.Service) {
-	for {
-		<-time.After(5 * time.Minute)
-
-		http.PostForm("https://www.carbonitex.net/discord/data/botdata.php", url.Values{"key": {p.key}, "servercount": {fmt.Sprintf("%d", service.ChannelCount())}})
-
-		<-time.After(55 * time.Minute)
+	// The server count covers every process, so only the process running the first shard posts it.
+	if d := rikka.DiscordService(service); d != nil && !d.RunsShard(0) {
+		return nil
 	}
 
+	scheduler := bot.Scheduler(service)
+	scheduler.Handle("carbonitex", func(job *rikka.Job) {
+		p.post(service)
+	})
+	// The first count is posted a few minutes after starting, once the guilds have loaded.
+	return scheduler.Schedule(&rikka.Job{
+		ID:       "carbonitex",
+		Handler:  "carbonitex",
+		Kind:     rikka.JobInterval,
+		Time:     time.Now().Add(5 * time.Minute),
+		Interval: time.Hour,
+		CatchUp:  rikka.CatchUpSkip,
+	})
+}
+
+func (p *carbonitexPlugin) post(service rikka.Service) {
+	http.PostForm("https://www.carbonitex.net/discord/data/botdata.php", url.Values{"key": {p.key}, "servercount": {fmt.Sprintf("%d", service.ChannelCount())}})
 }
 
 // New will create a new carbonitex plugin.
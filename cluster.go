@@ -0,0 +1,237 @@
+package rikka
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// How often a process publishes the stats of the shards it runs.
+const clusterStatsInterval = 30 * time.Second
+
+const clusterBroadcastChannel = "cluster:broadcast"
+
+func clusterShardKey(shard int) string {
+	return fmt.Sprintf("cluster:shard:%d", shard)
+}
+
+type shardStats struct {
+	Guilds   int
+	Members  int
+	Channels int
+}
+
+type clusterBroadcast struct {
+	Command string
+	Data    string
+}
+
+// ParseShardRange parses a list of shard IDs, eg. "0-3", "4" or "0,2,4-6".
+func ParseShardRange(s string) ([]int, error) {
+	seen := map[int]bool{}
+	ids := []int{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		bounds := strings.SplitN(part, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid shard range %q", part)
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, fmt.Errorf("invalid shard range %q", part)
+			}
+		}
+		if first < 0 || last < first {
+			return nil, fmt.Errorf("invalid shard range %q", part)
+		}
+
+		for id := first; id <= last; id++ {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// formatShardRange formats a sorted list of shard IDs, contiguous IDs are formatted as a range.
+func formatShardRange(ids []int) string {
+	parts := []string{}
+	for i := 0; i < len(ids); {
+		j := i
+		for j+1 < len(ids) && ids[j+1] == ids[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(ids[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", ids[i], ids[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// Clustered returns whether this process only runs some of the shards.
+func (d *Discord) Clustered() bool {
+	return len(d.ShardIDs) > 0 && len(d.ShardIDs) < d.Shards
+}
+
+// RunsShard returns whether a shard is run by this process.
+func (d *Discord) RunsShard(shard int) bool {
+	for _, s := range d.Sessions {
+		if s.ShardID == shard {
+			return true
+		}
+	}
+	return false
+}
+
+// ShardRange returns the shards run by this process, eg. "0-3".
+func (d *Discord) ShardRange() string {
+	ids := make([]int, len(d.Sessions))
+	for i, s := range d.Sessions {
+		ids[i] = s.ShardID
+	}
+	return formatShardRange(ids)
+}
+
+func (d *Discord) localShardStats() map[int]shardStats {
+	stats := map[int]shardStats{}
+	for _, s := range d.Sessions {
+		st := shardStats{Guilds: len(s.State.Guilds)}
+		for _, g := range s.State.Guilds {
+			st.Members += g.MemberCount
+			st.Channels += len(g.Channels)
+		}
+		stats[s.ShardID] = st
+	}
+	return stats
+}
+
+// publishShardStats stores the stats of the shards this process runs, so the other processes can aggregate them.
+func (d *Discord) publishShardStats() {
+	for shard, st := range d.localShardStats() {
+		if st.Guilds > numGuildsPerShard {
+			log.Printf("Shard %d has %d guilds, more shards should be used\n", shard, st.Guilds)
+		}
+
+		b, err := json.Marshal(st)
+		if err != nil {
+			continue
+		}
+		if err := client.Set(clusterShardKey(shard), b, 3*clusterStatsInterval).Err(); err != nil {
+			log.Println("Error publishing shard stats", err)
+		}
+	}
+}
+
+// ClusterStats returns the number of guilds, members and channels on every shard, including those run by other processes.
+// Shards that haven't published their stats recently aren't counted.
+func (d *Discord) ClusterStats() (guilds, members, channels int) {
+	local := d.localShardStats()
+	for _, st := range local {
+		guilds += st.Guilds
+		members += st.Members
+		channels += st.Channels
+	}
+
+	if !d.Clustered() {
+		return
+	}
+
+	keys := []string{}
+	for shard := 0; shard < d.Shards; shard++ {
+		if _, ok := local[shard]; !ok {
+			keys = append(keys, clusterShardKey(shard))
+		}
+	}
+
+	values, err := client.MGet(keys...).Result()
+	if err != nil {
+		log.Println("Error retrieving shard stats", err)
+		return
+	}
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		st := shardStats{}
+		if err := json.Unmarshal([]byte(s), &st); err != nil {
+			continue
+		}
+		guilds += st.Guilds
+		members += st.Members
+		channels += st.Channels
+	}
+	return
+}
+
+// OnBroadcast registers a handler for a command broadcast to every process.
+func (d *Discord) OnBroadcast(command string, handler func(data string)) {
+	d.broadcastMutex.Lock()
+	defer d.broadcastMutex.Unlock()
+
+	if d.broadcastHandlers == nil {
+		d.broadcastHandlers = map[string][]func(string){}
+	}
+	d.broadcastHandlers[command] = append(d.broadcastHandlers[command], handler)
+}
+
+// Broadcast runs a command on every process, including this one.
+// This is used for owner commands that affect the whole bot, such as changing the game or quitting.
+func (d *Discord) Broadcast(command, data string) error {
+	if !d.Clustered() {
+		d.handleBroadcast(command, data)
+		return nil
+	}
+
+	b, err := json.Marshal(clusterBroadcast{command, data})
+	if err != nil {
+		return err
+	}
+	return client.Publish(clusterBroadcastChannel, string(b)).Err()
+}
+
+func (d *Discord) handleBroadcast(command, data string) {
+	d.broadcastMutex.Lock()
+	handlers := d.broadcastHandlers[command]
+	d.broadcastMutex.Unlock()
+
+	for _, handler := range handlers {
+		handler(data)
+	}
+}
+
+// runCluster publishes the shard stats of this process and handles broadcast commands.
+func (d *Discord) runCluster() {
+	pubsub := client.Subscribe(clusterBroadcastChannel)
+	go func() {
+		for m := range pubsub.Channel() {
+			b := clusterBroadcast{}
+			if err := json.Unmarshal([]byte(m.Payload), &b); err != nil {
+				log.Println("Error reading broadcast", err)
+				continue
+			}
+			d.handleBroadcast(b.Command, b.Data)
+		}
+	}()
+
+	for {
+		d.publishShardStats()
+		<-time.After(clusterStatsInterval)
+	}
+}
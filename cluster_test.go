@@ -0,0 +1,56 @@
+package rikka
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseShardRange(t *testing.T) {
+	tests := []struct {
+		s   string
+		ids []int
+	}{
+		{"0", []int{0}},
+		{"0-3", []int{0, 1, 2, 3}},
+		{"4,0-1", []int{0, 1, 4}},
+		{"0,2,4-6", []int{0, 2, 4, 5, 6}},
+		{"1-2,2-3", []int{1, 2, 3}},
+		{" 1 , 3 ", []int{1, 3}},
+		{"", []int{}},
+	}
+
+	for _, test := range tests {
+		ids, err := ParseShardRange(test.s)
+		if err != nil {
+			t.Errorf("ParseShardRange(%q) returned %v", test.s, err)
+			continue
+		}
+		if !reflect.DeepEqual(ids, test.ids) {
+			t.Errorf("ParseShardRange(%q) = %v, want %v", test.s, ids, test.ids)
+		}
+	}
+
+	for _, s := range []string{"a", "3-1", "-1", "1-a", "0-"} {
+		if _, err := ParseShardRange(s); err == nil {
+			t.Errorf("ParseShardRange(%q) didn't return an error", s)
+		}
+	}
+}
+
+func TestFormatShardRange(t *testing.T) {
+	tests := []struct {
+		ids []int
+		s   string
+	}{
+		{[]int{0}, "0"},
+		{[]int{0, 1, 2, 3}, "0-3"},
+		{[]int{0, 2, 4, 5, 6}, "0,2,4-6"},
+		{[]int{}, ""},
+	}
+
+	for _, test := range tests {
+		if s := formatShardRange(test.ids); s != test.s {
+			t.Errorf("formatShardRange(%v) = %q, want %q", test.ids, s, test.s)
+		}
+	}
+}
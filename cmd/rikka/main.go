@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"math/rand"
 	"net/http"
@@ -15,6 +16,7 @@ import (
 	"github.com/ThyLeader/rikka/discordavatarplugin"
 	"github.com/ThyLeader/rikka/emojiplugin"
 	"github.com/ThyLeader/rikka/feedbackplugin"
+	"github.com/ThyLeader/rikka/guildlogplugin"
 	"github.com/ThyLeader/rikka/imageplugin"
 	"github.com/ThyLeader/rikka/inviteplugin"
 	"github.com/ThyLeader/rikka/mathplugin"
@@ -33,12 +35,22 @@ var discordToken string
 var discordApplicationClientID string
 var discordOwnerUserID string
 var discordShards int
+var discordShardIDs []int
 var carbonitexKey string
 var neuralURL string
 var weebshKey string
+var guildLogChannel string
+var musicLibrary string
+var musicBitrate int
+var musicYoutubeDL string
+var musicIdleMinutes int
+
+var shardsFlag = flag.String("shards", "", "The shards this process runs, eg. 0-3. All shards are run when empty.")
+var totalFlag = flag.Int("total", 0, "The total number of shards, overrides the shards config value.")
 
 func init() {
 	rand.Seed(time.Now().UnixNano())
+	flag.Parse()
 
 	viper.AddConfigPath(".")
 	viper.SetConfigType("json")
@@ -70,8 +82,23 @@ func init() {
 	} else {
 		panic("clientid not set")
 	}
+	// A shard count of 0 uses the number of shards recommended by Discord.
+	discordShards = viper.GetInt("shards")
+	if *totalFlag > 0 {
+		discordShards = *totalFlag
+	}
+	if *shardsFlag != "" {
+		if discordShardIDs, err = rikka.ParseShardRange(*shardsFlag); err != nil {
+			panic(err)
+		}
+	}
 	neuralURL = viper.GetString("neuralurl")
 	weebshKey = viper.GetString("weebsh_key")
+	guildLogChannel = viper.GetString("guildlog_channel")
+	musicLibrary = viper.GetString("music_library")
+	musicBitrate = viper.GetInt("music_bitrate")
+	musicYoutubeDL = viper.GetString("music_youtubedl")
+	musicIdleMinutes = viper.GetInt("music_idle_minutes")
 }
 
 func main() {
@@ -80,6 +107,9 @@ func main() {
 	// Set our variables.
 	bot := rikka.NewBot()
 
+	var discord *rikka.Discord
+	discord = rikka.NewDiscord(discordToken)
+
 	// Generally CommandPlugins don't hold state, so we share one instance of the command plugin for all services.
 	cp := rikka.NewCommandPlugin()
 	cp.AddCommand("invite", inviteplugin.InviteCommand, inviteplugin.InviteHelp)
@@ -92,29 +122,35 @@ func main() {
 	cp.AddCommand("support", misccommands.MessageSupport, misccommands.HelpSupport)
 	cp.AddCommand("server", misccommands.MessageSupport, nil)
 	cp.AddCommand("ping", misccommands.MessagePing, misccommands.HelpPing)
-	cp.AddCommand("exclude", misccommands.MessageExclude, nil)
-	cp.AddCommand("unexclude", misccommands.MessageUnexclude, nil)
 	cp.AddCommand("lenny", misccommands.MessageLenny, misccommands.HelpLenny)
 	cp.AddCommand("quit", func(bot *rikka.Bot, service rikka.Service, message rikka.Message, args string, parts []string) {
 		if service.IsBotOwner(message) {
-			q <- true
+			// Quit every process when the shards are split across several.
+			discord.Broadcast("quit", "")
 		}
 	}, nil)
 
-	var discord *rikka.Discord
-	discord = rikka.NewDiscord(discordToken)
-
 	discord.ApplicationClientID = discordApplicationClientID
 	discord.OwnerUserID = discordOwnerUserID
 	discord.Shards = discordShards
+	discord.ShardIDs = discordShardIDs
+	discord.OnBroadcast("quit", func(string) {
+		q <- true
+	})
 	bot.RegisterService(discord)
 
 	bot.RegisterPlugin(discord, cp)
 
 	//bot.RegisterPlugin(discord, darkthemetextplugin.New())
 	bot.RegisterPlugin(discord, discordavatarplugin.New())
-	bot.RegisterPlugin(discord, musicplugin.New(discord))
+	bot.RegisterPlugin(discord, musicplugin.New(discord, musicplugin.Config{
+		Library:     musicLibrary,
+		Bitrate:     musicBitrate,
+		YoutubeDL:   musicYoutubeDL,
+		IdleTimeout: time.Duration(musicIdleMinutes) * time.Minute,
+	}))
 	bot.RegisterPlugin(discord, playedplugin.New())
+	bot.RegisterPlugin(discord, guildlogplugin.New(guildLogChannel))
 	bot.RegisterPlugin(discord, playingplugin.New())
 	bot.RegisterPlugin(discord, reminderplugin.New())
 	bot.RegisterPlugin(discord, mathplugin.New())
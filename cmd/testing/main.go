@@ -68,6 +68,8 @@ func init() {
 	} else {
 		panic("clientid not set")
 	}
+	// A shard count of 0 uses the number of shards recommended by Discord.
+	discordShards = viper.GetInt("shards")
 }
 
 func main() {
@@ -108,7 +110,10 @@ func main() {
 
 	//bot.RegisterPlugin(discord, darkthemetextplugin.New())
 	bot.RegisterPlugin(discord, discordavatarplugin.New())
-	bot.RegisterPlugin(discord, musicplugin.New(discord))
+	// The testing bot plays tones instead of downloading songs.
+	bot.RegisterPlugin(discord, musicplugin.New(discord, musicplugin.Config{
+		Resolver: &musicplugin.FakeResolver{},
+	}))
 	bot.RegisterPlugin(discord, playedplugin.New())
 	bot.RegisterPlugin(discord, playingplugin.New())
 	bot.RegisterPlugin(discord, reminderplugin.New())
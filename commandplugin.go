@@ -14,9 +14,10 @@ type CommandHelpFunc func(bot *Bot, service Service, message Message) (string, s
 type CommandMessageFunc func(bot *Bot, service Service, message Message, args string, parts []string)
 
 // NewCommandHelp creates a new Command Help function.
+// The help text is looked up in the message catalog, so it may be either a message key or plain text.
 func NewCommandHelp(args, help string) CommandHelpFunc {
 	return func(bot *Bot, service Service, message Message) (string, string) {
-		return args, help
+		return args, bot.Translate(service, message, help)
 	}
 }
 
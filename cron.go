@@ -0,0 +1,201 @@
+package rikka
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCron is returned when a cron expression can't be parsed.
+var ErrInvalidCron = errors.New("invalid cron expression")
+
+// How far ahead the next match of a cron expression is searched for.
+const cronSearchYears = 5
+
+// CronSchedule is a parsed cron expression with minute, hour, day of month, month and day of week fields.
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// Days match either field when both are restricted, like in cron.
+	domAny, dowAny bool
+}
+
+// parseCronValue parses a single value of a cron field, months and weekdays may also be names.
+func parseCronValue(s string, field int) (int, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, nil
+	}
+	if len(s) >= 3 {
+		s = strings.ToLower(s[:3])
+		switch field {
+		case 3:
+			for m := time.January; m <= time.December; m++ {
+				if strings.HasPrefix(strings.ToLower(m.String()), s) {
+					return int(m), nil
+				}
+			}
+		case 4:
+			for d := time.Sunday; d <= time.Saturday; d++ {
+				if strings.HasPrefix(strings.ToLower(d.String()), s) {
+					return int(d), nil
+				}
+			}
+		}
+	}
+	return 0, ErrInvalidCron
+}
+
+// parseCronField parses a cron field like "*", "1-5", "*/15" or "1,15" into a set of bits.
+func parseCronField(s string, field, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(s, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, ErrInvalidCron
+			}
+			step = n
+			part = part[:i]
+		}
+
+		start, end := min, max
+		if part != "*" {
+			r := strings.SplitN(part, "-", 2)
+			var err error
+			if start, err = parseCronValue(r[0], field); err != nil {
+				return 0, err
+			}
+			end = start
+			if len(r) == 2 {
+				if end, err = parseCronValue(r[1], field); err != nil {
+					return 0, err
+				}
+			}
+		}
+		// Sunday may be written as 7.
+		if field == 4 && end == 7 {
+			bits |= 1
+			if start == 7 {
+				continue
+			}
+			end = 6
+		}
+		if start < min || end > max || start > end {
+			return 0, ErrInvalidCron
+		}
+		for i := start; i <= end; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, nil
+}
+
+// ParseCron parses a cron expression like "0 9 * * 1-5".
+// Fields may be lists, ranges and steps, and months and weekdays may be written as names.
+func ParseCron(expression string) (*CronSchedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, ErrInvalidCron
+	}
+
+	ranges := [][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 6}}
+	bits := make([]uint64, 5)
+	for i, f := range fields {
+		b, err := parseCronField(f, i, ranges[i][0], ranges[i][1])
+		if err != nil {
+			return nil, err
+		}
+		bits[i] = b
+	}
+
+	return &CronSchedule{
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		domAny: fields[2] == "*",
+		dowAny: fields[4] == "*",
+	}, nil
+}
+
+func (c *CronSchedule) matchesDay(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	}
+	return dom || dow
+}
+
+// Next returns the first time the schedule matches after a time, in its location, or the zero time if there is none.
+// The schedule is matched against the wall clock, so it keeps to the same time of day across daylight saving changes.
+// A time that is repeated when the clocks go back only matches the first time, and times skipped when the clocks go forward
+// match once, right after the clocks change.
+func (c *CronSchedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	t := wallClock(after).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(cronSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		year, month, day := t.Date()
+		hour, minute, _ := t.Clock()
+
+		switch {
+		case c.month&(1<<uint(month)) == 0:
+			t = time.Date(year, month+1, 1, 0, 0, 0, 0, time.UTC)
+		case !c.matchesDay(t):
+			t = time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC)
+		case c.hour&(1<<uint(hour)) == 0:
+			t = time.Date(year, month, day, hour+1, 0, 0, 0, time.UTC)
+		case c.minute&(1<<uint(minute)) == 0:
+			t = t.Add(time.Minute)
+		default:
+			// A repeated time that already happened the first time is skipped.
+			if next := wallTime(t, loc); next.After(after) {
+				return next
+			}
+			t = t.Add(time.Minute)
+		}
+	}
+	return time.Time{}
+}
+
+// wallClock returns the wall clock time of a time, in UTC so it can be counted on without daylight saving changes.
+func wallClock(t time.Time) time.Time {
+	year, month, day := t.Date()
+	hour, minute, sec := t.Clock()
+	return time.Date(year, month, day, hour, minute, sec, t.Nanosecond(), time.UTC)
+}
+
+// wallTime returns the first time a location shows a wall clock time, or the time the clocks skipped past it.
+func wallTime(wall time.Time, loc *time.Location) time.Time {
+	// The wall clock time is in one of the offsets in use the days around it.
+	var first, earliest time.Time
+	for _, d := range []time.Duration{-48 * time.Hour, 48 * time.Hour} {
+		_, offset := wall.Add(d).In(loc).Zone()
+		t := wall.Add(-time.Duration(offset) * time.Second).In(loc)
+		if wallClock(t).Equal(wall) && (first.IsZero() || t.Before(first)) {
+			first = t
+		}
+		if earliest.IsZero() || t.Before(earliest) {
+			earliest = t
+		}
+	}
+	if !first.IsZero() {
+		return first
+	}
+
+	// The clocks went forward past the wall clock time, which happens right after they changed.
+	t := earliest
+	for !wallClock(t).After(wall) {
+		t = t.Add(time.Minute)
+	}
+	return t
+}
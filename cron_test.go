@@ -0,0 +1,107 @@
+package rikka
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	// A Wednesday.
+	after := time.Date(2024, time.January, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expression string
+		next       time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.January, 10, 12, 1, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.January, 10, 12, 15, 0, 0, time.UTC)},
+		{"0 9 * * *", time.Date(2024, time.January, 11, 9, 0, 0, 0, time.UTC)},
+		{"30 17 * * 1-5", time.Date(2024, time.January, 10, 17, 30, 0, 0, time.UTC)},
+		{"0 9 * * sat,sun", time.Date(2024, time.January, 13, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 7", time.Date(2024, time.January, 14, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jun *", time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		// Both days are restricted, so either matches.
+		{"0 0 15 * fri", time.Date(2024, time.January, 12, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		c, err := ParseCron(test.expression)
+		if err != nil {
+			t.Errorf("ParseCron(%q) returned %v", test.expression, err)
+			continue
+		}
+		if next := c.Next(after); !next.Equal(test.next) {
+			t.Errorf("ParseCron(%q).Next = %v, want %v", test.expression, next, test.next)
+		}
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, expression := range []string{"", "* * * *", "* * * * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := ParseCron(expression); err != ErrInvalidCron {
+			t.Errorf("ParseCron(%q) returned %v, want ErrInvalidCron", expression, err)
+		}
+	}
+}
+
+func TestParseCronNever(t *testing.T) {
+	c, err := ParseCron("0 0 31 feb *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next := c.Next(time.Now()); !next.IsZero() {
+		t.Errorf("a schedule that never matches returned %v", next)
+	}
+}
+
+func TestCronDaylightSaving(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	edt := time.FixedZone("EDT", -4*60*60)
+	est := time.FixedZone("EST", -5*60*60)
+
+	tests := []struct {
+		expression string
+		after      time.Time
+		next       []time.Time
+	}{
+		// The clocks go back from 2:00 to 1:00 on 2024-11-03, 1:30 only matches the first time.
+		{"30 1 * * *", time.Date(2024, time.November, 2, 12, 0, 0, 0, loc), []time.Time{
+			time.Date(2024, time.November, 3, 1, 30, 0, 0, edt),
+			time.Date(2024, time.November, 4, 1, 30, 0, 0, est),
+		}},
+		{"0 * * * *", time.Date(2024, time.November, 3, 0, 30, 0, 0, edt), []time.Time{
+			time.Date(2024, time.November, 3, 1, 0, 0, 0, edt),
+			time.Date(2024, time.November, 3, 2, 0, 0, 0, est),
+		}},
+		// The clocks go forward from 2:00 to 3:00 on 2024-03-10, 2:30 matches once when they do.
+		{"30 2 * * *", time.Date(2024, time.March, 9, 12, 0, 0, 0, loc), []time.Time{
+			time.Date(2024, time.March, 10, 3, 0, 0, 0, edt),
+			time.Date(2024, time.March, 11, 2, 30, 0, 0, edt),
+		}},
+		{"*/15 2 * * *", time.Date(2024, time.March, 10, 1, 50, 0, 0, est), []time.Time{
+			time.Date(2024, time.March, 10, 3, 0, 0, 0, edt),
+			time.Date(2024, time.March, 11, 2, 0, 0, 0, edt),
+		}},
+	}
+
+	for _, test := range tests {
+		c, err := ParseCron(test.expression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		after := test.after.In(loc)
+		for _, want := range test.next {
+			next := c.Next(after)
+			if !next.Equal(want) {
+				t.Errorf("ParseCron(%q).Next(%v) = %v, want %v", test.expression, after, next, want.In(loc))
+				break
+			}
+			after = next
+		}
+	}
+}
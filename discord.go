@@ -7,6 +7,7 @@ import (
 	"os"
 	"regexp"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -166,22 +167,19 @@ func (m *DiscordMessage) Guild() *discordgo.Guild {
 	return g
 }
 
-// IsExcluded checks if the user is excluded from using the bot
-func (m *DiscordMessage) IsExcluded() bool {
-	res, err := client.SIsMember("exclude", m.UserID()).Result()
-	if err != nil {
-		fmt.Println("error checking exclude", err.Error())
-		return false
-	}
-	return res
-}
-
 // Discord is a Service provider for Discord.
 type Discord struct {
 	args        []interface{}
 	messageChan chan Message
+	queue       *sendQueue
 
+	// The number of shards, when less than 1 the number recommended by Discord is used.
 	Shards int
+	// The shards run by this process, all shards are run when empty.
+	ShardIDs []int
+
+	broadcastMutex    sync.Mutex
+	broadcastHandlers map[string][]func(string)
 
 	// The first session, used to send messages (and maintain backwards compatibility).
 	Session             *discordgo.Session
@@ -192,10 +190,12 @@ type Discord struct {
 
 // NewDiscord creates a new discord service.
 func NewDiscord(args ...interface{}) *Discord {
-	return &Discord{
+	d := &Discord{
 		args:        args,
 		messageChan: make(chan Message, 200),
 	}
+	d.queue = newSendQueue(d)
+	return d
 }
 
 var channelIDRegex = regexp.MustCompile("<#[0-9]*>")
@@ -237,6 +237,28 @@ func (d *Discord) replaceRoleNames(message *discordgo.Message, content string) s
 	})
 }
 
+// ReplaceMentions replaces the user, role and channel mentions in part of a message with their names, like Message does.
+func (d *Discord) ReplaceMentions(message Message, content string) string {
+	m, ok := message.(*DiscordMessage)
+	if !ok {
+		return content
+	}
+
+	c := *m.DiscordgoMessage
+	c.Content = content
+	content = c.ContentWithMentionsReplaced()
+	content = d.replaceRoleNames(&c, content)
+	return d.replaceChannelNames(&c, content)
+}
+
+// MessageLink returns a link that jumps to a message, guildID is empty for private channels.
+func MessageLink(guildID, channelID, messageID string) string {
+	if guildID == "" {
+		guildID = "@me"
+	}
+	return fmt.Sprintf("https://discordapp.com/channels/%s/%s/%s", guildID, channelID, messageID)
+}
+
 func (d *Discord) onMessageCreate(s *discordgo.Session, message *discordgo.MessageCreate) {
 	if message.Content == "" {
 		return
@@ -278,19 +300,31 @@ func (d *Discord) Name() string {
 func (d *Discord) Open() (<-chan Message, error) {
 	shards := d.Shards
 	if shards < 1 {
-		shards = 1
+		shards = d.recommendedShards()
+	}
+	d.Shards = shards
+
+	ids := d.ShardIDs
+	if len(ids) == 0 {
+		for i := 0; i < shards; i++ {
+			ids = append(ids, i)
+		}
 	}
 
-	d.Sessions = make([]*discordgo.Session, shards)
+	d.Sessions = make([]*discordgo.Session, len(ids))
+
+	for i, id := range ids {
+		if id >= shards {
+			return nil, fmt.Errorf("shard %d is out of range, there are %d shards", id, shards)
+		}
 
-	for i := 0; i < shards; i++ {
 		session, err := discordgo.New(d.args...)
 		if err != nil {
 			return nil, err
 		}
 		session.State.TrackPresences = false
 		session.ShardCount = shards
-		session.ShardID = i
+		session.ShardID = id
 		session.AddHandler(d.onMessageCreate)
 		session.AddHandler(d.onMessageUpdate)
 		session.AddHandler(d.onMessageDelete)
@@ -304,9 +338,70 @@ func (d *Discord) Open() (<-chan Message, error) {
 		d.Sessions[i].Open()
 	}
 
+	if d.Clustered() {
+		go d.runCluster()
+	}
+
 	return d.messageChan, nil
 }
 
+// recommendedShards asks Discord how many shards the bot should use.
+func (d *Discord) recommendedShards() int {
+	session, err := discordgo.New(d.args...)
+	if err != nil {
+		return 1
+	}
+	gateway, err := session.GatewayBot()
+	if err != nil {
+		log.Println("Error retrieving recommended shard count", err)
+		return 1
+	}
+	if gateway.Shards < 1 {
+		return 1
+	}
+	return gateway.Shards
+}
+
+// ShardForGuild returns the shard ID that handles a guild.
+func (d *Discord) ShardForGuild(guildID string) int {
+	if d.Shards < 2 {
+		return 0
+	}
+	id, err := strconv.ParseUint(guildID, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return int((id >> 22) % uint64(d.Shards))
+}
+
+// SessionForGuild returns the session of the shard that handles a guild.
+func (d *Discord) SessionForGuild(guildID string) *discordgo.Session {
+	shard := d.ShardForGuild(guildID)
+	for _, s := range d.Sessions {
+		if s.ShardID == shard {
+			return s
+		}
+	}
+	return d.Session
+}
+
+// SessionForChannel returns the session of the shard that handles the guild of a channel.
+// Private channels are handled by the first session.
+func (d *Discord) SessionForChannel(channelID string) *discordgo.Session {
+	c, err := d.Channel(channelID)
+	if err != nil || c.GuildID == "" {
+		return d.Session
+	}
+	return d.SessionForGuild(c.GuildID)
+}
+
+// ForEachSession calls a function for the session of every shard.
+func (d *Discord) ForEachSession(f func(s *discordgo.Session)) {
+	for _, s := range d.Sessions {
+		f(s)
+	}
+}
+
 // IsMe returns whether or not a message was sent by the bot.
 func (d *Discord) IsMe(message Message) bool {
 	if d.Session.State.User == nil {
@@ -316,13 +411,20 @@ func (d *Discord) IsMe(message Message) bool {
 }
 
 // SendMessage sends a message.
+// Messages are queued per channel, and messages longer than MaxMessageLength are split.
 func (d *Discord) SendMessage(channel, message string) (*discordgo.Message, error) {
+	return d.sendReply(channel, "", message)
+}
+
+// sendReply sends a message in reply to the message with the ID origin.
+// Only replies to the same message are coalesced together.
+func (d *Discord) sendReply(channel, origin, message string) (*discordgo.Message, error) {
 	if channel == "" {
 		log.Println("Empty channel could not send message", message)
-		return nil, nil
+		return nil, ErrEmptyChannel
 	}
 
-	m, err := d.Session.ChannelMessageSend(channel, message)
+	m, err := d.queue.send(channel, origin, message, nil)
 	if err != nil {
 		log.Println("Error sending discord message: ", err)
 		return nil, err
@@ -335,10 +437,10 @@ func (d *Discord) SendMessage(channel, message string) (*discordgo.Message, erro
 func (d *Discord) SendMessageEmbed(channel string, embed *discordgo.MessageEmbed) (*discordgo.Message, error) {
 	if channel == "" {
 		log.Println("Empty channel could not send message")
-		return nil, nil
+		return nil, ErrEmptyChannel
 	}
 
-	m, err := d.Session.ChannelMessageSendEmbed(channel, embed)
+	m, err := d.queue.send(channel, "", "", embed)
 	if err != nil {
 		log.Println("Error sending discord message: ", err)
 		return nil, err
@@ -351,7 +453,7 @@ func (d *Discord) SendMessageEmbed(channel string, embed *discordgo.MessageEmbed
 func (d *Discord) SendAction(channel, message string) (*discordgo.Message, error) {
 	if channel == "" {
 		log.Println("Empty channel could not send message", message)
-		return nil, nil
+		return nil, ErrEmptyChannel
 	}
 
 	p, err := d.UserChannelPermissions(d.UserID(), channel)
@@ -360,13 +462,10 @@ func (d *Discord) SendAction(channel, message string) (*discordgo.Message, error
 	}
 
 	if p&discordgo.PermissionEmbedLinks == discordgo.PermissionEmbedLinks {
-		if _, err := d.Session.ChannelMessageSendEmbed(channel, &discordgo.MessageEmbed{
+		return d.SendMessageEmbed(channel, &discordgo.MessageEmbed{
 			Color:       d.UserColor(d.UserID(), channel),
 			Description: message,
-		}); err != nil {
-			return nil, err
-		}
-		return nil, nil
+		})
 	}
 
 	return d.SendMessage(channel, message)
@@ -374,12 +473,12 @@ func (d *Discord) SendAction(channel, message string) (*discordgo.Message, error
 
 // DeleteMessage deletes a message.
 func (d *Discord) DeleteMessage(channel, messageID string) error {
-	return d.Session.ChannelMessageDelete(channel, messageID)
+	return d.SessionForChannel(channel).ChannelMessageDelete(channel, messageID)
 }
 
 // SendFile sends a file.
 func (d *Discord) SendFile(channel, name string, r io.Reader) error {
-	if _, err := d.Session.ChannelFileSend(channel, name, r); err != nil {
+	if _, err := d.SessionForChannel(channel).ChannelFileSend(channel, name, r); err != nil {
 		log.Println("Error sending discord message: ", err)
 		return err
 	}
@@ -388,12 +487,12 @@ func (d *Discord) SendFile(channel, name string, r io.Reader) error {
 
 // BanUser bans a user.
 func (d *Discord) BanUser(channel, userID string, duration int) error {
-	return d.Session.GuildBanCreate(channel, userID, 0)
+	return d.SessionForGuild(channel).GuildBanCreate(channel, userID, 0)
 }
 
 // UnbanUser unbans a user.
 func (d *Discord) UnbanUser(channel, userID string) error {
-	return d.Session.GuildBanDelete(channel, userID)
+	return d.SessionForGuild(channel).GuildBanDelete(channel, userID)
 }
 
 // UserName returns the bots name.
@@ -415,13 +514,18 @@ func (d *Discord) UserID() string {
 // Join accept an invite or return an error.
 // If AlreadyJoinedError is return, @me has already accepted that invite.
 func (d *Discord) Join(join string) error {
-	if i, err := d.Session.Invite(join); err == nil {
+	i, err := d.Session.Invite(join)
+	if err == nil {
 		if _, err := d.Guild(i.Guild.ID); err == nil {
 			return ErrAlreadyJoined
 		}
 	}
 
-	if _, err := d.Session.InviteAccept(join); err != nil {
+	session := d.Session
+	if i != nil && i.Guild != nil {
+		session = d.SessionForGuild(i.Guild.ID)
+	}
+	if _, err := session.InviteAccept(join); err != nil {
 		return err
 	}
 	return nil
@@ -429,7 +533,7 @@ func (d *Discord) Join(join string) error {
 
 // Typing sets that the bot is typing.
 func (d *Discord) Typing(channel string) error {
-	return d.Session.ChannelTyping(channel)
+	return d.SessionForChannel(channel).ChannelTyping(channel)
 }
 
 // PrivateMessage will send a private message to a user.
@@ -498,9 +602,10 @@ func (d *Discord) IsModerator(message Message) bool {
 	return d.IsChannelOwner(message)
 }
 
-// ChannelCount returns the number of channels the bot is in.
+// ChannelCount returns the number of channels the bot is in, across all processes when clustered.
 func (d *Discord) ChannelCount() int {
-	return len(d.Guilds())
+	guilds, _, _ := d.ClusterStats()
+	return guilds
 }
 
 // GuildList returns an array of the current guilds and a separate array of the member count.
@@ -553,6 +658,9 @@ func (d *Discord) Channel(channelID string) (channel *discordgo.Channel, err err
 
 // Guild returns the guild object given a guildID
 func (d *Discord) Guild(guildID string) (guild *discordgo.Guild, err error) {
+	if guild, err = d.SessionForGuild(guildID).State.Guild(guildID); err == nil {
+		return guild, nil
+	}
 	for _, s := range d.Sessions {
 		guild, err = s.State.Guild(guildID)
 		if err == nil {
@@ -582,6 +690,12 @@ func (d *Discord) UserChannelPermissions(userID, channelID string) (apermissions
 	return
 }
 
+// MemberCount returns the number of members and channels in all guilds of all shards, across all processes when clustered.
+func (d *Discord) MemberCount() (members, channels int) {
+	_, members, channels = d.ClusterStats()
+	return
+}
+
 // UserColor returns the color int given a userID and a channelID
 func (d *Discord) UserColor(userID, channelID string) int {
 	for _, s := range d.Sessions {
@@ -619,7 +733,7 @@ func (d *Discord) NicknameForID(userID, userName, channelID string) string {
 
 // Member returns the member object of a specific userID and guildID
 func (d *Discord) Member(gID, uID string) (*discordgo.Member, error) {
-	return d.Session.GuildMember(gID, uID)
+	return d.SessionForGuild(gID).GuildMember(gID, uID)
 }
 
 // TimestampForID takes a Discord snowflake and parses a timestamp from it
@@ -633,5 +747,22 @@ func (d *Discord) TimestampForID(id string) (time.Time, error) {
 
 // EditMessage edits a message given the channelID, messageID, and the content you want to edit the message to
 func (d *Discord) EditMessage(cID, mID, content string) (*discordgo.Message, error) {
-	return d.Session.ChannelMessageEdit(cID, mID, content)
+	return d.SessionForChannel(cID).ChannelMessageEdit(cID, mID, content)
+}
+
+// EditMessageEmbed replaces the embed of a message given the channelID and messageID
+func (d *Discord) EditMessageEmbed(cID, mID string, embed *discordgo.MessageEmbed) (*discordgo.Message, error) {
+	return d.SessionForChannel(cID).ChannelMessageEditEmbed(cID, mID, embed)
+}
+
+// DiscordService returns the Discord service behind a service, or nil if it is not a Discord service.
+// The service passed to message handlers may be wrapped, so plugins should use this instead of a type assertion.
+func DiscordService(service Service) *Discord {
+	switch s := service.(type) {
+	case *Discord:
+		return s
+	case *replyService:
+		return DiscordService(s.Service)
+	}
+	return nil
 }
@@ -34,7 +34,7 @@ func avatarMessageFunc(bot *rikka.Bot, service rikka.Service, message rikka.Mess
 		id = match[1]
 	}
 
-	discord := service.(*rikka.Discord)
+	discord := rikka.DiscordService(service)
 
 	u, err := discord.Session.User(id)
 	if err != nil {
@@ -48,7 +48,7 @@ func avatarHelpFunc(bot *rikka.Bot, service rikka.Service, message rikka.Message
 	if detailed {
 		return nil
 	}
-	return rikka.CommandHelp(service, "avatar", "[@username]", "Returns a big version of your avatar, or a users avatar if provided.")
+	return rikka.CommandHelp(service, "avatar", "[@username]", bot.Translate(service, message, "avatar.help"))
 }
 
 // New creates a new discordavatar plugin.
@@ -0,0 +1,13 @@
+package discordavatarplugin
+
+import "github.com/ThyLeader/rikka"
+
+func init() {
+	rikka.RegisterMessages("en", map[string]string{
+		"avatar.help": "Returns a big version of your avatar, or a users avatar if provided.",
+	})
+
+	rikka.RegisterMessages("es", map[string]string{
+		"avatar.help": "Devuelve una versión grande de tu avatar, o el de un usuario si se indica.",
+	})
+}
@@ -47,7 +47,7 @@ func emojiMessageFunc(bot *rikka.Bot, service rikka.Service, message rikka.Messa
 		if len(submatches) != 0 {
 			h, err := http.Get("https://cdn.discordapp.com/emojis/" + submatches[1] + ".png")
 			if err != nil {
-				service.SendMessage(message.Channel(), ":octagonal_sign: : Error getting emoji from Discord CDN - "+err.Error())
+				service.SendMessage(message.Channel(), bot.Translate(service, message, "emoji.error.cdn", err.Error()))
 				return
 			}
 
@@ -65,7 +65,7 @@ func emojiMessageFunc(bot *rikka.Bot, service rikka.Service, message rikka.Messa
 					service.SendFile(message.Channel(), "emoji.png", f)
 					return
 				} else {
-					service.SendMessage(message.Channel(), ":octagonal_sign: : Error opening emoji - "+err.Error())
+					service.SendMessage(message.Channel(), bot.Translate(service, message, "emoji.error.open", err.Error()))
 					return
 				}
 			}
@@ -74,7 +74,7 @@ func emojiMessageFunc(bot *rikka.Bot, service rikka.Service, message rikka.Messa
 }
 
 func emojiHelpFunc(bot *rikka.Bot, service rikka.Service, message rikka.Message, detailed bool) []string {
-	help := rikka.CommandHelp(service, "emoji", "<emoji>", "Returns a big version of an emoji.")
+	help := rikka.CommandHelp(service, "emoji", "<emoji>", bot.Translate(service, message, "emoji.help"))
 
 	if detailed {
 		return nil
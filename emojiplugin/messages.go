@@ -0,0 +1,17 @@
+package emojiplugin
+
+import "github.com/ThyLeader/rikka"
+
+func init() {
+	rikka.RegisterMessages("en", map[string]string{
+		"emoji.help":       "Returns a big version of an emoji.",
+		"emoji.error.cdn":  ":octagonal_sign: : Error getting emoji from Discord CDN - %s",
+		"emoji.error.open": ":octagonal_sign: : Error opening emoji - %s",
+	})
+
+	rikka.RegisterMessages("es", map[string]string{
+		"emoji.help":       "Devuelve una versión grande de un emoji.",
+		"emoji.error.cdn":  ":octagonal_sign: : Error al obtener el emoji del CDN de Discord - %s",
+		"emoji.error.open": ":octagonal_sign: : Error al abrir el emoji - %s",
+	})
+}
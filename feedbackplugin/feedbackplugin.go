@@ -5,14 +5,74 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/ThyLeader/rikka"
+	"github.com/dustin/go-humanize"
+	"github.com/go-redis/redis"
 )
 
+// The channel feedback is posted in.
+const feedbackChannel = "359902628055875585"
+
+const (
+	ticketsKey  = "feedback:tickets"
+	ticketIDKey = "feedback:id"
+	bannedKey   = "feedback:banned"
+)
+
+var client = redis.NewClient(&redis.Options{
+	Addr:     "localhost:6379",
+	Password: "",
+	DB:       0,
+})
+
+// ErrTicketNotFound is returned when a ticket doesn't exist.
+var ErrTicketNotFound = errors.New("ticket not found")
+
+type ticketStatus string
+
+const (
+	ticketOpen   ticketStatus = "open"
+	ticketClosed ticketStatus = "closed"
+)
+
+type ticketReply struct {
+	UserID   string
+	UserName string
+	Owner    bool
+	Message  string
+	Time     time.Time
+}
+
+type ticket struct {
+	ID        int64
+	UserID    string
+	UserName  string
+	ChannelID string
+	GuildID   string
+	GuildName string
+	Message   string
+	Status    ticketStatus
+	Created   time.Time
+	Updated   time.Time
+	Replies   []*ticketReply
+}
+
+type byTicketID []*ticket
+
+func (t byTicketID) Len() int           { return len(t) }
+func (t byTicketID) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+func (t byTicketID) Less(i, j int) bool { return t[i].ID > t[j].ID }
+
 type feedbackPlugin struct {
 	sync.Mutex
 
+	// Banned is only read to move bans from older saves into Redis, where tickets are stored.
 	Banned map[string]bool
 }
 
@@ -24,39 +84,314 @@ func (p *feedbackPlugin) Load(bot *rikka.Bot, service rikka.Service, data []byte
 		}
 	}
 
+	p.Lock()
+	for uID, banned := range p.Banned {
+		if !banned {
+			continue
+		}
+		if err := client.SAdd(bannedKey, uID).Err(); err != nil {
+			log.Println("Error migrating feedback ban", err)
+			continue
+		}
+		delete(p.Banned, uID)
+	}
+	p.Unlock()
+
 	return nil
 }
 
 func (p *feedbackPlugin) Ban(uID string) error {
-	p.Lock()
-	b, ok := p.Banned[uID]
-	if !ok {
-		p.Banned[uID] = true
-	} else {
-		if b {
-			p.Unlock()
-			return errors.New("User already banned")
-		}
-		p.Banned[uID] = true
+	n, err := client.SAdd(bannedKey, uID).Result()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.New("User already banned")
 	}
-	p.Unlock()
 	return nil
 }
 
 func (p *feedbackPlugin) Unban(uID string) error {
-	p.Lock()
-	b, ok := p.Banned[uID]
+	n, err := client.SRem(bannedKey, uID).Result()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.New("User isn't banned")
+	}
+	return nil
+}
+
+func (p *feedbackPlugin) IsBanned(uID string) bool {
+	banned, err := client.SIsMember(bannedKey, uID).Result()
+	if err != nil {
+		log.Println("Error checking feedback ban", err)
+		return false
+	}
+	return banned
+}
+
+func (p *feedbackPlugin) saveTicket(t *ticket) error {
+	b, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return client.HSet(ticketsKey, strconv.FormatInt(t.ID, 10), string(b)).Err()
+}
+
+func (p *feedbackPlugin) ticket(id int64) (*ticket, error) {
+	s, err := client.HGet(ticketsKey, strconv.FormatInt(id, 10)).Result()
+	if err == redis.Nil {
+		return nil, ErrTicketNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	t := &ticket{}
+	if err := json.Unmarshal([]byte(s), t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// tickets returns the tickets that match a function, newest first.
+func (p *feedbackPlugin) tickets(f func(t *ticket) bool) ([]*ticket, error) {
+	values, err := client.HGetAll(ticketsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	tickets := []*ticket{}
+	for _, s := range values {
+		t := &ticket{}
+		if err := json.Unmarshal([]byte(s), t); err != nil {
+			continue
+		}
+		if f(t) {
+			tickets = append(tickets, t)
+		}
+	}
+	sort.Sort(byTicketID(tickets))
+	return tickets, nil
+}
+
+func (p *feedbackPlugin) create(message rikka.Message, text string) (*ticket, error) {
+	id, err := client.Incr(ticketIDKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	t := &ticket{
+		ID:        id,
+		UserID:    message.UserID(),
+		UserName:  message.UserName(),
+		ChannelID: message.Channel(),
+		GuildID:   message.GuildID(),
+		GuildName: message.GuildName(),
+		Message:   text,
+		Status:    ticketOpen,
+		Created:   time.Now(),
+		Updated:   time.Now(),
+	}
+	return t, p.saveTicket(t)
+}
+
+// parseTicketID parses a ticket ID, with or without a leading #.
+func parseTicketID(s string) (int64, bool) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(s, "#"), 10, 64)
+	return id, err == nil && id > 0
+}
+
+func (p *feedbackPlugin) formatTicket(bot *rikka.Bot, service rikka.Service, message rikka.Message, t *ticket) string {
+	return bot.Translate(service, message, "feedback.ticket", t.ID, bot.Translate(service, message, "feedback.status."+string(t.Status)), humanize.Time(t.Created), bot.TranslatePlural(service, message, "feedback.replies", len(t.Replies), len(t.Replies)), t.Message)
+}
+
+func (p *feedbackPlugin) list(bot *rikka.Bot, service rikka.Service, message rikka.Message, tickets []*ticket) {
+	if len(tickets) == 0 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "feedback.tickets.none"))
+		return
+	}
+	lines := []string{}
+	for i, t := range tickets {
+		if i == 10 {
+			lines = append(lines, bot.Translate(service, message, "feedback.tickets.more", len(tickets)-i))
+			break
+		}
+		lines = append(lines, p.formatTicket(bot, service, message, t))
+	}
+	service.SendMessage(message.Channel(), strings.Join(lines, "\n"))
+}
+
+func (p *feedbackPlugin) show(bot *rikka.Bot, service rikka.Service, message rikka.Message, t *ticket) {
+	lines := []string{p.formatTicket(bot, service, message, t)}
+	for _, r := range t.Replies {
+		lines = append(lines, bot.Translate(service, message, "feedback.reply.line", r.UserName, humanize.Time(r.Time), r.Message))
+	}
+	service.SendMessage(message.Channel(), strings.Join(lines, "\n"))
+}
+
+// relay sends an owner reply to the user of a ticket, by private message or in the channel the ticket came from.
+func (p *feedbackPlugin) relay(bot *rikka.Bot, service rikka.Service, t *ticket, text string) error {
+	dm := bot.TranslateGuild(service, t.GuildID, t.UserID, "feedback.reply.dm", t.ID, text)
+	if _, err := service.PrivateMessage(t.UserID, dm); err == nil {
+		return nil
+	}
+	_, err := service.SendMessage(t.ChannelID, fmt.Sprintf("<@%s> %s", t.UserID, dm))
+	return err
+}
+
+func (p *feedbackPlugin) reply(bot *rikka.Bot, service rikka.Service, message rikka.Message, parts []string, args string) {
+	if len(parts) < 3 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "feedback.reply.usage", service.CommandPrefix()))
+		return
+	}
+	id, ok := parseTicketID(parts[1])
 	if !ok {
-		p.Banned[uID] = false
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "feedback.reply.usage", service.CommandPrefix()))
+		return
+	}
+	// The reply keeps its formatting, so it is taken from the arguments rather than the parts.
+	text := strings.TrimSpace(args)
+	for _, part := range parts[:2] {
+		text = strings.TrimSpace(strings.TrimPrefix(text, part))
+	}
+
+	owner := service.IsBotOwner(message)
+	if !owner && p.IsBanned(message.UserID()) {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "feedback.banned"))
+		return
+	}
+
+	t, err := p.ticket(id)
+	if err == ErrTicketNotFound || (err == nil && !owner && t.UserID != message.UserID()) {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "feedback.notfound", id))
+		return
+	}
+	if err != nil {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+		return
+	}
+	if !owner && t.Status == ticketClosed {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "feedback.closed", id))
+		return
+	}
+
+	t.Replies = append(t.Replies, &ticketReply{
+		UserID:   message.UserID(),
+		UserName: message.UserName(),
+		Owner:    owner,
+		Message:  text,
+		Time:     time.Now(),
+	})
+	t.Updated = time.Now()
+	if err := p.saveTicket(t); err != nil {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+		return
+	}
+
+	if owner {
+		if err := p.relay(bot, service, t, text); err != nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "feedback.reply.error", err.Error()))
+			return
+		}
 	} else {
-		if !b {
-			p.Unlock()
-			return errors.New("User isn't banned")
+		service.SendMessage(feedbackChannel, fmt.Sprintf("%s (`%s`) replied to ticket #%d\n```%s```", message.UserName(), message.UserID(), t.ID, text))
+	}
+	service.SendMessage(message.Channel(), bot.Translate(service, message, "feedback.reply.sent", t.ID))
+}
+
+func (p *feedbackPlugin) setStatus(bot *rikka.Bot, service rikka.Service, message rikka.Message, parts []string, status ticketStatus) {
+	if len(parts) < 2 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "feedback.id"))
+		return
+	}
+	id, ok := parseTicketID(parts[1])
+	if !ok {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "feedback.id"))
+		return
+	}
+
+	t, err := p.ticket(id)
+	if err == ErrTicketNotFound {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "feedback.notfound", id))
+		return
+	}
+	if err != nil {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+		return
+	}
+
+	t.Status = status
+	t.Updated = time.Now()
+	if err := p.saveTicket(t); err != nil {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+		return
+	}
+	service.SendMessage(message.Channel(), bot.Translate(service, message, "feedback.status", t.ID, bot.Translate(service, message, "feedback.status."+string(status))))
+}
+
+// banTarget returns the user to ban, either a user ID or the author of a ticket.
+func (p *feedbackPlugin) banTarget(s string) (string, error) {
+	if strings.HasPrefix(s, "#") {
+		id, ok := parseTicketID(s)
+		if !ok {
+			return "", ErrTicketNotFound
+		}
+		t, err := p.ticket(id)
+		if err != nil {
+			return "", err
 		}
-		p.Banned[uID] = false
+		return t.UserID, nil
 	}
-	p.Unlock()
-	return nil
+	return s, nil
+}
+
+func (p *feedbackPlugin) ownerCommand(bot *rikka.Bot, service rikka.Service, message rikka.Message, parts []string, args string) bool {
+	switch strings.ToLower(parts[0]) {
+	case "ban", "unban":
+		if len(parts) < 2 {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "feedback.userid"))
+			return true
+		}
+		uID, err := p.banTarget(parts[1])
+		if err != nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+			return true
+		}
+		if strings.ToLower(parts[0]) == "ban" {
+			if err := p.Ban(uID); err != nil {
+				service.SendMessage(message.Channel(), bot.Translate(service, message, "feedback.ban.error", err.Error()))
+				return true
+			}
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "feedback.ban", uID))
+			return true
+		}
+		if err := p.Unban(uID); err != nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "feedback.unban.error", err.Error()))
+			return true
+		}
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "feedback.unban", uID))
+		return true
+	case "close":
+		p.setStatus(bot, service, message, parts, ticketClosed)
+		return true
+	case "reopen":
+		p.setStatus(bot, service, message, parts, ticketOpen)
+		return true
+	case "list":
+		status := ticketOpen
+		if len(parts) > 1 {
+			status = ticketStatus(strings.ToLower(parts[1]))
+		}
+		tickets, err := p.tickets(func(t *ticket) bool {
+			return status == "all" || t.Status == status
+		})
+		if err != nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+			return true
+		}
+		p.list(bot, service, message, tickets)
+		return true
+	}
+	return false
 }
 
 func (p *feedbackPlugin) Message(bot *rikka.Bot, service rikka.Service, message rikka.Message) {
@@ -69,56 +404,87 @@ func (p *feedbackPlugin) Message(bot *rikka.Bot, service rikka.Service, message
 	}
 
 	m, parts := rikka.ParseCommand(service, message)
-	if service.IsBotOwner(message) {
-		if len(parts) > 0 {
-			switch parts[0] {
-			case "ban":
-				if len(parts) > 1 {
-					err := p.Ban(parts[1])
-					if err != nil {
-						service.SendMessage(message.Channel(), "Unable to ban user - "+err.Error())
-					}
-					service.SendMessage(message.Channel(), "Banned user "+parts[1])
+	if len(parts) > 0 {
+		if service.IsBotOwner(message) && p.ownerCommand(bot, service, message, parts, m) {
+			return
+		}
+
+		// The user commands are common words, so they are only commands when they are alone or followed by a ticket ID.
+		// Otherwise they are part of the feedback, eg. "feedback status page is broken".
+		id, ticketID := int64(0), false
+		if len(parts) > 1 {
+			id, ticketID = parseTicketID(parts[1])
+		}
+		command := ""
+		if len(parts) == 1 || ticketID {
+			command = strings.ToLower(parts[0])
+		}
+
+		switch command {
+		case "reply":
+			p.reply(bot, service, message, parts, m)
+			return
+		case "tickets", "status":
+			if ticketID {
+				t, err := p.ticket(id)
+				if err != nil || (t.UserID != message.UserID() && !service.IsBotOwner(message)) {
+					service.SendMessage(message.Channel(), bot.Translate(service, message, "feedback.notfound", id))
 					return
 				}
-				service.SendMessage(message.Channel(), "supply a userid you idiot")
+				p.show(bot, service, message, t)
 				return
-			case "unban":
-				if len(parts) > 1 {
-					err := p.Unban(parts[1])
-					if err != nil {
-						service.SendMessage(message.Channel(), "Unable to unban user - "+err.Error())
-					}
-					service.SendMessage(message.Channel(), "Unbanned user "+parts[1])
-					return
-				}
-				service.SendMessage(message.Channel(), "supply a userid you idiot")
+			}
+			tickets, err := p.tickets(func(t *ticket) bool {
+				return t.UserID == message.UserID()
+			})
+			if err != nil {
+				service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
 				return
-			default:
-				// service.SendMessage(message.Channel(), "tf are u tryna do")
-				// return
 			}
+			p.list(bot, service, message, tickets)
+			return
 		}
 	}
 
-	p.Lock()
-	b, ok := p.Banned[message.UserID()]
-	p.Unlock()
-	if ok {
-		if b {
-			service.SendMessage(message.Channel(), "Sorry, but you are banned from sending feedback because of abuse")
-			return
-		}
+	if p.IsBanned(message.UserID()) {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "feedback.banned"))
+		return
+	}
+
+	if strings.TrimSpace(m) == "" {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "feedback.empty", service.CommandPrefix()))
+		return
 	}
-	service.SendMessage("359902628055875585", fmt.Sprintf("%s (`%s`) left some feedback from guild %s (`%s`)\n```%s```", message.User().Username, message.UserID(), message.GuildName(), message.GuildID(), m))
-	service.SendMessage(message.Channel(), "Feedback left!\nOur support server is here: <https://rikka.xyz>")
+
+	t, err := p.create(message, m)
+	if err != nil {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+		return
+	}
+
+	service.SendMessage(feedbackChannel, fmt.Sprintf("Ticket #%d: %s (`%s`) left some feedback from guild %s (`%s`)\n```%s```", t.ID, message.User().Username, message.UserID(), message.GuildName(), message.GuildID(), m))
+	service.SendMessage(message.Channel(), bot.Translate(service, message, "feedback.sent", t.ID, service.CommandPrefix()))
 }
 
 func (p *feedbackPlugin) Help(bot *rikka.Bot, service rikka.Service, message rikka.Message, detailed bool) []string {
 	if detailed {
-		return nil
+		help := []string{
+			rikka.CommandHelp(service, "feedback", "<constructive criticism>", bot.Translate(service, message, "feedback.help"))[0],
+			rikka.CommandHelp(service, "feedback", "tickets [id]", bot.Translate(service, message, "feedback.help.tickets"))[0],
+			rikka.CommandHelp(service, "feedback", "reply <id> <text>", bot.Translate(service, message, "feedback.help.reply"))[0],
+		}
+		if service.IsBotOwner(message) {
+			help = append(help, []string{
+				rikka.CommandHelp(service, "feedback", "list [open|closed|all]", bot.Translate(service, message, "feedback.help.list"))[0],
+				rikka.CommandHelp(service, "feedback", "close <id>", bot.Translate(service, message, "feedback.help.close"))[0],
+				rikka.CommandHelp(service, "feedback", "reopen <id>", bot.Translate(service, message, "feedback.help.reopen"))[0],
+				rikka.CommandHelp(service, "feedback", "ban <userid|#id>", bot.Translate(service, message, "feedback.help.ban"))[0],
+				rikka.CommandHelp(service, "feedback", "unban <userid|#id>", bot.Translate(service, message, "feedback.help.unban"))[0],
+			}...)
+		}
+		return help
 	}
-	return rikka.CommandHelp(service, "feedback", "<constructive criticism>", "Sends a message to the devs with your thoughts")
+	return rikka.CommandHelp(service, "feedback", "<constructive criticism>", bot.Translate(service, message, "feedback.help"))
 }
 
 func (p *feedbackPlugin) Name() string {
@@ -126,6 +492,8 @@ func (p *feedbackPlugin) Name() string {
 }
 
 func (p *feedbackPlugin) Save() ([]byte, error) {
+	p.Lock()
+	defer p.Unlock()
 	return json.Marshal(p)
 }
 
@@ -133,7 +501,7 @@ func (p *feedbackPlugin) Stats(bot *rikka.Bot, service rikka.Service, message ri
 	return nil
 }
 
-// New creates a new discordavatar plugin.
+// New creates a new feedback plugin.
 func New() rikka.Plugin {
 	return &feedbackPlugin{
 		Banned: make(map[string]bool, 0),
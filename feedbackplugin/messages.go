@@ -0,0 +1,75 @@
+package feedbackplugin
+
+import "github.com/ThyLeader/rikka"
+
+func init() {
+	rikka.RegisterMessages("en", map[string]string{
+		"feedback.help":          "Sends a message to the devs with your thoughts",
+		"feedback.help.tickets":  "Shows your feedback tickets, or one ticket and its replies.",
+		"feedback.help.reply":    "Adds a reply to one of your open tickets.",
+		"feedback.help.list":     "Lists tickets, open tickets by default.",
+		"feedback.help.close":    "Closes a ticket.",
+		"feedback.help.reopen":   "Reopens a ticket.",
+		"feedback.help.ban":      "Bans a user, or the author of a ticket, from sending feedback.",
+		"feedback.help.unban":    "Unbans a user, or the author of a ticket.",
+		"feedback.ban":           "Banned user %s",
+		"feedback.ban.error":     "Unable to ban user - %s",
+		"feedback.unban":         "Unbanned user %s",
+		"feedback.unban.error":   "Unable to unban user - %s",
+		"feedback.userid":        "supply a userid you idiot",
+		"feedback.id":            "Please provide a ticket id.",
+		"feedback.banned":        "Sorry, but you are banned from sending feedback because of abuse",
+		"feedback.empty":         "Please write your feedback after the command, eg. `%sfeedback I love the music commands`",
+		"feedback.sent":          "Feedback left as ticket `#%d`! You can check on it with `%sfeedback tickets`.\nOur support server is here: <https://rikka.xyz>",
+		"feedback.notfound":      "Ticket `#%d` not found.",
+		"feedback.closed":        "Ticket `#%d` is closed.",
+		"feedback.status":        "Ticket `#%d` is now %s.",
+		"feedback.status.open":   "open",
+		"feedback.status.closed": "closed",
+		"feedback.ticket":        "`#%d` **%s**, %s, %s: %s",
+		"feedback.replies.one":   "%d reply",
+		"feedback.replies.other": "%d replies",
+		"feedback.tickets.none":  "There are no tickets.",
+		"feedback.tickets.more":  "...and %d more.",
+		"feedback.reply.line":    "**%s** %s: %s",
+		"feedback.reply.usage":   "Usage: `%sfeedback reply <id> <text>`",
+		"feedback.reply.dm":      "The devs replied to your feedback ticket `#%d`:\n%s",
+		"feedback.reply.error":   "Unable to deliver the reply - %s",
+		"feedback.reply.sent":    "Reply added to ticket `#%d`.",
+	})
+
+	rikka.RegisterMessages("es", map[string]string{
+		"feedback.help":          "Envía un mensaje a los desarrolladores con tu opinión",
+		"feedback.help.tickets":  "Muestra tus tickets de comentarios, o un ticket y sus respuestas.",
+		"feedback.help.reply":    "Añade una respuesta a uno de tus tickets abiertos.",
+		"feedback.help.list":     "Muestra los tickets, los abiertos por defecto.",
+		"feedback.help.close":    "Cierra un ticket.",
+		"feedback.help.reopen":   "Reabre un ticket.",
+		"feedback.help.ban":      "Prohíbe a un usuario, o al autor de un ticket, enviar comentarios.",
+		"feedback.help.unban":    "Levanta la prohibición de un usuario, o del autor de un ticket.",
+		"feedback.ban":           "Usuario %s bloqueado",
+		"feedback.ban.error":     "No se pudo bloquear al usuario - %s",
+		"feedback.unban":         "Usuario %s desbloqueado",
+		"feedback.unban.error":   "No se pudo desbloquear al usuario - %s",
+		"feedback.userid":        "indica un id de usuario, tonto",
+		"feedback.id":            "Por favor indica el id de un ticket.",
+		"feedback.banned":        "Lo siento, tienes prohibido enviar comentarios por abuso",
+		"feedback.empty":         "Por favor escribe tu comentario después del comando, p. ej. `%sfeedback Me encantan los comandos de música`",
+		"feedback.sent":          "¡Comentario enviado como ticket `#%d`! Puedes revisarlo con `%sfeedback tickets`.\nNuestro servidor de soporte está aquí: <https://rikka.xyz>",
+		"feedback.notfound":      "No se encontró el ticket `#%d`.",
+		"feedback.closed":        "El ticket `#%d` está cerrado.",
+		"feedback.status":        "El ticket `#%d` ahora está %s.",
+		"feedback.status.open":   "abierto",
+		"feedback.status.closed": "cerrado",
+		"feedback.ticket":        "`#%d` **%s**, %s, %s: %s",
+		"feedback.replies.one":   "%d respuesta",
+		"feedback.replies.other": "%d respuestas",
+		"feedback.tickets.none":  "No hay tickets.",
+		"feedback.tickets.more":  "...y %d más.",
+		"feedback.reply.line":    "**%s** %s: %s",
+		"feedback.reply.usage":   "Uso: `%sfeedback reply <id> <texto>`",
+		"feedback.reply.dm":      "Los desarrolladores respondieron a tu ticket `#%d`:\n%s",
+		"feedback.reply.error":   "No se pudo entregar la respuesta - %s",
+		"feedback.reply.sent":    "Respuesta añadida al ticket `#%d`.",
+	})
+}
@@ -0,0 +1,318 @@
+package guildlogplugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ThyLeader/rikka"
+	"github.com/bwmarrin/discordgo"
+	"github.com/dustin/go-humanize"
+	"github.com/go-redis/redis"
+)
+
+// The number of joins and leaves that are kept.
+const historySize = 1000
+
+const historyKey = "guildlog"
+
+var client = redis.NewClient(&redis.Options{
+	Addr:     "localhost:6379",
+	Password: "",
+	DB:       0,
+})
+
+type guildEventType string
+
+const (
+	guildJoin  guildEventType = "join"
+	guildLeave guildEventType = "leave"
+)
+
+type guildEvent struct {
+	Type    guildEventType
+	GuildID string
+	Name    string
+	OwnerID string
+	Members int
+	Time    time.Time
+}
+
+type guildLogPlugin struct {
+	sync.Mutex
+
+	bot       *rikka.Bot
+	channelID string
+	// Guild names are remembered, as they are no longer in the state when the bot leaves a guild.
+	names map[string]string
+}
+
+// Name returns the name of the plugin.
+func (p *guildLogPlugin) Name() string {
+	return "GuildLog"
+}
+
+// Load will load plugin state from a byte array.
+func (p *guildLogPlugin) Load(bot *rikka.Bot, service rikka.Service, data []byte) error {
+	if service.Name() != rikka.DiscordServiceName {
+		return errors.New("guild log plugin only supports Discord")
+	}
+	p.bot = bot
+
+	rikka.DiscordService(service).ForEachSession(func(session *discordgo.Session) {
+		// Guilds that were created before the plugin was loaded are already in the state.
+		session.State.RLock()
+		p.Lock()
+		for _, g := range session.State.Guilds {
+			if g.Name != "" {
+				p.names[g.ID] = g.Name
+			}
+		}
+		p.Unlock()
+		session.State.RUnlock()
+
+		session.AddHandler(func(s *discordgo.Session, g *discordgo.GuildCreate) {
+			p.onGuildCreate(service, s, g)
+		})
+		session.AddHandler(func(s *discordgo.Session, g *discordgo.GuildDelete) {
+			p.onGuildDelete(service, g)
+		})
+	})
+	return nil
+}
+
+// Save will save plugin state to a byte array.
+func (p *guildLogPlugin) Save() ([]byte, error) {
+	return nil, nil
+}
+
+func (p *guildLogPlugin) record(e *guildEvent) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if err := client.LPush(historyKey, string(b)).Err(); err != nil {
+		log.Println("Error recording guild event", err)
+		return
+	}
+	client.LTrim(historyKey, 0, historySize-1)
+}
+
+func (p *guildLogPlugin) history(eventType guildEventType, count int) ([]*guildEvent, error) {
+	values, err := client.LRange(historyKey, 0, historySize-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	events := []*guildEvent{}
+	for _, v := range values {
+		e := &guildEvent{}
+		if err := json.Unmarshal([]byte(v), e); err != nil {
+			continue
+		}
+		if eventType != "" && e.Type != eventType {
+			continue
+		}
+		events = append(events, e)
+		if len(events) == count {
+			break
+		}
+	}
+	return events, nil
+}
+
+// translate returns a message in the language of the guild of the log channel.
+func (p *guildLogPlugin) translate(service rikka.Service, key string, args ...interface{}) string {
+	guildID := ""
+	if c, err := rikka.DiscordService(service).Channel(p.channelID); err == nil {
+		guildID = c.GuildID
+	}
+	return p.bot.TranslateGuild(service, guildID, "", key, args...)
+}
+
+func (p *guildLogPlugin) onGuildCreate(service rikka.Service, s *discordgo.Session, g *discordgo.GuildCreate) {
+	if g.Unavailable {
+		return
+	}
+
+	p.Lock()
+	p.names[g.ID] = g.Name
+	p.Unlock()
+
+	// Guilds are also created when connecting, only guilds that were just joined are logged.
+	t, err := g.JoinedAt.Parse()
+	if err != nil {
+		return
+	}
+	if t.Before(time.Now().Add(-1 * time.Minute)) {
+		return
+	}
+
+	p.record(&guildEvent{
+		Type:    guildJoin,
+		GuildID: g.ID,
+		Name:    g.Name,
+		OwnerID: g.OwnerID,
+		Members: g.MemberCount,
+		Time:    time.Now(),
+	})
+
+	if p.channelID == "" {
+		return
+	}
+
+	guildOwner, err := s.State.Member(g.ID, g.OwnerID)
+	if err != nil {
+		service.SendMessage(p.channelID, p.translate(service, "guildlog.noowner"))
+		return
+	}
+	gc, _ := discordgo.Timestamp(guildOwner.JoinedAt).Parse()
+
+	var userCount float32
+	var botCount float32
+	for _, e := range g.Members {
+		if e.User.Bot {
+			botCount++
+		} else {
+			userCount++
+		}
+	}
+	percent := botCount / (userCount + botCount) * 100
+
+	service.SendMessageEmbed(p.channelID, &discordgo.MessageEmbed{
+		Color: rikka.DiscordService(service).UserColor(service.UserID(), p.channelID),
+		Title: p.translate(service, "guildlog.joined", service.UserName()),
+		Fields: []*discordgo.MessageEmbedField{
+			&discordgo.MessageEmbedField{Name: p.translate(service, "guildlog.name"), Value: g.Name, Inline: true},
+			&discordgo.MessageEmbedField{Name: p.translate(service, "guildlog.id"), Value: g.ID, Inline: true},
+			&discordgo.MessageEmbedField{Name: p.translate(service, "guildlog.ownername"), Value: guildOwner.User.Username + "#" + guildOwner.User.Discriminator, Inline: true},
+			&discordgo.MessageEmbedField{Name: p.translate(service, "guildlog.ownerid"), Value: guildOwner.User.ID, Inline: true},
+			&discordgo.MessageEmbedField{Name: p.translate(service, "guildlog.users"), Value: fmt.Sprintf("%v", userCount), Inline: true},
+			&discordgo.MessageEmbedField{Name: p.translate(service, "guildlog.bots"), Value: fmt.Sprintf("%v", botCount), Inline: true},
+			&discordgo.MessageEmbedField{Name: p.translate(service, "guildlog.percent"), Value: fmt.Sprintf("%v", int(percent)) + "%", Inline: true},
+			&discordgo.MessageEmbedField{Name: p.translate(service, "guildlog.created"), Value: humanize.Time(gc), Inline: true},
+		},
+		Thumbnail: &discordgo.MessageEmbedThumbnail{
+			URL: discordgo.EndpointGuildIcon(g.ID, g.Icon),
+		},
+	})
+}
+
+func (p *guildLogPlugin) onGuildDelete(service rikka.Service, g *discordgo.GuildDelete) {
+	// Guilds become unavailable during outages, the bot only left if they are still available.
+	if g.Unavailable {
+		return
+	}
+
+	p.Lock()
+	name := p.names[g.ID]
+	delete(p.names, g.ID)
+	p.Unlock()
+
+	p.record(&guildEvent{
+		Type:    guildLeave,
+		GuildID: g.ID,
+		Name:    name,
+		Time:    time.Now(),
+	})
+
+	if p.channelID == "" {
+		return
+	}
+
+	service.SendMessageEmbed(p.channelID, &discordgo.MessageEmbed{
+		Color: rikka.DiscordService(service).UserColor(service.UserID(), p.channelID),
+		Title: p.translate(service, "guildlog.left", service.UserName()),
+		Fields: []*discordgo.MessageEmbedField{
+			&discordgo.MessageEmbedField{Name: p.translate(service, "guildlog.name"), Value: name, Inline: true},
+			&discordgo.MessageEmbedField{Name: p.translate(service, "guildlog.id"), Value: g.ID, Inline: true},
+		},
+	})
+}
+
+// Help returns a list of help strings that are printed when the user requests them.
+func (p *guildLogPlugin) Help(bot *rikka.Bot, service rikka.Service, message rikka.Message, detailed bool) []string {
+	if detailed || !service.IsBotOwner(message) {
+		return nil
+	}
+
+	return rikka.CommandHelp(service, "guildlog", "[joins|leaves] [count]", bot.Translate(service, message, "guildlog.help"))
+}
+
+// Message handler.
+func (p *guildLogPlugin) Message(bot *rikka.Bot, service rikka.Service, message rikka.Message) {
+	defer rikka.MessageRecover()
+
+	if service.IsMe(message) {
+		return
+	}
+
+	if !rikka.MatchesCommand(service, "guildlog", message) {
+		return
+	}
+
+	if !service.IsBotOwner(message) {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.owner"))
+		return
+	}
+
+	_, parts := rikka.ParseCommand(service, message)
+
+	var eventType guildEventType
+	count := 10
+	for _, part := range parts {
+		switch strings.ToLower(part) {
+		case "joins", "join":
+			eventType = guildJoin
+		case "leaves", "leave":
+			eventType = guildLeave
+		default:
+			if n, err := strconv.Atoi(part); err == nil && n > 0 {
+				count = n
+			}
+		}
+	}
+
+	events, err := p.history(eventType, count)
+	if err != nil {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+		return
+	}
+
+	if len(events) == 0 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "guildlog.empty"))
+		return
+	}
+
+	lines := make([]string, len(events))
+	for i, e := range events {
+		name := e.Name
+		if name == "" {
+			name = bot.Translate(service, message, "guildlog.unknown")
+		}
+		lines[i] = bot.Translate(service, message, "guildlog.event."+string(e.Type), name, e.GuildID, humanize.Time(e.Time))
+		if e.Type == guildJoin {
+			lines[i] += " " + bot.TranslatePlural(service, message, "guildlog.members", e.Members, e.Members)
+		}
+	}
+	service.SendMessage(message.Channel(), strings.Join(lines, "\n"))
+}
+
+// Stats will return the stats for a plugin.
+func (p *guildLogPlugin) Stats(bot *rikka.Bot, service rikka.Service, message rikka.Message) []string {
+	return nil
+}
+
+// New will create a new guild log plugin.
+// Joins and leaves are posted to the provided channel, and recorded so the owner can list them.
+func New(channelID string) rikka.Plugin {
+	return &guildLogPlugin{
+		channelID: channelID,
+		names:     map[string]string{},
+	}
+}
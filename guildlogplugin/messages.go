@@ -0,0 +1,47 @@
+package guildlogplugin
+
+import "github.com/ThyLeader/rikka"
+
+func init() {
+	rikka.RegisterMessages("en", map[string]string{
+		"guildlog.help":          "Lists recent guild joins and leaves.",
+		"guildlog.noowner":       "Unable to retrieve information on the guild owner",
+		"guildlog.joined":        "%s joined a guild",
+		"guildlog.left":          "%s left a guild",
+		"guildlog.name":          "Name",
+		"guildlog.id":            "ID",
+		"guildlog.ownername":     "Owner name",
+		"guildlog.ownerid":       "Owner ID",
+		"guildlog.users":         "Users",
+		"guildlog.bots":          "Bots",
+		"guildlog.percent":       "Percent",
+		"guildlog.created":       "Created",
+		"guildlog.empty":         "No joins or leaves have been recorded.",
+		"guildlog.unknown":       "Unknown guild",
+		"guildlog.event.join":    "**Joined** %s (`%s`) %s",
+		"guildlog.event.leave":   "**Left** %s (`%s`) %s",
+		"guildlog.members.one":   "with %d member",
+		"guildlog.members.other": "with %d members",
+	})
+
+	rikka.RegisterMessages("es", map[string]string{
+		"guildlog.help":          "Muestra los servidores a los que el bot entró o salió recientemente.",
+		"guildlog.noowner":       "No se pudo obtener información del dueño del servidor",
+		"guildlog.joined":        "%s entró a un servidor",
+		"guildlog.left":          "%s salió de un servidor",
+		"guildlog.name":          "Nombre",
+		"guildlog.id":            "ID",
+		"guildlog.ownername":     "Nombre del dueño",
+		"guildlog.ownerid":       "ID del dueño",
+		"guildlog.users":         "Usuarios",
+		"guildlog.bots":          "Bots",
+		"guildlog.percent":       "Porcentaje",
+		"guildlog.created":       "Creado",
+		"guildlog.empty":         "No se ha registrado ninguna entrada ni salida.",
+		"guildlog.unknown":       "Servidor desconocido",
+		"guildlog.event.join":    "**Entró a** %s (`%s`) %s",
+		"guildlog.event.leave":   "**Salió de** %s (`%s`) %s",
+		"guildlog.members.one":   "con %d miembro",
+		"guildlog.members.other": "con %d miembros",
+	})
+}
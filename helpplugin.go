@@ -51,13 +51,13 @@ func (p *helpPlugin) Help(bot *Bot, service Service, message Message, detailed b
 	help := []string{}
 
 	if len(commands) > 0 {
-		help = append(help, CommandHelp(service, "help", "[topic]", fmt.Sprintf("Returns help for a specific topic. Available topics: %s%s%s", ticks, strings.Join(commands, ", "), ticks))[0])
+		help = append(help, CommandHelp(service, "help", "[topic]", bot.Translate(service, message, "help.help", fmt.Sprintf("%s%s%s", ticks, strings.Join(commands, ", "), ticks)))[0])
 	}
 
 	if detailed {
 		help = append(help, []string{
-			CommandHelp(service, "setprivatehelp", "", "Sets help text to be sent through private messages in this channel.")[0],
-			CommandHelp(service, "setpublichelp", "", "Sets the default help behavior for this channel.")[0],
+			CommandHelp(service, "setprivatehelp", "", bot.Translate(service, message, "help.help.setprivatehelp"))[0],
+			CommandHelp(service, "setpublichelp", "", bot.Translate(service, message, "help.help.setpublichelp"))[0],
 		}...)
 	}
 
@@ -87,16 +87,16 @@ func (p *helpPlugin) Message(bot *Bot, service Service, message Message) {
 			if len(parts) == 0 {
 				sort.Strings(help)
 				if service.SupportsPrivateMessages() {
-					help = append([]string{fmt.Sprintf("All commands can be used in private messages without the `%s` prefix.", service.CommandPrefix())}, help...)
+					help = append([]string{bot.Translate(service, message, "help.private", service.CommandPrefix())}, help...)
 				}
 			}
 
 			if len(parts) != 0 && len(help) == 0 {
-				help = []string{fmt.Sprintf("Unknown topic: %s", parts[0])}
+				help = []string{bot.Translate(service, message, "help.unknown", parts[0])}
 			}
 
 			if p.Private[message.Channel()] {
-				service.SendMessage(message.Channel(), "Help has been sent via private message.")
+				service.SendMessage(message.Channel(), bot.Translate(service, message, "help.sent"))
 				if service.SupportsMultiline() {
 					service.PrivateMessage(message.UserID(), strings.Join(help, "\n"))
 				} else {
@@ -124,7 +124,7 @@ func (p *helpPlugin) Message(bot *Bot, service Service, message Message) {
 
 			p.Private[message.Channel()] = true
 
-			service.PrivateMessage(message.UserID(), fmt.Sprintf("Help text in <#%s> will be sent through private messages.", message.Channel()))
+			service.PrivateMessage(message.UserID(), bot.Translate(service, message, "help.setprivate", message.Channel()))
 		} else if MatchesCommand(service, "setpublichelp", message) && service.SupportsPrivateMessages() && !service.IsPrivate(message) {
 			if !service.IsModerator(message) {
 				return
@@ -132,7 +132,7 @@ func (p *helpPlugin) Message(bot *Bot, service Service, message Message) {
 
 			p.Private[message.Channel()] = false
 
-			service.PrivateMessage(message.UserID(), fmt.Sprintf("Help text in <#%s> will be sent publically.", message.Channel()))
+			service.PrivateMessage(message.UserID(), bot.Translate(service, message, "help.setpublic", message.Channel()))
 		}
 	}
 }
@@ -46,7 +46,7 @@ func (i *imagePlugin) Message(bot *rikka.Bot, service rikka.Service, message rik
 			req.Header.Set("Authorization", "Bearer "+i.Key)
 			res, err := i.Client.Do(req)
 			if err != nil {
-				service.SendMessage(message.Channel(), "Error getting the image - "+err.Error())
+				service.SendMessage(message.Channel(), bot.Translate(service, message, "images.error", err.Error()))
 				return
 			}
 			defer res.Body.Close()
@@ -58,7 +58,7 @@ func (i *imagePlugin) Message(bot *rikka.Bot, service rikka.Service, message rik
 			json.Unmarshal(body, &r)
 
 			if r.Status == 404 {
-				service.SendMessage(message.Channel(), "Unable to find the requested image (404)")
+				service.SendMessage(message.Channel(), bot.Translate(service, message, "images.notfound"))
 				return
 			}
 
@@ -67,7 +67,7 @@ func (i *imagePlugin) Message(bot *rikka.Bot, service rikka.Service, message rik
 					URL: r.URL,
 				},
 				Footer: &discordgo.MessageEmbedFooter{
-					Text: "Powered by weeb.sh",
+					Text: bot.Translate(service, message, "images.footer"),
 				},
 			})
 		}
@@ -76,7 +76,7 @@ func (i *imagePlugin) Message(bot *rikka.Bot, service rikka.Service, message rik
 
 func (i *imagePlugin) Help(bot *rikka.Bot, service rikka.Service, message rikka.Message, detailed bool) (help []string) {
 	if detailed {
-		help = append(help, "Available image commands:\n")
+		help = append(help, bot.Translate(service, message, "images.available")+"\n")
 		index := 0
 		tmp := []string{}
 		for _, e := range i.Categories {
@@ -93,7 +93,7 @@ func (i *imagePlugin) Help(bot *rikka.Bot, service rikka.Service, message rikka.
 	}
 
 	help = []string{
-		rikka.CommandHelp(service, "images", "", fmt.Sprintf("Images, see `%shelp images`", service.CommandPrefix()))[0],
+		rikka.CommandHelp(service, "images", "", bot.Translate(service, message, "images.help", service.CommandPrefix()))[0],
 	}
 	return help
 }
@@ -0,0 +1,21 @@
+package imageplugin
+
+import "github.com/ThyLeader/rikka"
+
+func init() {
+	rikka.RegisterMessages("en", map[string]string{
+		"images.help":      "Images, see `%shelp images`",
+		"images.available": "Available image commands:",
+		"images.error":     "Error getting the image - %s",
+		"images.notfound":  "Unable to find the requested image (404)",
+		"images.footer":    "Powered by weeb.sh",
+	})
+
+	rikka.RegisterMessages("es", map[string]string{
+		"images.help":      "Imágenes, mira `%shelp images`",
+		"images.available": "Comandos de imágenes disponibles:",
+		"images.error":     "Error al obtener la imagen - %s",
+		"images.notfound":  "No se encontró la imagen pedida (404)",
+		"images.footer":    "Gracias a weeb.sh",
+	})
+}
@@ -37,7 +37,6 @@ type Message interface {
 	Timestamp() (time.Time, error)
 	Guild() *discordgo.Guild
 	GuildName() string
-	IsExcluded() bool
 }
 
 // ErrAlreadyJoined is an error dispatched on Join if the bot is already joined to the request.
@@ -74,6 +73,7 @@ type Service interface {
 	Member(guildID, userID string) (*discordgo.Member, error)
 	TimestampForID(id string) (time.Time, error)
 	EditMessage(cID, mID, content string) (*discordgo.Message, error)
+	EditMessageEmbed(cID, mID string, embed *discordgo.MessageEmbed) (*discordgo.Message, error)
 	NicknameForID(userID, userName, channelID string) string
 	// MakeCallback(service Service, uID string) chan Message
 	// CloseCallback(service Service, uID string)
@@ -1,8 +1,6 @@
 package inviteplugin
 
 import (
-	"fmt"
-
 	"github.com/ThyLeader/rikka"
 )
 
@@ -10,23 +8,23 @@ import (
 func InviteHelp(bot *rikka.Bot, service rikka.Service, message rikka.Message) (string, string) {
 	switch service.Name() {
 	case rikka.DiscordServiceName:
-		discord := service.(*rikka.Discord)
+		discord := rikka.DiscordService(service)
 
 		if discord.ApplicationClientID != "" {
-			return "", fmt.Sprintf("Returns a URL to add %s to your server.", service.UserName())
+			return "", bot.Translate(service, message, "invite.help", service.UserName())
 		}
-		return "<discordinvite>", "Joins the provided Discord server."
+		return "<discordinvite>", bot.Translate(service, message, "invite.help.join")
 	}
-	return "<channel>", "Joins the provided channel."
+	return "<channel>", bot.Translate(service, message, "invite.help.channel")
 }
 
 // InviteCommand is a command for accepting an invite to a channel.
 func InviteCommand(bot *rikka.Bot, service rikka.Service, message rikka.Message, command string, parts []string) {
 	if service.Name() == rikka.DiscordServiceName {
-		discord := service.(*rikka.Discord)
+		discord := rikka.DiscordService(service)
 
 		if discord.ApplicationClientID != "" {
-			service.SendMessage(message.Channel(), fmt.Sprintf("Please visit https://discordapp.com/oauth2/authorize?client_id=%s&scope=bot to add %s to your server.", discord.ApplicationClientID, service.UserName()))
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "invite.url", discord.ApplicationClientID, service.UserName()))
 			return
 		}
 	}
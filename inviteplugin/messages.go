@@ -0,0 +1,19 @@
+package inviteplugin
+
+import "github.com/ThyLeader/rikka"
+
+func init() {
+	rikka.RegisterMessages("en", map[string]string{
+		"invite.help":         "Returns a URL to add %s to your server.",
+		"invite.help.join":    "Joins the provided Discord server.",
+		"invite.help.channel": "Joins the provided channel.",
+		"invite.url":          "Please visit https://discordapp.com/oauth2/authorize?client_id=%s&scope=bot to add %s to your server.",
+	})
+
+	rikka.RegisterMessages("es", map[string]string{
+		"invite.help":         "Devuelve un enlace para añadir a %s a tu servidor.",
+		"invite.help.join":    "Entra al servidor de Discord indicado.",
+		"invite.help.channel": "Entra al canal indicado.",
+		"invite.url":          "Visita https://discordapp.com/oauth2/authorize?client_id=%s&scope=bot para añadir a %s a tu servidor.",
+	})
+}
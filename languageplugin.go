@@ -0,0 +1,163 @@
+package rikka
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+type languagePlugin struct {
+	sync.RWMutex
+
+	Guilds map[string]string
+	// Users is only read to move user languages from older saves into Redis, where they are shared by every process.
+	Users map[string]string `json:",omitempty"`
+
+	users *userPreferences
+}
+
+// Name returns the name of the plugin.
+func (p *languagePlugin) Name() string {
+	return "Language"
+}
+
+func (p *languagePlugin) language(guildID, userID string) string {
+	p.RLock()
+	defer p.RUnlock()
+
+	if l, ok := p.users.get(userID); ok {
+		return l
+	}
+	if l, ok := p.Guilds[guildID]; ok && guildID != "" {
+		return l
+	}
+	return DefaultLanguage
+}
+
+func (p *languagePlugin) available() string {
+	codes := Languages()
+	available := make([]string, len(codes))
+	for i, code := range codes {
+		available[i] = fmt.Sprintf("`%s` (%s)", code, LanguageName(code))
+	}
+	return strings.Join(available, ", ")
+}
+
+// Help returns a list of help strings that are printed when the user requests them.
+func (p *languagePlugin) Help(bot *Bot, service Service, message Message, detailed bool) []string {
+	help := CommandHelp(service, "language", "[code]", bot.Translate(service, message, "language.help"))
+	if detailed {
+		help = append(help, []string{
+			CommandHelp(service, "language", "me <code>", bot.Translate(service, message, "language.help.me"))[0],
+			CommandHelp(service, "language", "reset", bot.Translate(service, message, "language.help.reset"))[0],
+			CommandHelp(service, "language", "me reset", bot.Translate(service, message, "language.help.mereset"))[0],
+			bot.Translate(service, message, "language.available", p.available()),
+		}...)
+	}
+	return help
+}
+
+func (p *languagePlugin) set(languages map[string]string, id, code string) {
+	p.Lock()
+	defer p.Unlock()
+
+	if code == "" {
+		delete(languages, id)
+		return
+	}
+	languages[id] = code
+}
+
+// Message handler.
+func (p *languagePlugin) Message(bot *Bot, service Service, message Message) {
+	defer MessageRecover()
+
+	if service.IsMe(message) {
+		return
+	}
+
+	if !MatchesCommand(service, "language", message) && !MatchesCommand(service, "lang", message) {
+		return
+	}
+
+	_, parts := ParseCommand(service, message)
+
+	if len(parts) == 0 {
+		code := bot.messageLanguage(service, message)
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "language.current", code, LanguageName(code), p.available()))
+		return
+	}
+
+	user := service.IsPrivate(message)
+	if strings.ToLower(parts[0]) == "me" {
+		user = true
+		parts = parts[1:]
+	}
+
+	if len(parts) != 1 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "language.usage", service.CommandPrefix()))
+		return
+	}
+
+	code := strings.ToLower(parts[0])
+	if code == "reset" {
+		code = ""
+	} else if LanguageName(code) == "" {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "language.unknown", code, p.available()))
+		return
+	}
+
+	if user {
+		if err := p.users.set(service, message.UserID(), code); err != nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+			return
+		}
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "language.set.user", bot.messageLanguage(service, message)))
+		return
+	}
+
+	if !service.IsModerator(message) {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "language.moderator"))
+		return
+	}
+
+	p.set(p.Guilds, message.GuildID(), code)
+	service.SendMessage(message.Channel(), bot.Translate(service, message, "language.set.guild", bot.Language(service, message.GuildID(), "")))
+}
+
+// Load will load plugin state from a byte array.
+func (p *languagePlugin) Load(bot *Bot, service Service, data []byte) error {
+	if data != nil {
+		if err := json.Unmarshal(data, p); err != nil {
+			log.Println("Error loading data", err)
+		}
+	}
+
+	p.Lock()
+	p.users.migrate(p.Users)
+	p.Unlock()
+	p.users.load(service)
+	return nil
+}
+
+// Save will save plugin state to a byte array.
+func (p *languagePlugin) Save() ([]byte, error) {
+	p.RLock()
+	defer p.RUnlock()
+	return json.Marshal(p)
+}
+
+// Stats will return the stats for a plugin.
+func (p *languagePlugin) Stats(bot *Bot, service Service, message Message) []string {
+	return nil
+}
+
+func newLanguagePlugin() *languagePlugin {
+	return &languagePlugin{
+		Guilds: make(map[string]string),
+		Users:  make(map[string]string),
+		users:  newUserPreferences("language:users"),
+	}
+}
@@ -0,0 +1,177 @@
+package rikka
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DefaultLanguage is the language used when none has been selected, and the language missing messages fall back to.
+const DefaultLanguage string = "en"
+
+// PluralFunc is the function signature for plural rules, it returns the plural form (eg. "one" or "other") for a count.
+type PluralFunc func(n int) string
+
+// PluralOneOther is the plural rule for languages that only distinguish between one and everything else.
+func PluralOneOther(n int) string {
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+// PluralOther is the plural rule for languages without plural forms.
+func PluralOther(n int) string {
+	return "other"
+}
+
+type language struct {
+	name     string
+	plural   PluralFunc
+	messages map[string]string
+}
+
+var languagesMutex sync.RWMutex
+var languages = map[string]*language{}
+
+// RegisterLanguage registers a language with the message catalog.
+func RegisterLanguage(code, name string, plural PluralFunc) {
+	languagesMutex.Lock()
+	defer languagesMutex.Unlock()
+
+	l := languages[code]
+	if l == nil {
+		l = &language{messages: map[string]string{}}
+		languages[code] = l
+	}
+	l.name = name
+	l.plural = plural
+}
+
+// RegisterMessages adds messages for a language to the message catalog.
+// Plural messages are registered once per plural form, eg. "reminder.count.one" and "reminder.count.other".
+func RegisterMessages(code string, messages map[string]string) {
+	languagesMutex.Lock()
+	defer languagesMutex.Unlock()
+
+	l := languages[code]
+	if l == nil {
+		l = &language{plural: PluralOneOther, messages: map[string]string{}}
+		languages[code] = l
+	}
+	for k, v := range messages {
+		l.messages[k] = v
+	}
+}
+
+// Languages returns the codes of all registered languages.
+func Languages() []string {
+	languagesMutex.RLock()
+	defer languagesMutex.RUnlock()
+
+	codes := []string{}
+	for code := range languages {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// LanguageName returns the display name of a language, or an empty string if it is not registered.
+func LanguageName(code string) string {
+	languagesMutex.RLock()
+	defer languagesMutex.RUnlock()
+
+	if l := languages[code]; l != nil {
+		return l.name
+	}
+	return ""
+}
+
+func lookupMessage(code, key string) (string, bool) {
+	languagesMutex.RLock()
+	defer languagesMutex.RUnlock()
+
+	if l := languages[code]; l != nil {
+		if m, ok := l.messages[key]; ok {
+			return m, true
+		}
+	}
+	if l := languages[DefaultLanguage]; l != nil {
+		if m, ok := l.messages[key]; ok {
+			return m, true
+		}
+	}
+	return "", false
+}
+
+func pluralForm(code string, n int) string {
+	languagesMutex.RLock()
+	defer languagesMutex.RUnlock()
+
+	if l := languages[code]; l != nil && l.plural != nil {
+		return l.plural(n)
+	}
+	return PluralOneOther(n)
+}
+
+func formatMessage(format string, args []interface{}) string {
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Localize returns the message for a key in a language formatted with args.
+// Messages missing from the language fall back to the default language, and then to the key itself.
+func Localize(code, key string, args ...interface{}) string {
+	if m, ok := lookupMessage(code, key); ok {
+		return formatMessage(m, args)
+	}
+	return key
+}
+
+// LocalizePlural returns the plural form of the message for a key that matches n, formatted with args.
+func LocalizePlural(code, key string, n int, args ...interface{}) string {
+	if m, ok := lookupMessage(code, key+"."+pluralForm(code, n)); ok {
+		return formatMessage(m, args)
+	}
+	if m, ok := lookupMessage(code, key+".other"); ok {
+		return formatMessage(m, args)
+	}
+	return Localize(code, key, args...)
+}
+
+// Language returns the language selected for a user in a guild.
+// A user's own language takes priority over the guild language, which takes priority over the default language.
+func (b *Bot) Language(service Service, guildID, userID string) string {
+	s := b.Services[service.Name()]
+	if s == nil || s.language == nil {
+		return DefaultLanguage
+	}
+	return s.language.language(guildID, userID)
+}
+
+func (b *Bot) messageLanguage(service Service, message Message) string {
+	guildID := ""
+	if !service.IsPrivate(message) {
+		guildID = message.GuildID()
+	}
+	return b.Language(service, guildID, message.UserID())
+}
+
+// Translate returns the message for a key in the language of the sender of a message.
+func (b *Bot) Translate(service Service, message Message, key string, args ...interface{}) string {
+	return Localize(b.messageLanguage(service, message), key, args...)
+}
+
+// TranslatePlural returns the plural message for a key in the language of the sender of a message.
+func (b *Bot) TranslatePlural(service Service, message Message, key string, n int, args ...interface{}) string {
+	return LocalizePlural(b.messageLanguage(service, message), key, n, args...)
+}
+
+// TranslateGuild returns the message for a key in the language of a user in a guild.
+// It is used when there is no message to reply to, either ID may be empty.
+func (b *Bot) TranslateGuild(service Service, guildID, userID, key string, args ...interface{}) string {
+	return Localize(b.Language(service, guildID, userID), key, args...)
+}
@@ -1,8 +1,6 @@
 package mathplugin
 
 import (
-	"fmt"
-
 	"github.com/Knetic/govaluate"
 	"github.com/ThyLeader/rikka"
 )
@@ -14,32 +12,31 @@ func messageFunc(bot *rikka.Bot, service rikka.Service, message rikka.Message) {
 	if !rikka.MatchesCommand(service, "math", message) && !rikka.MatchesCommand(service, "eval", message) {
 		return
 	}
-	defer mathRecover(service, message.Channel())
+	defer mathRecover(bot, service, message)
 	t, _ := rikka.ParseCommand(service, message)
 
 	expression, err := govaluate.NewEvaluableExpression(t)
 	result, err := expression.Evaluate(nil)
 	if err != nil {
-		service.SendMessage(message.Channel(), fmt.Sprintf("There was an error\n%s", err.Error()))
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "math.error", err.Error()))
 		return
 	}
 
-	service.SendMessage(message.Channel(), fmt.Sprintf("**%s**, your expression evaluates to `%v`", message.UserName(), result))
+	service.SendMessage(message.Channel(), bot.Translate(service, message, "math.result", message.UserName(), result))
 }
 
 func helpFunc(bot *rikka.Bot, service rikka.Service, message rikka.Message, detailed bool) []string {
 	if detailed {
 		return []string{
-			"Check out <https://gist.github.com/ThyLeader/d0a98d2c15a824997513a92c911b0fab> for a detailed explanation.",
-			"An edited down version coming soon",
+			bot.Translate(service, message, "math.help.detailed"),
 		}
 	}
-	return rikka.CommandHelp(service, "math/eval", "<expression>", "Evaluates an expression. see `help math` for all possible operators")
+	return rikka.CommandHelp(service, "math/eval", "<expression>", bot.Translate(service, message, "math.help"))
 }
 
-func mathRecover(service rikka.Service, cID string) {
+func mathRecover(bot *rikka.Bot, service rikka.Service, message rikka.Message) {
 	if r := recover(); r != nil {
-		service.SendMessage(cID, "There was an error processing the request")
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "math.recover"))
 	}
 }
 
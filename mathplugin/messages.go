@@ -0,0 +1,21 @@
+package mathplugin
+
+import "github.com/ThyLeader/rikka"
+
+func init() {
+	rikka.RegisterMessages("en", map[string]string{
+		"math.help":          "Evaluates an expression. see `help math` for all possible operators",
+		"math.help.detailed": "Check out <https://gist.github.com/ThyLeader/d0a98d2c15a824997513a92c911b0fab> for a detailed explanation.\nAn edited down version coming soon",
+		"math.error":         "There was an error\n%s",
+		"math.result":        "**%s**, your expression evaluates to `%v`",
+		"math.recover":       "There was an error processing the request",
+	})
+
+	rikka.RegisterMessages("es", map[string]string{
+		"math.help":          "Evalúa una expresión. mira `help math` para ver todos los operadores",
+		"math.help.detailed": "Mira <https://gist.github.com/ThyLeader/d0a98d2c15a824997513a92c911b0fab> para una explicación detallada.\nPronto habrá una versión resumida",
+		"math.error":         "Hubo un error\n%s",
+		"math.result":        "**%s**, tu expresión da `%v`",
+		"math.recover":       "Hubo un error al procesar la petición",
+	})
+}
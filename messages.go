@@ -0,0 +1,134 @@
+package rikka
+
+func init() {
+	RegisterLanguage("en", "English", PluralOneOther)
+	RegisterLanguage("es", "Español", PluralOneOther)
+
+	RegisterMessages("en", map[string]string{
+		"help.help":                   "Returns help for a specific topic. Available topics: %s",
+		"help.help.setprivatehelp":    "Sets help text to be sent through private messages in this channel.",
+		"help.help.setpublichelp":     "Sets the default help behavior for this channel.",
+		"help.private":                "All commands can be used in private messages without the `%s` prefix.",
+		"help.unknown":                "Unknown topic: %s",
+		"help.sent":                   "Help has been sent via private message.",
+		"help.setprivate":             "Help text in <#%s> will be sent through private messages.",
+		"help.setpublic":              "Help text in <#%s> will be sent publically.",
+		"language.help":               "Shows or sets the language of this server.",
+		"language.help.me":            "Sets your own language, this overrides the server language.",
+		"language.help.reset":         "Resets the language of this server to the default.",
+		"language.help.mereset":       "Resets your own language to the server language.",
+		"language.available":          "Available languages: %s",
+		"language.current":            "The current language is `%s` (%s).\nAvailable languages: %s",
+		"language.usage":              "Usage: `%slanguage [me] <code|reset>`",
+		"language.unknown":            "Unknown language `%s`. Available languages: %s",
+		"language.moderator":          "Sorry, only moderators can change the language of this server.",
+		"language.set.user":           "Your language is now `%s`.",
+		"language.set.guild":          "The language of this server is now `%s`.",
+		"timezone.help":               "Shows or sets your time zone, times are shown and reminders are set in it.",
+		"timezone.help.reset":         "Resets your time zone to the server time zone.",
+		"timezone.help.guild":         "Sets the default time zone of this server.",
+		"timezone.examples":           "Time zones are IANA names, eg. `Europe/Madrid`, `America/New_York` or `UTC`.",
+		"timezone.current":            "Your time zone is `%s`, it is %s.",
+		"timezone.usage":              "Usage: `%stimezone [guild] <zone|reset>`",
+		"timezone.unknown":            "Unknown time zone `%s`. Time zones are IANA names, eg. `Europe/Madrid`, `America/New_York` or `UTC`.",
+		"timezone.moderator":          "Sorry, only moderators can change the time zone of this server.",
+		"timezone.set.user":           "Your time zone is now `%s`, it is %s.",
+		"timezone.set.guild":          "The time zone of this server is now `%s`.",
+		"blocklist.help":              "Lists the users blocked from using the bot in this server.",
+		"blocklist.help.add":          "Blocks a user in this server, or only from one plugin, optionally for a duration such as `12h` or `7d`.",
+		"blocklist.help.remove":       "Removes a user's entries, or a single entry, from the blocklist of this server.",
+		"blocklist.help.search":       "Searches the blocklist by user, reason or plugin.",
+		"blocklist.help.global":       "Add `global` to block a user everywhere. Global entries are listed in private messages.",
+		"blocklist.usage.add":         "Usage: `%sblock <@user|id> [plugin:<name>] [duration] [reason]`",
+		"blocklist.usage.remove":      "Usage: `%sunblock <@user|id|#entry>`",
+		"blocklist.usage.search":      "Usage: `%sblocklist search <query>`",
+		"blocklist.unknownplugin":     "Unknown plugin `%s`",
+		"blocklist.added":             "Blocked %s %s (entry `#%d`).",
+		"blocklist.removed.one":       "Removed %d blocklist entry.",
+		"blocklist.removed.other":     "Removed %d blocklist entries.",
+		"blocklist.notfound":          "No blocklist entry found.",
+		"blocklist.empty":             "The blocklist is empty.",
+		"blocklist.noresults":         "No blocklist entries match `%s`.",
+		"blocklist.entry":             "`#%d` %s, %s: %s (added by %s on %s)",
+		"blocklist.expires":           "expires %s",
+		"blocklist.noreason":          "no reason given",
+		"blocklist.scope.global":      "everywhere",
+		"blocklist.scope.guild":       "in server `%s`",
+		"blocklist.scope.plugin":      "from `%s` everywhere",
+		"blocklist.scope.guildplugin": "from `%s` in server `%s`",
+		"error.owner":                 "Sorry, you must be the owner to use this command",
+		"error.moderator":             "Sorry, you must be a moderator to use this command",
+		"error.generic":               "There was an error! %s",
+		"error.private":               "Sorry, this command doesn't work in private chat.",
+		"error.mentions.one":          "Please only mention one user",
+		"error.search.one":            "Please only search for one user at a time",
+		"menu.exists":                 "A menu already exists",
+		"menu.exit":                   "Exiting menu",
+		"menu.timeout":                "Menu timed out",
+		"menu.invalid":                "BAKA!! Seems you cant type a correct response. Exiting menu",
+	})
+
+	RegisterMessages("es", map[string]string{
+		"help.help":                   "Muestra la ayuda de un tema. Temas disponibles: %s",
+		"help.help.setprivatehelp":    "La ayuda en este canal se enviará por mensaje privado.",
+		"help.help.setpublichelp":     "Restablece el comportamiento de la ayuda en este canal.",
+		"help.private":                "Todos los comandos se pueden usar en mensajes privados sin el prefijo `%s`.",
+		"help.unknown":                "Tema desconocido: %s",
+		"help.sent":                   "La ayuda se ha enviado por mensaje privado.",
+		"help.setprivate":             "La ayuda en <#%s> se enviará por mensaje privado.",
+		"help.setpublic":              "La ayuda en <#%s> se enviará públicamente.",
+		"language.help":               "Muestra o cambia el idioma de este servidor.",
+		"language.help.me":            "Cambia tu propio idioma, este tiene prioridad sobre el del servidor.",
+		"language.help.reset":         "Restablece el idioma de este servidor.",
+		"language.help.mereset":       "Restablece tu idioma al del servidor.",
+		"language.available":          "Idiomas disponibles: %s",
+		"language.current":            "El idioma actual es `%s` (%s).\nIdiomas disponibles: %s",
+		"language.usage":              "Uso: `%slanguage [me] <código|reset>`",
+		"language.unknown":            "Idioma desconocido `%s`. Idiomas disponibles: %s",
+		"language.moderator":          "Lo siento, solo los moderadores pueden cambiar el idioma de este servidor.",
+		"language.set.user":           "Tu idioma ahora es `%s`.",
+		"language.set.guild":          "El idioma de este servidor ahora es `%s`.",
+		"timezone.help":               "Muestra o cambia tu zona horaria, en ella se muestran las horas y se crean los recordatorios.",
+		"timezone.help.reset":         "Restablece tu zona horaria a la del servidor.",
+		"timezone.help.guild":         "Cambia la zona horaria por defecto de este servidor.",
+		"timezone.examples":           "Las zonas horarias son nombres IANA, p. ej. `Europe/Madrid`, `America/New_York` o `UTC`.",
+		"timezone.current":            "Tu zona horaria es `%s`, son las %s.",
+		"timezone.usage":              "Uso: `%stimezone [guild] <zona|reset>`",
+		"timezone.unknown":            "Zona horaria desconocida `%s`. Las zonas horarias son nombres IANA, p. ej. `Europe/Madrid`, `America/New_York` o `UTC`.",
+		"timezone.moderator":          "Lo siento, solo los moderadores pueden cambiar la zona horaria de este servidor.",
+		"timezone.set.user":           "Tu zona horaria ahora es `%s`, son las %s.",
+		"timezone.set.guild":          "La zona horaria de este servidor ahora es `%s`.",
+		"blocklist.help":              "Muestra los usuarios bloqueados en este servidor.",
+		"blocklist.help.add":          "Bloquea a un usuario en este servidor, o solo en un plugin, opcionalmente durante un tiempo como `12h` o `7d`.",
+		"blocklist.help.remove":       "Elimina las entradas de un usuario, o una sola entrada, de la lista de bloqueo de este servidor.",
+		"blocklist.help.search":       "Busca en la lista de bloqueo por usuario, motivo o plugin.",
+		"blocklist.help.global":       "Añade `global` para bloquear a un usuario en todas partes. Las entradas globales se muestran en mensajes privados.",
+		"blocklist.usage.add":         "Uso: `%sblock <@usuario|id> [plugin:<nombre>] [duración] [motivo]`",
+		"blocklist.usage.remove":      "Uso: `%sunblock <@usuario|id|#entrada>`",
+		"blocklist.usage.search":      "Uso: `%sblocklist search <búsqueda>`",
+		"blocklist.unknownplugin":     "Plugin desconocido `%s`",
+		"blocklist.added":             "%s bloqueado %s (entrada `#%d`).",
+		"blocklist.removed.one":       "Se eliminó %d entrada de la lista de bloqueo.",
+		"blocklist.removed.other":     "Se eliminaron %d entradas de la lista de bloqueo.",
+		"blocklist.notfound":          "No se encontró ninguna entrada.",
+		"blocklist.empty":             "La lista de bloqueo está vacía.",
+		"blocklist.noresults":         "Ninguna entrada coincide con `%s`.",
+		"blocklist.entry":             "`#%d` %s, %s: %s (añadido por %s el %s)",
+		"blocklist.expires":           "expira el %s",
+		"blocklist.noreason":          "sin motivo",
+		"blocklist.scope.global":      "en todas partes",
+		"blocklist.scope.guild":       "en el servidor `%s`",
+		"blocklist.scope.plugin":      "de `%s` en todas partes",
+		"blocklist.scope.guildplugin": "de `%s` en el servidor `%s`",
+		"error.owner":                 "Lo siento, debes ser el dueño para usar este comando",
+		"error.moderator":             "Lo siento, debes ser moderador para usar este comando",
+		"error.generic":               "¡Hubo un error! %s",
+		"error.private":               "Lo siento, este comando no funciona en mensajes privados.",
+		"error.mentions.one":          "Por favor menciona solo a un usuario",
+		"error.search.one":            "Por favor busca solo a un usuario a la vez",
+		"menu.exists":                 "Ya existe un menú",
+		"menu.exit":                   "Saliendo del menú",
+		"menu.timeout":                "El menú ha expirado",
+		"menu.invalid":                "¡¡BAKA!! Parece que no sabes escribir una respuesta correcta. Saliendo del menú",
+	})
+}
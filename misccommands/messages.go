@@ -0,0 +1,25 @@
+package misccommands
+
+import "github.com/ThyLeader/rikka"
+
+func init() {
+	rikka.RegisterMessages("en", map[string]string{
+		"misc.help.pepe":    "Sends a pepe.",
+		"misc.help.ts":      "Parses a snowflake (id) and returns a timestamp.",
+		"misc.help.support": "Gives an invite link to join the support server.",
+		"misc.help.ping":    "Shows bot latency.",
+		"misc.help.lenny":   "Sends a random lenny",
+		"misc.ts.invalid":   "Incorrect snowflake",
+		"misc.support":      "You can join the support server here: https://rikka.xyz",
+	})
+
+	rikka.RegisterMessages("es", map[string]string{
+		"misc.help.pepe":    "Envía un pepe.",
+		"misc.help.ts":      "Lee un snowflake (id) y devuelve su fecha.",
+		"misc.help.support": "Da un enlace de invitación al servidor de soporte.",
+		"misc.help.ping":    "Muestra la latencia del bot.",
+		"misc.help.lenny":   "Envía un lenny al azar",
+		"misc.ts.invalid":   "Snowflake incorrecto",
+		"misc.support":      "Puedes unirte al servidor de soporte aquí: https://rikka.xyz",
+	})
+}
@@ -9,7 +9,6 @@ import (
 	"time"
 
 	"github.com/ThyLeader/rikka"
-	"github.com/go-redis/redis"
 )
 
 var pepe = `:frog::frog::frog::frog::frog::frog::frog:
@@ -29,12 +28,6 @@ __:frog::frog::frog::frog::frog::frog::frog::frog::frog::frog::frog:
 :frog::frog::frog::frog::frog::frog::frog::frog::frog::frog:
 :frog::frog::frog::frog::frog::frog::frog::frog::frog:`
 
-var client = redis.NewClient(&redis.Options{
-	Addr:     "localhost:6379",
-	Password: "",
-	DB:       0,
-})
-
 func MessagePeepo(bot *rikka.Bot, service rikka.Service, message rikka.Message, command string, parts []string) {
 	if service.IsMe(message) {
 		return
@@ -46,7 +39,7 @@ func MessagePeepo(bot *rikka.Bot, service rikka.Service, message rikka.Message,
 	service.SendMessage(message.Channel(), pepe)
 }
 
-var HelpPeepo = rikka.NewCommandHelp("", "Sends a pepe.")
+var HelpPeepo = rikka.NewCommandHelp("", "misc.help.pepe")
 
 var userIDRegex = regexp.MustCompile("<@!?([0-9]*)>")
 var chanIDRegex = regexp.MustCompile("<#!?([0-9]*)>")
@@ -77,22 +70,22 @@ func MessageIDTS(bot *rikka.Bot, service rikka.Service, message rikka.Message, c
 	}
 	t, err := service.TimestampForID(id)
 	if err != nil {
-		service.SendMessage(message.Channel(), "Incorrect snowflake")
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "misc.ts.invalid"))
 		return
 	}
-	service.SendMessage(message.Channel(), fmt.Sprintf("`%s`", t.UTC().Format(time.UnixDate)))
+	service.SendMessage(message.Channel(), fmt.Sprintf("`%s`", t.In(bot.MessageLocation(service, message)).Format(time.UnixDate)))
 }
 
 // HelpIDTS is the help function for timestamp parsing
-var HelpIDTS = rikka.NewCommandHelp("[@username]", "Parses a snowflake (id) and returns a timestamp.")
+var HelpIDTS = rikka.NewCommandHelp("[@username]", "misc.help.ts")
 
 // MessageSupport is the message handler for support
 func MessageSupport(bot *rikka.Bot, service rikka.Service, message rikka.Message, command string, parts []string) {
-	service.SendMessage(message.Channel(), "You can join the support server here: https://rikka.xyz")
+	service.SendMessage(message.Channel(), bot.Translate(service, message, "misc.support"))
 }
 
 // HelpSupport is the help function for support
-var HelpSupport = rikka.NewCommandHelp("", "Gives an invite link to join the support server.")
+var HelpSupport = rikka.NewCommandHelp("", "misc.help.support")
 
 // MessagePing is the command handler for the ping command
 func MessagePing(bot *rikka.Bot, service rikka.Service, message rikka.Message, command string, parts []string) {
@@ -104,43 +97,7 @@ func MessagePing(bot *rikka.Bot, service rikka.Service, message rikka.Message, c
 }
 
 // HelpPing is the help text for the ping command
-var HelpPing = rikka.NewCommandHelp("", "Shows bot latency.")
-
-// MessageExclude excludes people from using the bot
-func MessageExclude(bot *rikka.Bot, service rikka.Service, message rikka.Message, command string, parts []string) {
-	if !service.IsBotOwner(message) {
-		service.SendMessage(message.Channel(), "Sorry, you must be the owner to use this command")
-		return
-	}
-	if len(parts) != 1 {
-		service.SendMessage(message.Channel(), "userid not provided")
-		return
-	}
-	err := client.SAdd("exclude", parts[0]).Err()
-	if err != nil {
-		service.SendMessage(message.Channel(), err.Error())
-		return
-	}
-	service.SendMessage(message.Channel(), fmt.Sprintf("Successfully excluded user `%s`", parts[0]))
-}
-
-// MessageUnexclude excludes people from using the bot
-func MessageUnexclude(bot *rikka.Bot, service rikka.Service, message rikka.Message, command string, parts []string) {
-	if !service.IsBotOwner(message) {
-		service.SendMessage(message.Channel(), "Sorry, you must be the owner to use this command")
-		return
-	}
-	if len(parts) != 1 {
-		service.SendMessage(message.Channel(), "userid not provided")
-		return
-	}
-	err := client.SRem("exclude", parts[0]).Err()
-	if err != nil {
-		service.SendMessage(message.Channel(), err.Error())
-		return
-	}
-	service.SendMessage(message.Channel(), fmt.Sprintf("Successfully unexcluded user `%s`", parts[0]))
-}
+var HelpPing = rikka.NewCommandHelp("", "misc.help.ping")
 
 // MessageLenny is the handler for the lenny command
 func MessageLenny(bot *rikka.Bot, service rikka.Service, message rikka.Message, command string, parts []string) {
@@ -149,7 +106,7 @@ func MessageLenny(bot *rikka.Bot, service rikka.Service, message rikka.Message,
 }
 
 // HelpLenny is the help text for the lenny command
-var HelpLenny = rikka.NewCommandHelp("", "Sends a random lenny")
+var HelpLenny = rikka.NewCommandHelp("", "misc.help.lenny")
 
 var lenny = []string{
 	"( ͡° ͜ʖ ͡°)",
@@ -0,0 +1,216 @@
+package musicplugin
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ThyLeader/rikka"
+)
+
+var roleMentionRegexp = regexp.MustCompile(`^<@&([0-9]+)>$`)
+
+// The default percentage of listeners that have to vote to skip a song.
+const defaultSkipVotes = 50
+
+// djCommands are the commands that need the DJ role, when a guild has one.
+// Others can vote to skip songs instead.
+var djCommands = map[string]bool{
+	"leave":        true,
+	"stop":         true,
+	"clear":        true,
+	"skipto":       true,
+	"remove":       true,
+	"rm":           true,
+	"move":         true,
+	"mv":           true,
+	"shuffle":      true,
+	"dedupe":       true,
+	"removeabsent": true,
+	"seek":         true,
+	"forward":      true,
+	"ff":           true,
+	"rewind":       true,
+	"rw":           true,
+	"volume":       true,
+	"vol":          true,
+	"bitrate":      true,
+	"filter":       true,
+	"filters":      true,
+	"fx":           true,
+	"loop":         true,
+	"l":            true,
+	"repeat":       true,
+	"r":            true,
+}
+
+// djSettings are the DJ commands that show a setting when they are used without a value, which everyone can do.
+var djSettings = map[string]bool{
+	"volume":  true,
+	"vol":     true,
+	"bitrate": true,
+	"filter":  true,
+	"filters": true,
+	"fx":      true,
+}
+
+// isDJ returns whether the sender of a message can use the DJ commands in a guild.
+// Everyone is a DJ in guilds without a DJ role, and moderators always are.
+func (p *MusicPlugin) isDJ(service rikka.Service, message rikka.Message, guildID string) bool {
+	roleID := p.settings(guildID).DJRole
+	if roleID == "" || service.IsModerator(message) {
+		return true
+	}
+
+	m, err := service.Member(guildID, message.UserID())
+	if err != nil {
+		return false
+	}
+	for _, r := range m.Roles {
+		if r == roleID {
+			return true
+		}
+	}
+	return false
+}
+
+// roleName returns the name of a role in a guild, or its ID if it can't be found.
+func (p *MusicPlugin) roleName(guildID, roleID string) string {
+	g, err := p.discord.Guild(guildID)
+	if err != nil {
+		return roleID
+	}
+	for _, r := range g.Roles {
+		if r.ID == roleID {
+			return r.Name
+		}
+	}
+	return roleID
+}
+
+// findRole returns the ID of a role in a guild from a mention, ID or name.
+func (p *MusicPlugin) findRole(guildID, role string) (string, bool) {
+	if m := roleMentionRegexp.FindStringSubmatch(role); m != nil {
+		role = m[1]
+	}
+	g, err := p.discord.Guild(guildID)
+	if err != nil {
+		return "", false
+	}
+	for _, r := range g.Roles {
+		if r.ID == role || strings.EqualFold(r.Name, role) {
+			return r.ID, true
+		}
+	}
+	return "", false
+}
+
+// djCommand shows or sets the DJ role of a guild, and the percentage of listeners that have to vote to skip a song.
+func (p *MusicPlugin) djCommand(bot *rikka.Bot, service rikka.Service, message rikka.Message, guildID string, parts []string) {
+	command := parts[0]
+	current := p.settings(guildID)
+
+	if len(parts) == 1 {
+		switch command {
+		case "dj":
+			if current.DJRole == "" {
+				service.SendMessage(message.Channel(), bot.Translate(service, message, "music.dj.none"))
+				return
+			}
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.dj", p.roleName(guildID, current.DJRole)))
+		case "skipvotes":
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.skipvotes", current.SkipVotes))
+		}
+		return
+	}
+
+	if !service.IsModerator(message) {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.moderator"))
+		return
+	}
+
+	switch command {
+	case "dj":
+		arg := strings.Join(parts[1:], " ")
+		switch strings.ToLower(arg) {
+		case "off", "none", "reset", "clear":
+			p.updateSettings(guildID, func(s *guildSettings) {
+				s.DJRole = ""
+			})
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.dj.off"))
+			return
+		}
+		roleID, ok := p.findRole(guildID, arg)
+		if !ok {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.dj.notfound", arg))
+			return
+		}
+		p.updateSettings(guildID, func(s *guildSettings) {
+			s.DJRole = roleID
+		})
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.dj.set", p.roleName(guildID, roleID)))
+
+	case "skipvotes":
+		v, err := strconv.Atoi(strings.TrimSuffix(parts[1], "%"))
+		if err != nil || v < 1 || v > 100 {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.skipvotes.invalid"))
+			return
+		}
+		p.updateSettings(guildID, func(s *guildSettings) {
+			s.SkipVotes = v
+		})
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.skipvotes.set", v))
+	}
+}
+
+// skip skips the song that is playing for DJs and the user that added it, others vote to skip it.
+func (p *MusicPlugin) skip(bot *rikka.Bot, vc *voiceConnection, service rikka.Service, message rikka.Message) {
+	vc.Lock()
+	playing := vc.Playing != nil && vc.control != nil
+	addedBy := ""
+	if vc.Playing != nil {
+		addedBy = vc.Playing.AddedByID
+	}
+	vc.Unlock()
+	if !playing {
+		return
+	}
+
+	if addedBy == message.UserID() || p.isDJ(service, message, vc.GuildID) {
+		vc.send(Skip)
+		return
+	}
+
+	listeners := p.listeners(vc)
+	delete(listeners, service.UserID())
+	if !listeners[message.UserID()] {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.skip.listening"))
+		return
+	}
+
+	vc.Lock()
+	if vc.votes == nil {
+		vc.votes = map[string]bool{}
+	}
+	vc.votes[message.UserID()] = true
+	// Votes of users that left the channel don't count.
+	votes := 0
+	for userID := range vc.votes {
+		if listeners[userID] {
+			votes++
+		}
+	}
+	vc.Unlock()
+
+	// The number of votes needed is rounded up, and is at least 1.
+	needed := (len(listeners)*p.settings(vc.GuildID).SkipVotes + 99) / 100
+	if needed < 1 {
+		needed = 1
+	}
+	if votes < needed {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.skip.vote", votes, needed))
+		return
+	}
+	service.SendMessage(message.Channel(), bot.Translate(service, message, "music.skip.voted", votes, needed))
+	vc.send(Skip)
+}
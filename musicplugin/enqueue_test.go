@@ -0,0 +1,115 @@
+package musicplugin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ThyLeader/rikka"
+	"github.com/bwmarrin/discordgo"
+)
+
+// testService records the messages the plugin sends, other service methods aren't used by the queue.
+type testService struct {
+	rikka.Service
+	sent []string
+}
+
+func (s *testService) Name() string {
+	return rikka.DiscordServiceName
+}
+
+func (s *testService) SendMessage(channel, message string) (*discordgo.Message, error) {
+	s.sent = append(s.sent, message)
+	return &discordgo.Message{ChannelID: channel, Content: message}, nil
+}
+
+func (s *testService) Typing(channel string) error {
+	return nil
+}
+
+func (s *testService) IsPrivate(message rikka.Message) bool {
+	return false
+}
+
+// baseMessage names the embedded message, so it doesn't clash with its Message method.
+type baseMessage = rikka.Message
+
+// testMessage is a message sent by a user in a guild.
+type testMessage struct {
+	baseMessage
+	userID string
+}
+
+func (m *testMessage) Channel() string {
+	return "channel"
+}
+
+func (m *testMessage) GuildID() string {
+	return "guild"
+}
+
+func (m *testMessage) UserID() string {
+	return m.userID
+}
+
+func (m *testMessage) UserName() string {
+	return "user " + m.userID
+}
+
+func TestEnqueue(t *testing.T) {
+	fake := &FakeResolver{Duration: time.Minute}
+	p := New(nil, Config{Resolver: fake}).(*MusicPlugin)
+	p.Settings["guild"] = &guildSettings{MaxQueue: 3, MaxDuration: 120, MaxShare: 100, MaxPlaylist: 10}
+	bot := rikka.NewBot()
+	service := &testService{}
+	vc := &voiceConnection{GuildID: "guild"}
+
+	for _, query := range []string{"a", "b", "c", "d"} {
+		if err := p.enqueue(bot, vc, query, service, &testMessage{userID: "1"}, false); err != nil {
+			t.Fatalf("enqueue(%q) returned %v", query, err)
+		}
+	}
+
+	if len(vc.Queue) != 3 {
+		t.Fatalf("the queue has %d songs, want 3", len(vc.Queue))
+	}
+	for i, s := range vc.Queue {
+		if s.Title != []string{"a", "b", "c"}[i] || s.Source != "fake" || s.AddedByID != "1" || s.TextChannelID != "channel" {
+			t.Errorf("song %d is %+v", i, s)
+		}
+	}
+	if len(service.sent) != 4 {
+		t.Fatalf("sent %q, want 3 songs added and the queue full", service.sent)
+	}
+	if want := p.limitMessage(bot, service, &testMessage{userID: "1"}, p.settings("guild"), "music.limit.queue"); service.sent[3] != want {
+		t.Errorf("the last message is %q, want %q", service.sent[3], want)
+	}
+
+	// The queue plays the songs in order, the resolver of their source opens them.
+	r, err := p.resolver(vc.Queue[0].Source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	audio, err := r.Open(vc.Queue[0])
+	if err != nil || audio.Reader == nil {
+		t.Errorf("Open of the first song = %v, %v", audio, err)
+	}
+}
+
+func TestEnqueueTooLong(t *testing.T) {
+	fake := &FakeResolver{Duration: 10 * time.Minute}
+	p := New(nil, Config{Resolver: fake}).(*MusicPlugin)
+	p.Settings["guild"] = &guildSettings{MaxDuration: 60}
+	service := &testService{}
+	vc := &voiceConnection{GuildID: "guild"}
+
+	if err := p.enqueue(rikka.NewBot(), vc, "long", service, &testMessage{userID: "1"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(vc.Queue) != 0 {
+		t.Errorf("a song longer than the limit was queued")
+	}
+	if len(service.sent) != 1 {
+		t.Errorf("sent %q, want the song is too long", service.sent)
+	}
+}
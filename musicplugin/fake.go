@@ -0,0 +1,128 @@
+package musicplugin
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"time"
+)
+
+// The format of the audio a FakeResolver generates.
+const (
+	fakeSampleRate = 48000
+	fakeChannels   = 2
+	// Samples are 16 bit.
+	fakeSampleSize = 2
+)
+
+// FakeResolver finds a song for anything and plays a tone for it, so the queue and player work without
+// youtube-dl or a network connection.
+type FakeResolver struct {
+	// Duration is how long songs are, 30 seconds when it is 0.
+	Duration time.Duration
+}
+
+func (r *FakeResolver) duration() time.Duration {
+	if r.Duration <= 0 {
+		return 30 * time.Second
+	}
+	return r.Duration
+}
+
+func (r *FakeResolver) Source() string {
+	return "fake"
+}
+
+func (r *FakeResolver) Resolve(query string, search bool) ([]song, error) {
+	if query == "" {
+		return nil, ErrNoResults
+	}
+
+	titles := []string{query}
+	if search {
+		titles = titles[:0]
+		for i := 1; i <= searchResults; i++ {
+			titles = append(titles, fmt.Sprintf("%s %d", query, i))
+		}
+	}
+
+	songs := []song{}
+	for _, t := range titles {
+		songs = append(songs, song{
+			ID:       t,
+			Title:    t,
+			URL:      t,
+			Duration: int(r.duration().Seconds()),
+			Source:   r.Source(),
+		})
+	}
+	return songs, nil
+}
+
+// Open returns a WAV file with a tone, its pitch depends on the song.
+func (r *FakeResolver) Open(s song) (*Audio, error) {
+	h := fnv.New32a()
+	h.Write([]byte(s.URL))
+
+	samples := int(r.duration().Seconds() * fakeSampleRate)
+	return &Audio{
+		Reader: io.MultiReader(
+			wavHeader(samples),
+			&toneReader{
+				frequency: 220 + float64(h.Sum32()%440),
+				samples:   samples,
+			},
+		),
+	}, nil
+}
+
+// wavHeader returns the header of a WAV file with a number of samples in the fake format.
+func wavHeader(samples int) io.Reader {
+	size := uint32(samples * fakeChannels * fakeSampleSize)
+
+	header := make([]byte, 44)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], 36+size)
+	copy(header[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(header[16:], 16)
+	// PCM
+	binary.LittleEndian.PutUint16(header[20:], 1)
+	binary.LittleEndian.PutUint16(header[22:], fakeChannels)
+	binary.LittleEndian.PutUint32(header[24:], fakeSampleRate)
+	binary.LittleEndian.PutUint32(header[28:], fakeSampleRate*fakeChannels*fakeSampleSize)
+	binary.LittleEndian.PutUint16(header[32:], fakeChannels*fakeSampleSize)
+	binary.LittleEndian.PutUint16(header[34:], fakeSampleSize*8)
+	copy(header[36:], "data")
+	binary.LittleEndian.PutUint32(header[40:], size)
+	return bytes.NewReader(header)
+}
+
+// toneReader generates the PCM samples of a sine wave.
+type toneReader struct {
+	frequency float64
+	samples   int
+	i         int
+}
+
+func (r *toneReader) Read(p []byte) (int, error) {
+	frame := fakeChannels * fakeSampleSize
+	if r.i >= r.samples {
+		return 0, io.EOF
+	}
+	// A buffer that can't hold a frame would never be filled.
+	if len(p) < frame {
+		return 0, io.ErrShortBuffer
+	}
+	n := 0
+	for ; n+frame <= len(p) && r.i < r.samples; r.i++ {
+		v := int16(math.Sin(2*math.Pi*r.frequency*float64(r.i)/fakeSampleRate) * math.MaxInt16 / 4)
+		for c := 0; c < fakeChannels; c++ {
+			binary.LittleEndian.PutUint16(p[n:], uint16(v))
+			n += fakeSampleSize
+		}
+	}
+	return n, nil
+}
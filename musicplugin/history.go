@@ -0,0 +1,266 @@
+package musicplugin
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ThyLeader/rikka"
+	"github.com/dustin/go-humanize"
+)
+
+// The most songs kept in the history of a guild.
+const maxHistory = 200
+
+// The most tracks and requesters counted in the stats of a guild. When there are more, only the most played half is kept,
+// so tracks that were just played have time to be played again before the next prune. The totals still count everything.
+const maxPlayCounts = 1000
+
+// The number of songs shown on a page of the history, and the number of top tracks and requesters.
+const (
+	historyPageSize = 10
+	topSize         = 10
+)
+
+// A historyEntry is a song that was played in a guild.
+type historyEntry struct {
+	Song song
+	// Time is when the song started playing.
+	Time time.Time
+	// Played is how long the song played, it is less than its duration when it was skipped or moved in.
+	Played  time.Duration
+	Skipped bool
+}
+
+// listeningStats are the totals of everything played in a guild.
+type listeningStats struct {
+	Plays int
+	Time  time.Duration
+	// Tracks are by source and URL, Requesters by user ID.
+	Tracks     map[string]*playCount
+	Requesters map[string]*playCount
+}
+
+// A playCount is how often and how long a track or requester was played.
+type playCount struct {
+	Name  string
+	Plays int
+	Time  time.Duration
+	// key is the key of the count while it is pruned.
+	key string
+}
+
+func (c *playCount) add(name string, played time.Duration) {
+	c.Name = name
+	c.Plays++
+	c.Time += played
+}
+
+// record adds a song that was played to the history and stats of a guild.
+func (p *MusicPlugin) record(guildID string, s song, started time.Time, played time.Duration, skipped bool) {
+	// Only what is needed to show and replay the song is kept.
+	s.TextChannelID = ""
+	s.Description = ""
+	s.Remaining = 0
+	s.Position = 0
+
+	p.Lock()
+	defer p.Unlock()
+
+	history := append(p.History[guildID], historyEntry{
+		Song:    s,
+		Time:    started,
+		Played:  played,
+		Skipped: skipped,
+	})
+	if len(history) > maxHistory {
+		history = history[len(history)-maxHistory:]
+	}
+	p.History[guildID] = history
+
+	stats := p.ListeningStats[guildID]
+	if stats == nil {
+		stats = &listeningStats{
+			Tracks:     map[string]*playCount{},
+			Requesters: map[string]*playCount{},
+		}
+		p.ListeningStats[guildID] = stats
+	}
+	stats.Plays++
+	stats.Time += played
+
+	track := s.Source + s.URL
+	if stats.Tracks[track] == nil {
+		stats.Tracks[track] = &playCount{}
+	}
+	stats.Tracks[track].add(s.Title, played)
+
+	if s.AddedByID != "" {
+		if stats.Requesters[s.AddedByID] == nil {
+			stats.Requesters[s.AddedByID] = &playCount{}
+		}
+		stats.Requesters[s.AddedByID].add(s.AddedBy, played)
+	}
+
+	prune(stats.Tracks)
+	prune(stats.Requesters)
+}
+
+// prune removes the least played counts when there are more than maxPlayCounts.
+func prune(counts map[string]*playCount) {
+	if len(counts) <= maxPlayCounts {
+		return
+	}
+	list := []playCount{}
+	for key, c := range counts {
+		c := *c
+		c.key = key
+		list = append(list, c)
+	}
+	sort.Sort(byPlays(list))
+	for _, c := range list[maxPlayCounts/2:] {
+		delete(counts, c.key)
+	}
+}
+
+// history returns the history of a guild, the most recent song first.
+func (p *MusicPlugin) history(guildID string) []historyEntry {
+	p.Lock()
+	defer p.Unlock()
+
+	history := p.History[guildID]
+	recent := make([]historyEntry, len(history))
+	for i, e := range history {
+		recent[len(history)-1-i] = e
+	}
+	return recent
+}
+
+type byPlays []playCount
+
+func (a byPlays) Len() int      { return len(a) }
+func (a byPlays) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a byPlays) Less(i, j int) bool {
+	if a[i].Plays != a[j].Plays {
+		return a[i].Plays > a[j].Plays
+	}
+	return a[i].Time > a[j].Time
+}
+
+// top returns the counts that were played most, by plays and then time.
+func top(counts map[string]*playCount) []playCount {
+	list := []playCount{}
+	for _, c := range counts {
+		list = append(list, *c)
+	}
+	sort.Sort(byPlays(list))
+	if len(list) > topSize {
+		list = list[:topSize]
+	}
+	return list
+}
+
+// historyCommand handles the history, replay and top commands.
+func (p *MusicPlugin) historyCommand(bot *rikka.Bot, vc *voiceConnection, service rikka.Service, message rikka.Message, guildID string, parts []string) {
+	switch parts[0] {
+	case "history", "recent":
+		history := p.history(guildID)
+		if len(history) == 0 {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.history.none"))
+			return
+		}
+
+		pages := (len(history) + historyPageSize - 1) / historyPageSize
+		page := 1
+		if len(parts) > 1 {
+			var err error
+			if page, err = strconv.Atoi(parts[1]); err != nil || page < 1 || page > pages {
+				service.SendMessage(message.Channel(), bot.Translate(service, message, "music.history.page", parts[1], pages))
+				return
+			}
+		}
+
+		lines := []string{bot.Translate(service, message, "music.history", page, pages), "```"}
+		for i := (page - 1) * historyPageSize; i < len(history) && i < page*historyPageSize; i++ {
+			e := history[i]
+			played := formatTimestamp(e.Played)
+			if e.Song.Duration > 0 {
+				played += "/" + formatTimestamp(time.Duration(e.Song.Duration)*time.Second)
+			}
+			line := fmt.Sprintf("%d. %s (%s, %s, %s)", i+1, e.Song.Title, e.Song.AddedBy, humanize.Time(e.Time), played)
+			if e.Skipped {
+				line += " " + bot.Translate(service, message, "music.history.skipped")
+			}
+			lines = append(lines, line)
+		}
+		lines = append(lines, "```", bot.Translate(service, message, "music.history.help", service.CommandPrefix()))
+		service.SendMessage(message.Channel(), strings.Join(lines, "\n"))
+
+	case "replay":
+		if vc == nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.novoice"))
+			return
+		}
+		history := p.history(guildID)
+		if len(parts) != 2 {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.replay.usage", service.CommandPrefix()))
+			return
+		}
+		n, ok := parsePosition(parts[1], len(history))
+		if !ok {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.replay.invalid", parts[1], len(history)))
+			return
+		}
+		p.gostart(vc, service)
+		p.addSong(bot, vc, service, message, history[n-1].Song)
+
+	case "top":
+		p.Lock()
+		stats := p.ListeningStats[guildID]
+		var counts []playCount
+		plays, total := 0, time.Duration(0)
+		requesters := len(parts) > 1 && (parts[1] == "users" || parts[1] == "requesters" || parts[1] == "djs")
+		if stats != nil {
+			plays, total = stats.Plays, stats.Time
+			if requesters {
+				counts = top(stats.Requesters)
+			} else {
+				counts = top(stats.Tracks)
+			}
+		}
+		p.Unlock()
+
+		if len(counts) == 0 {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.history.none"))
+			return
+		}
+
+		title := "music.top.tracks"
+		if requesters {
+			title = "music.top.requesters"
+		}
+		lines := []string{bot.Translate(service, message, title), "```"}
+		for i, c := range counts {
+			lines = append(lines, fmt.Sprintf("%d. %s (%s, %s)", i+1, c.Name, bot.TranslatePlural(service, message, "music.top.plays", c.Plays, c.Plays), formatListeningTime(c.Time)))
+		}
+		lines = append(lines, "```", bot.TranslatePlural(service, message, "music.top.total", plays, plays, formatListeningTime(total)))
+		service.SendMessage(message.Channel(), strings.Join(lines, "\n"))
+	}
+}
+
+// formatListeningTime formats a listening time in days, hours and minutes.
+func formatListeningTime(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	}
+	if hours > 0 {
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
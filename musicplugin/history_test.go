@@ -0,0 +1,62 @@
+package musicplugin
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPrune(t *testing.T) {
+	counts := map[string]*playCount{}
+	for i := 0; i <= maxPlayCounts; i++ {
+		counts[fmt.Sprint(i)] = &playCount{Name: fmt.Sprint(i), Plays: 1, Time: time.Duration(i)}
+	}
+	counts["top"] = &playCount{Name: "top", Plays: 100}
+
+	prune(counts)
+	if len(counts) != maxPlayCounts/2 {
+		t.Fatalf("prune kept %d counts, want %d", len(counts), maxPlayCounts/2)
+	}
+	if counts["top"] == nil {
+		t.Errorf("prune removed the most played count")
+	}
+	if counts["0"] != nil {
+		t.Errorf("prune kept the least played count")
+	}
+}
+
+func TestRecordPrunes(t *testing.T) {
+	p := New(nil, Config{}).(*MusicPlugin)
+	for i := 0; i < maxPlayCounts+10; i++ {
+		p.record("guild", song{Title: fmt.Sprint(i), URL: fmt.Sprint(i), AddedByID: fmt.Sprint(i)}, time.Now(), time.Minute, false)
+	}
+
+	stats := p.ListeningStats["guild"]
+	if len(stats.Tracks) > maxPlayCounts || len(stats.Requesters) > maxPlayCounts {
+		t.Errorf("the stats have %d tracks and %d requesters, want at most %d", len(stats.Tracks), len(stats.Requesters), maxPlayCounts)
+	}
+	if stats.Plays != maxPlayCounts+10 || stats.Time != time.Duration(maxPlayCounts+10)*time.Minute {
+		t.Errorf("the totals are %d plays and %v, want every play counted", stats.Plays, stats.Time)
+	}
+	if len(p.History["guild"]) != maxHistory {
+		t.Errorf("the history has %d songs, want %d", len(p.History["guild"]), maxHistory)
+	}
+}
+
+func TestFormatListeningTime(t *testing.T) {
+	tests := []struct {
+		d time.Duration
+		s string
+	}{
+		{0, "0m"},
+		{5 * time.Minute, "5m"},
+		{2*time.Hour + 5*time.Minute, "2h 5m"},
+		{26*time.Hour + 5*time.Minute, "1d 2h 5m"},
+	}
+
+	for _, test := range tests {
+		if s := formatListeningTime(test.d); s != test.s {
+			t.Errorf("formatListeningTime(%v) = %q, want %q", test.d, s, test.s)
+		}
+	}
+}
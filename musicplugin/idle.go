@@ -0,0 +1,121 @@
+package musicplugin
+
+import (
+	"log"
+	"time"
+
+	"github.com/ThyLeader/rikka"
+)
+
+// The default time the bot stays in a voice channel without listeners or songs.
+const defaultIdleTimeout = 10 * time.Minute
+
+// How often voice channels are checked for listeners.
+const idleCheckInterval = 15 * time.Second
+
+// idleTimeout returns how long the bot stays in a voice channel without listeners or songs.
+func (p *MusicPlugin) idleTimeout() time.Duration {
+	if p.config.IdleTimeout <= 0 {
+		return defaultIdleTimeout
+	}
+	return p.config.IdleTimeout
+}
+
+// watch checks the voice channels for listeners until the plugin stops.
+func (p *MusicPlugin) watch(service rikka.Service) {
+	for range time.Tick(idleCheckInterval) {
+		p.Lock()
+		vcs := []*voiceConnection{}
+		for _, vc := range p.VoiceConnections {
+			vcs = append(vcs, vc)
+		}
+		p.Unlock()
+
+		for _, vc := range vcs {
+			p.checkIdle(service, vc)
+		}
+	}
+}
+
+// checkIdle pauses the song when nobody is listening and resumes it when someone is back,
+// and leaves the voice channel when nobody has listened or nothing has played for the idle timeout.
+func (p *MusicPlugin) checkIdle(service rikka.Service, vc *voiceConnection) {
+	defer rikka.MessageRecover()
+
+	listeners := p.listeners(vc)
+	if listeners == nil {
+		return
+	}
+	delete(listeners, service.UserID())
+	empty := len(listeners) == 0
+
+	vc.Lock()
+	playing := vc.Playing != nil && vc.control != nil
+	idle := empty || (vc.Playing == nil && len(vc.Queue) == 0)
+	if !idle {
+		vc.idleSince = time.Time{}
+	} else if vc.idleSince.IsZero() {
+		vc.idleSince = time.Now()
+	}
+	since := vc.idleSince
+
+	pause := playing && empty && !vc.paused
+	resume := playing && !empty && vc.autoPaused
+	if pause {
+		vc.paused = true
+		vc.autoPaused = true
+	}
+	if resume {
+		vc.paused = false
+		vc.autoPaused = false
+	}
+	vc.Unlock()
+
+	switch {
+	case idle && time.Since(since) >= p.idleTimeout():
+		p.leave(vc)
+		p.announce(service, vc, "music.idle.left", int(p.idleTimeout().Minutes()))
+	case pause:
+		vc.send(Pause)
+		p.announce(service, vc, "music.idle.paused")
+	case resume:
+		vc.send(Resume)
+		p.announce(service, vc, "music.idle.resumed")
+	}
+}
+
+// leave stops the music and leaves the voice channel of a voice connection.
+func (p *MusicPlugin) leave(vc *voiceConnection) {
+	vc.stop()
+
+	vc.Lock()
+	conn := vc.conn
+	vc.Unlock()
+
+	if conn != nil {
+		if err := conn.Disconnect(); err != nil {
+			log.Println("musicplugin: disconnecting from vc err:", err)
+		}
+	}
+
+	p.Lock()
+	if p.VoiceConnections[vc.GuildID] == vc {
+		delete(p.VoiceConnections, vc.GuildID)
+	}
+	p.Unlock()
+}
+
+// announce sends a message to the text channel the music was last controlled from.
+func (p *MusicPlugin) announce(service rikka.Service, vc *voiceConnection, key string, args ...interface{}) {
+	vc.Lock()
+	channelID := vc.TextChannelID
+	if channelID == "" && vc.Playing != nil {
+		channelID = vc.Playing.TextChannelID
+	}
+	vc.Unlock()
+
+	if channelID == "" {
+		return
+	}
+	service.SendMessage(channelID, p.bot.TranslateGuild(service, vc.GuildID, "", key, args...))
+}
@@ -0,0 +1,124 @@
+package musicplugin
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ThyLeader/rikka"
+)
+
+// The default limits of a guild.
+const (
+	defaultMaxQueue = 500
+	// defaultMaxDuration is in seconds.
+	defaultMaxDuration = 5 * 60 * 60
+	defaultMaxShare    = 100
+	defaultMaxPlaylist = maxPlaylistSongs
+)
+
+// The highest limits a guild can choose, playlists can't be longer than maxPlaylistSongs.
+const (
+	maxQueueLimit    = 5000
+	maxDurationLimit = 24 * time.Hour
+)
+
+// room returns how many more songs a user can add to the queue, and the message of the limit that stops them.
+// The lock must be held.
+func (vc *voiceConnection) room(userID string, s guildSettings) (int, string) {
+	n := s.MaxQueue - len(vc.Queue)
+	key := "music.limit.queue"
+
+	if s.MaxShare < 100 {
+		share := s.MaxQueue * s.MaxShare / 100
+		if share < 1 {
+			share = 1
+		}
+		for _, q := range vc.Queue {
+			if q.AddedByID == userID {
+				share--
+			}
+		}
+		if share < n {
+			n = share
+			key = "music.limit.share"
+		}
+	}
+
+	if n < 0 {
+		n = 0
+	}
+	return n, key
+}
+
+// limitMessage returns the message for a limit that stops a user from adding songs.
+func (p *MusicPlugin) limitMessage(bot *rikka.Bot, service rikka.Service, message rikka.Message, s guildSettings, key string) string {
+	if key == "music.limit.share" {
+		return bot.Translate(service, message, key, s.MaxQueue*s.MaxShare/100, s.MaxShare)
+	}
+	return bot.Translate(service, message, key, s.MaxQueue)
+}
+
+// limitCommand shows the limits of a guild, or sets one of them.
+func (p *MusicPlugin) limitCommand(bot *rikka.Bot, service rikka.Service, message rikka.Message, guildID string, parts []string) {
+	current := p.settings(guildID)
+
+	if len(parts) < 3 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.limits", current.MaxQueue, formatTimestamp(time.Duration(current.MaxDuration)*time.Second), current.MaxShare, current.MaxPlaylist, service.CommandPrefix()))
+		return
+	}
+
+	if !service.IsModerator(message) {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.moderator"))
+		return
+	}
+
+	value := parts[2]
+	switch parts[1] {
+	case "queue":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxQueueLimit {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.limit.invalid", 1, maxQueueLimit))
+			return
+		}
+		p.updateSettings(guildID, func(s *guildSettings) {
+			s.MaxQueue = n
+		})
+
+	case "duration", "length":
+		d, ok := parseTimestamp(value)
+		if !ok || d < time.Minute || d > maxDurationLimit {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.limit.invalid", "1:00", formatTimestamp(maxDurationLimit)))
+			return
+		}
+		p.updateSettings(guildID, func(s *guildSettings) {
+			s.MaxDuration = int(d.Seconds())
+		})
+
+	case "share", "user":
+		n, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+		if err != nil || n < 1 || n > 100 {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.limit.invalid", "1%", "100%"))
+			return
+		}
+		p.updateSettings(guildID, func(s *guildSettings) {
+			s.MaxShare = n
+		})
+
+	case "playlist":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxPlaylistSongs {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.limit.invalid", 1, maxPlaylistSongs))
+			return
+		}
+		p.updateSettings(guildID, func(s *guildSettings) {
+			s.MaxPlaylist = n
+		})
+
+	default:
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.limit.usage", service.CommandPrefix()))
+		return
+	}
+
+	service.SendMessage(message.Channel(), bot.Translate(service, message, "music.limit.set", parts[1], value))
+}
@@ -0,0 +1,391 @@
+package musicplugin
+
+import "github.com/ThyLeader/rikka"
+
+func init() {
+	rikka.RegisterMessages("en", map[string]string{
+		"music.help":                   "Music, see `%shelp music`",
+		"music.help.examples":          "Examples:",
+		"music.help.short":             "All music commands can be shortened with `%[1]sm` or `%[1]smu`",
+		"music.help.join":              "Join your voice channel or the provided voice channel.",
+		"music.help.leave":             "Leave current voice channel.",
+		"music.help.play":              "Start playing music and optionally enqueue provided url.",
+		"music.help.info":              "Show the song that is playing in a panel that is kept up to date.",
+		"music.help.pause":             "Pause playback of current song.",
+		"music.help.resume":            "Resume playback of current song.",
+		"music.help.skip":              "Skip current song.",
+		"music.help.stop":              "Stop playing music.",
+		"music.help.list":              "List contents of queue.",
+		"music.help.clear":             "Clear all items from queue.",
+		"music.help.stats":             "View stats about the music command.",
+		"music.help.loop":              "Loops through the current queue.",
+		"music.help.repeat":            "Repeats the current song.",
+		"music.help.announce":          "Toggles 'now playing' announcements.",
+		"music.join.novoice":           "I couldn't find you in any voice channels, please join one.",
+		"music.join":                   "Now, let's play some music!",
+		"music.novoice":                "There is no voice connection for this Guild.",
+		"music.leave":                  "Closed voice connection.",
+		"music.debug":                  "debug mode set to %v",
+		"music.stats":                  "Music stats:\n`Total connections:`\t%v\n`Total songs queued:`\t%v\n`Current songs queued:`\t%v\n`Current time queued:`\t%v",
+		"music.queue.empty":            "The music queue is empty.",
+		"music.queue.playing":          "**(Now Playing)**",
+		"music.loop.norepeat":          "Disabled repeat and set looping to `%v`",
+		"music.loop":                   "Looping set to `%v`",
+		"music.repeat.noloop":          "Disabled looping and set repeat to `%v`",
+		"music.repeat":                 "Repeat set to `%v`",
+		"music.clear":                  "Queue cleared",
+		"music.announce":               "Song announcements set to `%v`",
+		"music.unknown":                "Unknown music command, try `help music`",
+		"music.add.error":              "Error adding song to playlist.",
+		"music.add.toolong":            "Sorry, `%s` can't be added, songs can be up to `%s` long.",
+		"music.search.none":            "Your search term `%s` returned no results",
+		"music.search.select":          "Please select the song you would like to play.",
+		"music.search.help":            "Type the appropriate number to select the song.\nType 'exit' to leave the menu.",
+		"music.search.invalid":         "Please type a number between 1 and 5. You typed `%s`.",
+		"music.search.picked":          "You picked number %v.",
+		"music.added.one":              "Added *%s* to the queue as requested by %s.\nThere is now `%v` song in the queue",
+		"music.added.other":            "Added *%s* to the queue as requested by %s.\nThere are now `%v` songs in the queue",
+		"music.playing.one":            "Now playing *%s* as requested by *%s*\nSong left in queue: `%v` `[%s total]`",
+		"music.playing.other":          "Now playing *%s* as requested by *%s*\nSongs left in queue: `%v` `[%s total]`",
+		"music.error.loop":             "There was an error. Resetting loop and repeat",
+		"music.help.stream":            "Enqueue an audio file or internet radio URL, it is played without youtube-dl.",
+		"music.help.local":             "Enqueue a file or folder from the music library, or search it.",
+		"music.help.library":           "Browse or search the music library. Audio files attached to `play` are queued too.",
+		"music.stream.invalid":         "Please provide an http or https URL of an audio file or stream.",
+		"music.url.private":            "Songs can't be played from private or internal addresses.",
+		"music.library.none":           "There is no music library configured.",
+		"music.library.notfound":       "`%s` was not found in the music library.",
+		"music.library.empty":          "There are no audio files in `%s`.",
+		"music.library.browse":         "Music library `%s`:",
+		"music.library.results":        "Music library files matching `%s`:",
+		"music.library.more":           "...and %d more.",
+		"music.library.help":           "Queue a file or folder with `%smusic local <path>`.",
+		"music.help.remove":            "Remove a song, a range of songs, or the songs added by a user from the queue.",
+		"music.help.move":              "Move a song to another position in the queue.",
+		"music.help.shuffle":           "Shuffle the queue.",
+		"music.help.skipto":            "Skip to a song in the queue, skipping the songs before it.",
+		"music.help.dedupe":            "Remove songs that are in the queue more than once.",
+		"music.help.removeabsent":      "Remove the songs added by users who are no longer in the voice channel.",
+		"music.queue.remove.usage":     "Usage: `%smusic remove <n | from-to | @user>`",
+		"music.queue.move.usage":       "Usage: `%smusic move <from> <to>`",
+		"music.queue.skipto.usage":     "Usage: `%smusic skipto <n>`",
+		"music.queue.position":         "`%s` is not a position in the queue, there are %d songs waiting.",
+		"music.queue.removed.song":     "Removed *%s* from the queue.",
+		"music.queue.removed.one":      "Removed %d song from the queue.",
+		"music.queue.removed.other":    "Removed %d songs from the queue.",
+		"music.queue.moved":            "Moved *%s* to position %d.",
+		"music.queue.shuffled.one":     "Shuffled %d song.",
+		"music.queue.shuffled.other":   "Shuffled %d songs.",
+		"music.queue.skipto":           "Skipping to *%s*.",
+		"music.queue.deduped.one":      "Removed %d duplicate song.",
+		"music.queue.deduped.other":    "Removed %d duplicate songs.",
+		"music.queue.absent.one":       "Removed %d song added by users who left the voice channel.",
+		"music.queue.absent.other":     "Removed %d songs added by users who left the voice channel.",
+		"music.help.volume":            "Show or set the volume of this server, in percent.",
+		"music.help.bitrate":           "Show or set the bitrate of this server, in kbps.",
+		"music.help.filter":            "Show or set the audio filter of this server: bassboost, nightcore, vaporwave, normalize or 8d.",
+		"music.volume":                 "The volume is `%d%%`.",
+		"music.volume.set":             "Volume set to `%d%%`.",
+		"music.volume.invalid":         "Please give a volume between 1 and %d percent.",
+		"music.bitrate":                "The bitrate is `%dkbps`.",
+		"music.bitrate.set":            "Bitrate set to `%dkbps`.",
+		"music.bitrate.invalid":        "Please give a bitrate between %d and %d kbps.",
+		"music.filter":                 "The audio filter is `%s`. Filters: %s",
+		"music.filter.set":             "Audio filter set to `%s`.",
+		"music.filter.off":             "Audio filter turned off.",
+		"music.filter.unknown":         "Unknown filter `%s`. Filters: %s",
+		"music.help.seek":              "Move to a position in the current song.",
+		"music.help.forward":           "Move forward or back in the current song, 10 seconds by default.",
+		"music.seek":                   "Moved to `%s` of `%s`.",
+		"music.seek.usage":             "Please give a position, eg. `%smusic seek 1:30`.",
+		"music.seek.invalid":           "`%s` isn't a position, please use seconds or `mm:ss`.",
+		"music.seek.nothing":           "Nothing is playing.",
+		"music.seek.stream":            "Streams can't be moved in.",
+		"music.seek.end":               "That is past the end of the song, it is `%s` long.",
+		"music.help.dj":                "Show or set the role needed to skip, stop, clear, leave, change the queue, loop or repeat, and change the volume, bitrate and filter. Others can vote to skip.",
+		"music.help.skipvotes":         "Show or set the percentage of listeners that have to vote to skip a song.",
+		"music.dj":                     "The DJ role is `%s`.",
+		"music.dj.none":                "There is no DJ role, everyone can control the music.",
+		"music.dj.set":                 "The DJ role is now `%s`.",
+		"music.dj.off":                 "The DJ role was removed, everyone can control the music.",
+		"music.dj.notfound":            "I couldn't find the role `%s`.",
+		"music.dj.required":            "You need the `%s` role to do that, but you can vote to skip.",
+		"music.skipvotes":              "`%d%%` of listeners have to vote to skip a song.",
+		"music.skipvotes.set":          "`%d%%` of listeners now have to vote to skip a song.",
+		"music.skipvotes.invalid":      "Please give a percentage between 1 and 100.",
+		"music.skip.listening":         "You have to be in the voice channel to vote to skip.",
+		"music.skip.vote":              "Vote to skip counted, `%d` of `%d` votes needed.",
+		"music.skip.voted":             "`%d` of `%d` votes, skipping.",
+		"music.idle.paused":            "Everyone left the voice channel, the music is paused until someone is back.",
+		"music.idle.resumed":           "Welcome back, the music continues.",
+		"music.idle.left":              "I left the voice channel because nobody listened or nothing played for %d minutes.",
+		"music.help.limit":             "Show the limits of the queue, or set one of them.",
+		"music.limits":                 "`Queue size:` %d songs\n`Longest song:` %s\n`Share of the queue per user:` %d%%\n`Songs from a playlist:` %d\nModerators can change them with `%smusic limit <queue|duration|share|playlist> <value>`.",
+		"music.limit.usage":            "Please use `%smusic limit <queue|duration|share|playlist> <value>`.",
+		"music.limit.invalid":          "Please give a value between %v and %v.",
+		"music.limit.set":              "The `%s` limit is now `%s`.",
+		"music.limit.queue":            "The queue is full, it can hold %d songs.",
+		"music.limit.share":            "You already have %d songs in the queue, the most one user can have (%d%% of it).",
+		"music.limit.playlist":         "Only the first %d of the %d songs are added.",
+		"music.limit.toolong.one":      "%d song was left out because it is longer than `%s`.",
+		"music.limit.toolong.other":    "%d songs were left out because they are longer than `%s`.",
+		"music.help.history":           "Show the songs that were played in this server.",
+		"music.help.replay":            "Queue a song from the history again.",
+		"music.help.top":               "Show the songs, or with `users` the requesters, that were played most in this server.",
+		"music.history":                "Recently played, page %d of %d:",
+		"music.history.none":           "Nothing has been played in this server yet.",
+		"music.history.page":           "There is no page `%s`, there are %d pages.",
+		"music.history.skipped":        "[skipped]",
+		"music.history.help":           "Use `%smusic replay <n>` to play a song again.",
+		"music.replay.usage":           "Please give the number of a song in the history, eg. `%smusic replay 1`.",
+		"music.replay.invalid":         "`%s` isn't in the history, please give a number from 1 to %d.",
+		"music.top.tracks":             "Most played songs:",
+		"music.top.requesters":         "Top requesters:",
+		"music.top.plays.one":          "%d play",
+		"music.top.plays.other":        "%d plays",
+		"music.top.total.one":          "%d song played in this server, `%s` of listening.",
+		"music.top.total.other":        "%d songs played in this server, `%s` of listening.",
+		"music.help.controls":          "Show or set whether the now playing panel has reactions to pause, skip and loop.",
+		"music.controls":               "Reaction controls on the now playing panel: `%v`",
+		"music.controls.usage":         "Please use `%smusic controls on` or `%[1]smusic controls off`.",
+		"music.panel.requester":        "Requested by",
+		"music.panel.loop":             "Loop",
+		"music.panel.repeat":           "Repeat",
+		"music.panel.on":               "On",
+		"music.panel.off":              "Off",
+		"music.panel.live":             "live",
+		"music.panel.sound":            "Sound",
+		"music.panel.sound.value":      "%d%%, filter: %s",
+		"music.panel.next":             "Up next",
+		"music.panel.empty":            "Nothing, add songs with `music play`.",
+		"music.panel.queue.one":        "%d song in the queue, %s",
+		"music.panel.queue.other":      "%d songs in the queue, %s",
+		"music.panel.nothing.one":      "Nothing is playing, %d song is in the queue.",
+		"music.panel.nothing.other":    "Nothing is playing, %d songs are in the queue.",
+		"music.panel.stopped":          "Nothing is playing anymore.",
+		"music.help.playlist":          "Save the queue as a playlist of yours or of the server, and load, share, import or export playlists.",
+		"music.playlist.usage":         "Usage: `%smusic playlist <save|load|append|list|show|rename|delete|share|unshare|import|export> [guild|@user] <name>`",
+		"music.playlist.name":          "Playlist names can't have spaces or formatting and can be up to %d characters long.",
+		"music.playlist.notfound":      "Playlist `%s` not found.",
+		"music.playlist.other":         "You can only change your own playlists.",
+		"music.playlist.nothing":       "The queue is empty, there is nothing to save.",
+		"music.playlist.saved":         "Saved %d songs to playlist `%s`.",
+		"music.playlist.truncated":     "Playlists can only have %d songs, the rest were left out.",
+		"music.playlist.toomany":       "You can only have %d playlists.",
+		"music.playlist.loaded":        "Loaded %d songs from playlist `%s`, there are now `%d` songs in the queue.",
+		"music.playlist.appended":      "Added %d songs from playlist `%s`, there are now `%d` songs in the queue.",
+		"music.playlist.show":          "Playlist `%s`, %d songs [%s]:",
+		"music.playlist.none":          "There are no playlists.",
+		"music.playlist.list.user":     "Your playlists:",
+		"music.playlist.list.guild":    "Server playlists:",
+		"music.playlist.list.other":    "Playlists shared by <@%s>:",
+		"music.playlist.line":          "`%s` %d songs [%s]%s",
+		"music.playlist.shared":        " (shared)",
+		"music.playlist.exists":        "There is already a playlist named `%s`.",
+		"music.playlist.renamed":       "Playlist `%s` renamed to `%s`.",
+		"music.playlist.deleted":       "Playlist `%s` deleted.",
+		"music.playlist.share.on":      "Playlist `%s` is now shared, others can load it with `%smusic playlist load <@%s> %s`",
+		"music.playlist.share.off":     "Playlist `%s` is no longer shared.",
+		"music.playlist.share.guild":   "Server playlists are already shared with everyone in the server.",
+		"music.playlist.import.error":  "Couldn't import the playlist: %s. Attach an M3U or text file of URLs, or give its URL.",
+		"music.playlist.skipped.one":   "%d entry couldn't be read and was skipped.",
+		"music.playlist.skipped.other": "%d entries couldn't be read and were skipped.",
+		"music.playlist.reading.one":   "Importing %d entry, I'll let you know when the playlist is saved.",
+		"music.playlist.reading.other": "Importing %d entries, I'll let you know when the playlist is saved.",
+		"music.playlist.lookups.one":   "%d entry without a title was left out, only %d are looked up per import. Playlists exported with `music playlist export` keep their titles.",
+		"music.playlist.lookups.other": "%d entries without a title were left out, only %d are looked up per import. Playlists exported with `music playlist export` keep their titles.",
+	})
+
+	rikka.RegisterMessages("es", map[string]string{
+		"music.help":                   "Música, mira `%shelp music`",
+		"music.help.examples":          "Ejemplos:",
+		"music.help.short":             "Todos los comandos de música se pueden abreviar con `%[1]sm` o `%[1]smu`",
+		"music.help.join":              "Entra a tu canal de voz o al canal de voz indicado.",
+		"music.help.leave":             "Sale del canal de voz actual.",
+		"music.help.play":              "Empieza a reproducir música y opcionalmente añade la url indicada.",
+		"music.help.info":              "Muestra la canción actual en un panel que se mantiene actualizado.",
+		"music.help.pause":             "Pausa la canción actual.",
+		"music.help.resume":            "Reanuda la canción actual.",
+		"music.help.skip":              "Salta la canción actual.",
+		"music.help.stop":              "Deja de reproducir música.",
+		"music.help.list":              "Muestra el contenido de la cola.",
+		"music.help.clear":             "Elimina todas las canciones de la cola.",
+		"music.help.stats":             "Muestra estadísticas del comando de música.",
+		"music.help.loop":              "Repite la cola actual en bucle.",
+		"music.help.repeat":            "Repite la canción actual.",
+		"music.help.announce":          "Activa o desactiva los anuncios de 'reproduciendo ahora'.",
+		"music.join.novoice":           "No te encontré en ningún canal de voz, por favor entra a uno.",
+		"music.join":                   "¡Ahora, pongamos algo de música!",
+		"music.novoice":                "No hay conexión de voz en este servidor.",
+		"music.leave":                  "Conexión de voz cerrada.",
+		"music.debug":                  "modo de depuración: %v",
+		"music.stats":                  "Estadísticas de música:\n`Conexiones totales:`\t%v\n`Canciones añadidas:`\t%v\n`Canciones en cola:`\t%v\n`Tiempo en cola:`\t%v",
+		"music.queue.empty":            "La cola de música está vacía.",
+		"music.queue.playing":          "**(Reproduciendo ahora)**",
+		"music.loop.norepeat":          "Repetición desactivada y bucle establecido en `%v`",
+		"music.loop":                   "Bucle establecido en `%v`",
+		"music.repeat.noloop":          "Bucle desactivado y repetición establecida en `%v`",
+		"music.repeat":                 "Repetición establecida en `%v`",
+		"music.clear":                  "Cola vaciada",
+		"music.announce":               "Anuncios de canciones establecidos en `%v`",
+		"music.unknown":                "Comando de música desconocido, prueba `help music`",
+		"music.add.error":              "Error al añadir la canción a la lista.",
+		"music.add.toolong":            "Lo siento, no se puede añadir `%s`, las canciones pueden durar hasta `%s`.",
+		"music.search.none":            "Tu búsqueda `%s` no devolvió resultados",
+		"music.search.select":          "Por favor elige la canción que quieres reproducir.",
+		"music.search.help":            "Escribe el número correspondiente para elegir la canción.\nEscribe 'exit' para salir del menú.",
+		"music.search.invalid":         "Por favor escribe un número entre 1 y 5. Escribiste `%s`.",
+		"music.search.picked":          "Elegiste el número %v.",
+		"music.added.one":              "*%s* añadida a la cola a petición de %s.\nAhora hay `%v` canción en la cola",
+		"music.added.other":            "*%s* añadida a la cola a petición de %s.\nAhora hay `%v` canciones en la cola",
+		"music.playing.one":            "Reproduciendo *%s* a petición de *%s*\nCanción restante en la cola: `%v` `[%s en total]`",
+		"music.playing.other":          "Reproduciendo *%s* a petición de *%s*\nCanciones restantes en la cola: `%v` `[%s en total]`",
+		"music.error.loop":             "Hubo un error. Restableciendo bucle y repetición",
+		"music.help.stream":            "Añade a la cola un archivo de audio o una radio por internet, se reproduce sin youtube-dl.",
+		"music.help.local":             "Añade a la cola un archivo o carpeta de la biblioteca de música, o la busca.",
+		"music.help.library":           "Explora o busca en la biblioteca de música. Los archivos de audio adjuntos a `play` también se añaden.",
+		"music.stream.invalid":         "Por favor indica una URL http o https de un archivo o stream de audio.",
+		"music.url.private":            "No se pueden reproducir canciones desde direcciones privadas o internas.",
+		"music.library.none":           "No hay ninguna biblioteca de música configurada.",
+		"music.library.notfound":       "No se encontró `%s` en la biblioteca de música.",
+		"music.library.empty":          "No hay archivos de audio en `%s`.",
+		"music.library.browse":         "Biblioteca de música `%s`:",
+		"music.library.results":        "Archivos de la biblioteca que coinciden con `%s`:",
+		"music.library.more":           "...y %d más.",
+		"music.library.help":           "Añade un archivo o carpeta con `%smusic local <ruta>`.",
+		"music.help.remove":            "Quita de la cola una canción, un rango de canciones o las canciones añadidas por un usuario.",
+		"music.help.move":              "Mueve una canción a otra posición de la cola.",
+		"music.help.shuffle":           "Mezcla la cola.",
+		"music.help.skipto":            "Salta a una canción de la cola, saltando las anteriores.",
+		"music.help.dedupe":            "Quita las canciones que están más de una vez en la cola.",
+		"music.help.removeabsent":      "Quita las canciones añadidas por usuarios que ya no están en el canal de voz.",
+		"music.queue.remove.usage":     "Uso: `%smusic remove <n | desde-hasta | @usuario>`",
+		"music.queue.move.usage":       "Uso: `%smusic move <desde> <hasta>`",
+		"music.queue.skipto.usage":     "Uso: `%smusic skipto <n>`",
+		"music.queue.position":         "`%s` no es una posición de la cola, hay %d canciones esperando.",
+		"music.queue.removed.song":     "Se quitó *%s* de la cola.",
+		"music.queue.removed.one":      "Se quitó %d canción de la cola.",
+		"music.queue.removed.other":    "Se quitaron %d canciones de la cola.",
+		"music.queue.moved":            "*%s* movida a la posición %d.",
+		"music.queue.shuffled.one":     "Se mezcló %d canción.",
+		"music.queue.shuffled.other":   "Se mezclaron %d canciones.",
+		"music.queue.skipto":           "Saltando a *%s*.",
+		"music.queue.deduped.one":      "Se quitó %d canción repetida.",
+		"music.queue.deduped.other":    "Se quitaron %d canciones repetidas.",
+		"music.queue.absent.one":       "Se quitó %d canción añadida por usuarios que salieron del canal de voz.",
+		"music.queue.absent.other":     "Se quitaron %d canciones añadidas por usuarios que salieron del canal de voz.",
+		"music.help.volume":            "Muestra o cambia el volumen de este servidor, en porcentaje.",
+		"music.help.bitrate":           "Muestra o cambia la tasa de bits de este servidor, en kbps.",
+		"music.help.filter":            "Muestra o cambia el filtro de audio de este servidor: bassboost, nightcore, vaporwave, normalize u 8d.",
+		"music.volume":                 "El volumen es `%d%%`.",
+		"music.volume.set":             "Volumen cambiado a `%d%%`.",
+		"music.volume.invalid":         "Por favor indica un volumen entre 1 y %d por ciento.",
+		"music.bitrate":                "La tasa de bits es `%dkbps`.",
+		"music.bitrate.set":            "Tasa de bits cambiada a `%dkbps`.",
+		"music.bitrate.invalid":        "Por favor indica una tasa de bits entre %d y %d kbps.",
+		"music.filter":                 "El filtro de audio es `%s`. Filtros: %s",
+		"music.filter.set":             "Filtro de audio cambiado a `%s`.",
+		"music.filter.off":             "Filtro de audio desactivado.",
+		"music.filter.unknown":         "Filtro desconocido `%s`. Filtros: %s",
+		"music.help.seek":              "Mueve la canción actual a una posición.",
+		"music.help.forward":           "Avanza o retrocede en la canción actual, 10 segundos por defecto.",
+		"music.seek":                   "Movido a `%s` de `%s`.",
+		"music.seek.usage":             "Por favor indica una posición, p. ej. `%smusic seek 1:30`.",
+		"music.seek.invalid":           "`%s` no es una posición, por favor usa segundos o `mm:ss`.",
+		"music.seek.nothing":           "No se está reproduciendo nada.",
+		"music.seek.stream":            "No se puede mover en las transmisiones.",
+		"music.seek.end":               "Eso está después del final de la canción, dura `%s`.",
+		"music.help.dj":                "Muestra o cambia el rol necesario para saltar, detener, vaciar, salir, cambiar la cola, repetir, y cambiar el volumen, el bitrate y el filtro. Los demás pueden votar para saltar.",
+		"music.help.skipvotes":         "Muestra o cambia el porcentaje de oyentes que deben votar para saltar una canción.",
+		"music.dj":                     "El rol de DJ es `%s`.",
+		"music.dj.none":                "No hay rol de DJ, todos pueden controlar la música.",
+		"music.dj.set":                 "El rol de DJ ahora es `%s`.",
+		"music.dj.off":                 "Se quitó el rol de DJ, todos pueden controlar la música.",
+		"music.dj.notfound":            "No pude encontrar el rol `%s`.",
+		"music.dj.required":            "Necesitas el rol `%s` para hacer eso, pero puedes votar para saltar.",
+		"music.skipvotes":              "El `%d%%` de los oyentes deben votar para saltar una canción.",
+		"music.skipvotes.set":          "Ahora el `%d%%` de los oyentes deben votar para saltar una canción.",
+		"music.skipvotes.invalid":      "Por favor indica un porcentaje entre 1 y 100.",
+		"music.skip.listening":         "Tienes que estar en el canal de voz para votar para saltar.",
+		"music.skip.vote":              "Voto para saltar contado, `%d` de `%d` votos necesarios.",
+		"music.skip.voted":             "`%d` de `%d` votos, saltando.",
+		"music.idle.paused":            "Todos salieron del canal de voz, la música está en pausa hasta que alguien vuelva.",
+		"music.idle.resumed":           "Bienvenido de vuelta, la música continúa.",
+		"music.idle.left":              "Salí del canal de voz porque nadie escuchó o nada sonó durante %d minutos.",
+		"music.help.limit":             "Muestra los límites de la cola, o cambia uno de ellos.",
+		"music.limits":                 "`Tamaño de la cola:` %d canciones\n`Canción más larga:` %s\n`Parte de la cola por usuario:` %d%%\n`Canciones de una lista:` %d\nLos moderadores pueden cambiarlos con `%smusic limit <queue|duration|share|playlist> <valor>`.",
+		"music.limit.usage":            "Por favor usa `%smusic limit <queue|duration|share|playlist> <valor>`.",
+		"music.limit.invalid":          "Por favor indica un valor entre %v y %v.",
+		"music.limit.set":              "El límite `%s` ahora es `%s`.",
+		"music.limit.queue":            "La cola está llena, puede tener %d canciones.",
+		"music.limit.share":            "Ya tienes %d canciones en la cola, lo máximo para un usuario (%d%% de ella).",
+		"music.limit.playlist":         "Solo se añaden las primeras %d de las %d canciones.",
+		"music.limit.toolong.one":      "%d canción se dejó fuera porque dura más de `%s`.",
+		"music.limit.toolong.other":    "%d canciones se dejaron fuera porque duran más de `%s`.",
+		"music.help.history":           "Muestra las canciones que se reprodujeron en este servidor.",
+		"music.help.replay":            "Añade de nuevo a la cola una canción del historial.",
+		"music.help.top":               "Muestra las canciones, o con `users` quienes las pidieron, más reproducidas en este servidor.",
+		"music.history":                "Reproducidas recientemente, página %d de %d:",
+		"music.history.none":           "Todavía no se ha reproducido nada en este servidor.",
+		"music.history.page":           "No hay página `%s`, hay %d páginas.",
+		"music.history.skipped":        "[saltada]",
+		"music.history.help":           "Usa `%smusic replay <n>` para reproducir una canción de nuevo.",
+		"music.replay.usage":           "Por favor indica el número de una canción del historial, p. ej. `%smusic replay 1`.",
+		"music.replay.invalid":         "`%s` no está en el historial, por favor indica un número del 1 al %d.",
+		"music.top.tracks":             "Canciones más reproducidas:",
+		"music.top.requesters":         "Quienes más canciones pidieron:",
+		"music.top.plays.one":          "%d reproducción",
+		"music.top.plays.other":        "%d reproducciones",
+		"music.top.total.one":          "%d canción reproducida en este servidor, `%s` de escucha.",
+		"music.top.total.other":        "%d canciones reproducidas en este servidor, `%s` de escucha.",
+		"music.help.controls":          "Muestra o cambia si el panel de la canción actual tiene reacciones para pausar, saltar y repetir la cola.",
+		"music.controls":               "Controles con reacciones en el panel de la canción actual: `%v`",
+		"music.controls.usage":         "Por favor usa `%smusic controls on` o `%[1]smusic controls off`.",
+		"music.panel.requester":        "Pedida por",
+		"music.panel.loop":             "Bucle",
+		"music.panel.repeat":           "Repetir",
+		"music.panel.on":               "Sí",
+		"music.panel.off":              "No",
+		"music.panel.live":             "en vivo",
+		"music.panel.sound":            "Sonido",
+		"music.panel.sound.value":      "%d%%, filtro: %s",
+		"music.panel.next":             "A continuación",
+		"music.panel.empty":            "Nada, añade canciones con `music play`.",
+		"music.panel.queue.one":        "%d canción en la cola, %s",
+		"music.panel.queue.other":      "%d canciones en la cola, %s",
+		"music.panel.nothing.one":      "No se está reproduciendo nada, hay %d canción en la cola.",
+		"music.panel.nothing.other":    "No se está reproduciendo nada, hay %d canciones en la cola.",
+		"music.panel.stopped":          "Ya no se está reproduciendo nada.",
+		"music.help.playlist":          "Guarda la cola como una lista tuya o del servidor, y carga, comparte, importa o exporta listas.",
+		"music.playlist.usage":         "Uso: `%smusic playlist <save|load|append|list|show|rename|delete|share|unshare|import|export> [guild|@usuario] <nombre>`",
+		"music.playlist.name":          "Los nombres de las listas no pueden tener espacios ni formato y pueden tener hasta %d caracteres.",
+		"music.playlist.notfound":      "No se encontró la lista `%s`.",
+		"music.playlist.other":         "Solo puedes cambiar tus propias listas.",
+		"music.playlist.nothing":       "La cola está vacía, no hay nada que guardar.",
+		"music.playlist.saved":         "Se guardaron %d canciones en la lista `%s`.",
+		"music.playlist.truncated":     "Las listas solo pueden tener %d canciones, el resto se dejó fuera.",
+		"music.playlist.toomany":       "Solo puedes tener %d listas.",
+		"music.playlist.loaded":        "Se cargaron %d canciones de la lista `%s`, ahora hay `%d` canciones en la cola.",
+		"music.playlist.appended":      "Se añadieron %d canciones de la lista `%s`, ahora hay `%d` canciones en la cola.",
+		"music.playlist.show":          "Lista `%s`, %d canciones [%s]:",
+		"music.playlist.none":          "No hay listas.",
+		"music.playlist.list.user":     "Tus listas:",
+		"music.playlist.list.guild":    "Listas del servidor:",
+		"music.playlist.list.other":    "Listas compartidas por <@%s>:",
+		"music.playlist.line":          "`%s` %d canciones [%s]%s",
+		"music.playlist.shared":        " (compartida)",
+		"music.playlist.exists":        "Ya hay una lista llamada `%s`.",
+		"music.playlist.renamed":       "Lista `%s` renombrada a `%s`.",
+		"music.playlist.deleted":       "Lista `%s` eliminada.",
+		"music.playlist.share.on":      "La lista `%s` ahora está compartida, otros pueden cargarla con `%smusic playlist load <@%s> %s`",
+		"music.playlist.share.off":     "La lista `%s` ya no está compartida.",
+		"music.playlist.share.guild":   "Las listas del servidor ya están compartidas con todos en el servidor.",
+		"music.playlist.import.error":  "No se pudo importar la lista: %s. Adjunta un archivo M3U o de texto con URLs, o indica su URL.",
+		"music.playlist.skipped.one":   "%d entrada no se pudo leer y se omitió.",
+		"music.playlist.skipped.other": "%d entradas no se pudieron leer y se omitieron.",
+		"music.playlist.reading.one":   "Importando %d entrada, te avisaré cuando la lista esté guardada.",
+		"music.playlist.reading.other": "Importando %d entradas, te avisaré cuando la lista esté guardada.",
+		"music.playlist.lookups.one":   "%d entrada sin título se dejó fuera, solo se buscan %d por importación. Las listas exportadas con `music playlist export` conservan sus títulos.",
+		"music.playlist.lookups.other": "%d entradas sin título se dejaron fuera, solo se buscan %d por importación. Las listas exportadas con `music playlist export` conservan sus títulos.",
+	})
+}
@@ -1,14 +1,11 @@
 package musicplugin
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/url"
-	"os"
-	"os/exec"
 	"strconv"
 	"strings"
 	"sync"
@@ -19,30 +16,81 @@ import (
 	"github.com/jonas747/dca"
 )
 
+// Config configures the music plugin.
+type Config struct {
+	// Library is a directory of audio files that can be browsed and queued, there is no library when it is empty.
+	Library string
+	// Bitrate is the default bitrate of songs in kbps, guilds can choose their own.
+	Bitrate int
+	// YoutubeDL is the path of youtube-dl, or of a fork like yt-dlp, it is found in the PATH when it is empty.
+	YoutubeDL string
+	// Resolver finds the songs that are played and searched for, youtube-dl is used when it is nil.
+	Resolver Resolver
+	// IdleTimeout is how long the bot stays in a voice channel without listeners or songs, 10 minutes when it is 0.
+	IdleTimeout time.Duration
+}
+
 type MusicPlugin struct {
 	sync.Mutex
 
+	bot     *rikka.Bot
 	discord *rikka.Discord
+	config  Config
 
 	VoiceConnections map[string]*voiceConnection
+	// GuildPlaylists are saved playlists by guild ID and lower case name.
+	GuildPlaylists map[string]map[string]*playlist
+	// UserPlaylists is only read to move the playlists of users from older saves into Redis.
+	UserPlaylists map[string]map[string]*playlist `json:",omitempty"`
+	// Settings are the music settings of guilds, by guild ID.
+	Settings map[string]*guildSettings
+	// History are the songs played in guilds, the most recent last, and ListeningStats their totals, by guild ID.
+	History        map[string][]historyEntry
+	ListeningStats map[string]*listeningStats
 }
 
 type voiceConnection struct {
 	sync.Mutex
 	debug bool
 
-	GuildID      string
-	ChannelID    string
-	MaxQueueSize int
-	Queue        []song
-	Loop         bool
-	Repeat       bool
-	Announce     bool
+	GuildID   string
+	ChannelID string
+	// Queue holds the songs waiting to be played, the song that is playing is not in it.
+	Queue []song
+	// Playing is the song that is playing, it is saved so it is played again after a restart.
+	Playing  *song
+	Loop     bool
+	Repeat   bool
+	Announce bool
+	// TextChannelID is the channel the music was last controlled from, where idle announcements are sent.
+	TextChannelID string
 
 	close   chan struct{}
 	control chan controlMessage
-	playing *song
-	conn    *discordgo.VoiceConnection
+	// seek is where the song that is playing is moved to by a Seek control message.
+	seek time.Duration
+	// votes are the users that voted to skip the song that is playing.
+	votes map[string]bool
+	// skipped are the songs skipped past in a looping queue, they are queued again after the song that is playing.
+	skipped []song
+	// paused is whether the song that is playing is paused, autoPaused whether it was paused because nobody was listening.
+	paused     bool
+	autoPaused bool
+	// idleSince is when the voice channel was left without listeners or songs.
+	idleSince time.Time
+	// panelMessageID is the now playing panel that is kept updated.
+	panelMessageID string
+	// wake is signalled when songs are added, so the queue doesn't have to be polled.
+	wake chan struct{}
+	conn *discordgo.VoiceConnection
+}
+
+// notify wakes the queue if it is waiting for songs.
+func (vc *voiceConnection) notify() {
+	select {
+	case vc.wake <- struct{}{}:
+	default:
+	}
 }
 
 type controlMessage int
@@ -53,11 +101,16 @@ const (
 	Skip controlMessage = iota
 	Pause
 	Resume
+	// Restart encodes the song that is playing again from where it is, with the current settings.
+	Restart
+	// Seek encodes the song that is playing again from the seek position of the voice connection.
+	Seek
 )
 
 type song struct {
 	TextChannelID string
 	AddedBy       string
+	AddedByID     string
 	ID            string `json:"id"`
 	Title         string `json:"title"`
 	Description   string `json:"description"`
@@ -68,14 +121,23 @@ type song struct {
 	Likes         int    `json:"like_count"`
 	Views         int    `json:"view_count"`
 	Remaining     int
+	// Position is how far the song has played, it is saved so the song resumes from there after a restart.
+	Position time.Duration
+	// Source is where the song is played from, youtube-dl, a direct URL or the music library.
+	Source string
 }
 
 // New will create a new music plugin.
-func New(discord *rikka.Discord) rikka.Plugin {
+func New(discord *rikka.Discord, config Config) rikka.Plugin {
 
 	p := &MusicPlugin{
 		discord:          discord,
+		config:           config,
 		VoiceConnections: make(map[string]*voiceConnection),
+		GuildPlaylists:   make(map[string]map[string]*playlist),
+		Settings:         make(map[string]*guildSettings),
+		History:          make(map[string][]historyEntry),
+		ListeningStats:   make(map[string]*listeningStats),
 	}
 
 	return p
@@ -92,11 +154,26 @@ func (p *MusicPlugin) Load(bot *rikka.Bot, service rikka.Service, data []byte) (
 		panic("Music Plugin only supports Discord.")
 	}
 
+	p.bot = bot
+
 	if data != nil {
 		if err = json.Unmarshal(data, p); err != nil {
 			log.Println("musicplugin: loading data err:", err)
 		}
 	}
+	p.migratePlaylists()
+	if p.GuildPlaylists == nil {
+		p.GuildPlaylists = make(map[string]map[string]*playlist)
+	}
+	if p.Settings == nil {
+		p.Settings = make(map[string]*guildSettings)
+	}
+	if p.History == nil {
+		p.History = make(map[string][]historyEntry)
+	}
+	if p.ListeningStats == nil {
+		p.ListeningStats = make(map[string]*listeningStats)
+	}
 
 	go p.init(service)
 
@@ -116,7 +193,20 @@ func (p *MusicPlugin) init(service rikka.Service) {
 
 func (p *MusicPlugin) ready(service rikka.Service) {
 	// Join all registered voice channels and start the playback queue
+	p.Lock()
+	for guildID, v := range p.VoiceConnections {
+		// There is no need to join again when there is nothing to play.
+		if v.Playing == nil && len(v.Queue) == 0 {
+			delete(p.VoiceConnections, guildID)
+		}
+	}
+	vcs := []*voiceConnection{}
 	for _, v := range p.VoiceConnections {
+		vcs = append(vcs, v)
+	}
+	p.Unlock()
+
+	for _, v := range vcs {
 		if v.ChannelID == "" {
 			continue
 		}
@@ -127,10 +217,18 @@ func (p *MusicPlugin) ready(service rikka.Service) {
 		}
 		p.gostart(vc, service)
 	}
+
+	p.discord.ForEachSession(func(s *discordgo.Session) {
+		s.AddHandler(p.onReactionAdd)
+	})
+	go p.watch(service)
 }
 
 // Save will save plugin state to a byte array.
 func (p *MusicPlugin) Save() ([]byte, error) {
+	p.Lock()
+	defer p.Unlock()
+
 	return json.Marshal(p)
 }
 
@@ -141,27 +239,49 @@ func (p *MusicPlugin) Help(bot *rikka.Bot, service rikka.Service, message rikka.
 	}
 
 	help := []string{
-		rikka.CommandHelp(service, "music", "<command>", fmt.Sprintf("Music, see `%shelp music`", service.CommandPrefix()))[0],
+		rikka.CommandHelp(service, "music", "<command>", bot.Translate(service, message, "music.help", service.CommandPrefix()))[0],
 	}
 
 	if detailed {
 		help = append(help, []string{
-			"Examples:",
-			rikka.CommandHelp(service, "music", "join [channelid]", "Join your voice channel or the provided voice channel.")[0],
-			rikka.CommandHelp(service, "music", "leave", "Leave current voice channel.")[0],
-			rikka.CommandHelp(service, "music", "play/add [url | youtube search term]", "Start playing music and optionally enqueue provided url.")[0],
-			rikka.CommandHelp(service, "music", "info", "Information about this plugin and the currently playing song.")[0],
-			rikka.CommandHelp(service, "music", "pause", "Pause playback of current song.")[0],
-			rikka.CommandHelp(service, "music", "resume", "Resume playback of current song.")[0],
-			rikka.CommandHelp(service, "music", "skip", "Skip current song.")[0],
-			rikka.CommandHelp(service, "music", "stop", "Stop playing music.")[0],
-			rikka.CommandHelp(service, "music", "list/queue", "List contents of queue.")[0],
-			rikka.CommandHelp(service, "music", "clear", "Clear all items from queue.")[0],
-			rikka.CommandHelp(service, "music", "stats", "View stats about the music command.")[0],
-			rikka.CommandHelp(service, "music", "loop", "Loops through the current queue.")[0],
-			rikka.CommandHelp(service, "music", "repeat", "Repeats the current song.")[0],
-			rikka.CommandHelp(service, "music", "announce", "Toggles 'now playing' announcements.")[0],
-			"All music commands can be shortened with `r.m` or `r.mu`",
+			bot.Translate(service, message, "music.help.examples"),
+			rikka.CommandHelp(service, "music", "join [channelid]", bot.Translate(service, message, "music.help.join"))[0],
+			rikka.CommandHelp(service, "music", "leave", bot.Translate(service, message, "music.help.leave"))[0],
+			rikka.CommandHelp(service, "music", "play/add [url | youtube search term]", bot.Translate(service, message, "music.help.play"))[0],
+			rikka.CommandHelp(service, "music", "stream/radio <url>", bot.Translate(service, message, "music.help.stream"))[0],
+			rikka.CommandHelp(service, "music", "local <path | search term>", bot.Translate(service, message, "music.help.local"))[0],
+			rikka.CommandHelp(service, "music", "library [path | search <term>]", bot.Translate(service, message, "music.help.library"))[0],
+			rikka.CommandHelp(service, "music", "playlist <save|load|append|list|show|rename|delete|share|import|export> [guild|@user] <name>", bot.Translate(service, message, "music.help.playlist"))[0],
+			rikka.CommandHelp(service, "music", "info/np", bot.Translate(service, message, "music.help.info"))[0],
+			rikka.CommandHelp(service, "music", "controls [on | off]", bot.Translate(service, message, "music.help.controls"))[0],
+			rikka.CommandHelp(service, "music", "pause", bot.Translate(service, message, "music.help.pause"))[0],
+			rikka.CommandHelp(service, "music", "resume", bot.Translate(service, message, "music.help.resume"))[0],
+			rikka.CommandHelp(service, "music", "skip", bot.Translate(service, message, "music.help.skip"))[0],
+			rikka.CommandHelp(service, "music", "limit [queue | duration | share | playlist] [value]", bot.Translate(service, message, "music.help.limit"))[0],
+			rikka.CommandHelp(service, "music", "dj [@role | off]", bot.Translate(service, message, "music.help.dj"))[0],
+			rikka.CommandHelp(service, "music", "skipvotes [1-100]", bot.Translate(service, message, "music.help.skipvotes"))[0],
+			rikka.CommandHelp(service, "music", "seek <[h:]mm:ss>", bot.Translate(service, message, "music.help.seek"))[0],
+			rikka.CommandHelp(service, "music", "forward/rewind [seconds]", bot.Translate(service, message, "music.help.forward"))[0],
+			rikka.CommandHelp(service, "music", "stop", bot.Translate(service, message, "music.help.stop"))[0],
+			rikka.CommandHelp(service, "music", "list/queue", bot.Translate(service, message, "music.help.list"))[0],
+			rikka.CommandHelp(service, "music", "clear", bot.Translate(service, message, "music.help.clear"))[0],
+			rikka.CommandHelp(service, "music", "remove <n | from-to | @user>", bot.Translate(service, message, "music.help.remove"))[0],
+			rikka.CommandHelp(service, "music", "move <from> <to>", bot.Translate(service, message, "music.help.move"))[0],
+			rikka.CommandHelp(service, "music", "shuffle", bot.Translate(service, message, "music.help.shuffle"))[0],
+			rikka.CommandHelp(service, "music", "skipto <n>", bot.Translate(service, message, "music.help.skipto"))[0],
+			rikka.CommandHelp(service, "music", "dedupe", bot.Translate(service, message, "music.help.dedupe"))[0],
+			rikka.CommandHelp(service, "music", "removeabsent", bot.Translate(service, message, "music.help.removeabsent"))[0],
+			rikka.CommandHelp(service, "music", "stats", bot.Translate(service, message, "music.help.stats"))[0],
+			rikka.CommandHelp(service, "music", "history [page]", bot.Translate(service, message, "music.help.history"))[0],
+			rikka.CommandHelp(service, "music", "replay <n>", bot.Translate(service, message, "music.help.replay"))[0],
+			rikka.CommandHelp(service, "music", "top [users]", bot.Translate(service, message, "music.help.top"))[0],
+			rikka.CommandHelp(service, "music", "loop", bot.Translate(service, message, "music.help.loop"))[0],
+			rikka.CommandHelp(service, "music", "repeat", bot.Translate(service, message, "music.help.repeat"))[0],
+			rikka.CommandHelp(service, "music", "announce", bot.Translate(service, message, "music.help.announce"))[0],
+			rikka.CommandHelp(service, "music", "volume [1-200]", bot.Translate(service, message, "music.help.volume"))[0],
+			rikka.CommandHelp(service, "music", "bitrate [8-128]", bot.Translate(service, message, "music.help.bitrate"))[0],
+			rikka.CommandHelp(service, "music", "filter [name | off]", bot.Translate(service, message, "music.help.filter"))[0],
+			bot.Translate(service, message, "music.help.short", service.CommandPrefix()),
 		}...)
 	}
 
@@ -181,7 +301,7 @@ func (p *MusicPlugin) Message(bot *rikka.Bot, service rikka.Service, message rik
 	}
 
 	if service.IsPrivate(message) {
-		service.SendMessage(message.Channel(), "Sorry, this command doesn't work in private chat.")
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.private"))
 		return
 	}
 
@@ -201,7 +321,19 @@ func (p *MusicPlugin) Message(bot *rikka.Bot, service rikka.Service, message rik
 	}
 
 	// grab pointer to this channels voice connection, if exists.
+	p.Lock()
 	vc, vcok := p.VoiceConnections[channel.GuildID]
+	p.Unlock()
+	if vcok {
+		vc.Lock()
+		vc.TextChannelID = message.Channel()
+		vc.Unlock()
+	}
+
+	if djCommands[parts[0]] && !(djSettings[parts[0]] && len(parts) == 1) && !p.isDJ(service, message, channel.GuildID) {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.dj.required", p.roleName(channel.GuildID, p.settings(channel.GuildID).DJRole)))
+		return
+	}
 
 	switch parts[0] {
 
@@ -228,7 +360,7 @@ func (p *MusicPlugin) Message(bot *rikka.Bot, service rikka.Service, message rik
 			}
 
 			if channelID == "" {
-				service.SendMessage(message.Channel(), "I couldn't find you in any voice channels, please join one.")
+				service.SendMessage(message.Channel(), bot.Translate(service, message, "music.join.novoice"))
 				return
 			}
 		}
@@ -239,46 +371,71 @@ func (p *MusicPlugin) Message(bot *rikka.Bot, service rikka.Service, message rik
 			break
 		}
 
-		service.SendMessage(message.Channel(), "Now, let's play some music!")
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.join"))
 
 	case "leave":
 		// leave voice channel for this Guild
 		if !vcok {
-			service.SendMessage(message.Channel(), "There is no voice connection for this Guild.")
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.novoice"))
 			return
 		}
 
-		err = vc.conn.Disconnect()
-		if err != nil {
-			log.Println("error disconnecting from vc", err.Error())
-		}
-		delete(p.VoiceConnections, channel.GuildID)
-		service.SendMessage(message.Channel(), "Closed voice connection.")
+		p.leave(vc)
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.leave"))
 
 	case "debug":
 		// enable or disable debug
 		if !vcok {
-			service.SendMessage(message.Channel(), fmt.Sprintf("There is no voice connection for this Guild."))
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.novoice"))
 			return
 		}
 
 		vc.Lock()
 		vc.debug = !vc.debug
-		service.SendMessage(message.Channel(), fmt.Sprintf("debug mode set to %v", vc.debug))
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.debug", vc.debug))
 		vc.Unlock()
 
 	//case "play":
 	case "add", "play":
 		// Start queue player and optionally enqueue provided songs
 		if !vcok {
-			service.SendMessage(message.Channel(), "There is no voice connection for this Guild.")
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.novoice"))
 			return
 		}
 
 		p.gostart(vc, service)
 
+		// Audio files attached to the message are queued as they are.
+		if songs := attachments(message); len(songs) > 0 {
+			for _, s := range songs {
+				if !p.addSong(bot, vc, service, message, s) {
+					return
+				}
+			}
+			if len(parts) == 1 {
+				return
+			}
+		}
+
 		if len(parts[1:]) == 1 {
 			u, err := url.ParseRequestURI(parts[1])
+			// URLs are read by the bot, so they can't point into the network it runs in.
+			if err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+				if u, err = publicURL(u.String()); err != nil {
+					log.Println("musicplugin: checking url err:", err)
+					key := "music.add.error"
+					if err == errPrivateAddress {
+						key = "music.url.private"
+					}
+					service.SendMessage(message.Channel(), bot.Translate(service, message, key))
+					return
+				}
+			}
+			if err == nil && isStream(u) {
+				service.Typing(message.Channel())
+				p.addSong(bot, vc, service, message, streamSong(u, ""))
+				return
+			}
 			if err != nil {
 				service.Typing(message.Channel())
 				err = p.enqueue(bot, vc, parts[1], service, message, true)
@@ -300,83 +457,136 @@ func (p *MusicPlugin) Message(bot *rikka.Bot, service rikka.Service, message rik
 			service.SendMessage(message.Channel(), err.Error())
 		}
 
-	case "stop":
-		// stop the queue player
+	case "stream", "radio":
+		// enqueue an audio file or internet radio URL that is played without youtube-dl
 		if !vcok {
-			service.SendMessage(message.Channel(), "There is no voice connection for this Guild.")
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.novoice"))
+			return
+		}
+		if len(parts) != 2 {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.stream.invalid"))
 			return
 		}
 
-		if vc.close != nil {
-			close(vc.close)
-			vc.close = nil
+		p.gostart(vc, service)
+		p.stream(bot, vc, service, message, parts[1])
+
+	case "local", "file":
+		// enqueue a file or directory from the music library
+		if !vcok {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.novoice"))
+			return
+		}
+		if len(parts) < 2 {
+			p.library(bot, service, message, nil)
+			return
 		}
 
-		if vc.control != nil {
-			close(vc.control)
-			vc.control = nil
+		p.gostart(vc, service)
+		service.Typing(message.Channel())
+		p.local(bot, vc, service, message, strings.Join(parts[1:], " "))
+
+	case "library", "lib":
+		// browse or search the music library
+		p.library(bot, service, message, parts[1:])
+
+	case "playlist", "playlists", "pl":
+		// manage saved playlists, mentions are kept to refer to the playlists of other users
+		_, raw := rikka.ParseCommandString(service, message.RawMessage())
+		p.playlistCommand(bot, vc, service, message, channel.GuildID, raw[1:])
+
+	case "remove", "rm", "move", "mv", "shuffle", "skipto", "dedupe", "removeabsent":
+		// change the songs waiting in the queue
+		if !vcok {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.novoice"))
+			return
 		}
 
-	case "skip":
-		// skip current song
+		if p.queueCommand(bot, vc, service, message, parts) {
+			vc.send(Skip)
+		}
+
+	case "volume", "vol", "bitrate", "filter", "filters", "fx":
+		// change how songs sound in this guild, the song that is playing is changed too
+		p.settingsCommand(bot, service, message, channel.GuildID, parts)
+
+	case "stop":
+		// stop the queue player
 		if !vcok {
-			service.SendMessage(message.Channel(), "There is no voice connection for this Guild.")
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.novoice"))
 			return
 		}
 
-		if vc.control == nil {
+		vc.stop()
+
+	case "skip":
+		// skip current song
+		if !vcok {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.novoice"))
 			return
 		}
-		vc.control <- Skip
+
+		p.skip(bot, vc, service, message)
+
+	case "history", "recent", "replay", "top":
+		// show the songs that were played, play one again, or show what was played most
+		p.historyCommand(bot, vc, service, message, channel.GuildID, parts)
+
+	case "limit", "limits":
+		// show or set the limits of the queue
+		p.limitCommand(bot, service, message, channel.GuildID, parts)
+
+	case "dj", "skipvotes":
+		// show or set who can use the DJ commands, and how many listeners have to vote to skip
+		p.djCommand(bot, service, message, channel.GuildID, parts)
 
 	case "pause":
 		// pause the queue player
 		if !vcok {
-			service.SendMessage(message.Channel(), "There is no voice connection for this Guild.")
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.novoice"))
 			return
 		}
 
-		if vc.control == nil {
-			return
-		}
-		vc.control <- Pause
+		vc.Lock()
+		vc.paused = true
+		vc.autoPaused = false
+		vc.Unlock()
+		vc.send(Pause)
 
 	case "resume":
 		// resume the queue player
 		if !vcok {
-			service.SendMessage(message.Channel(), "There is no voice connection for this Guild.")
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.novoice"))
 			return
 		}
 
-		if vc.control == nil {
+		vc.Lock()
+		vc.paused = false
+		vc.autoPaused = false
+		vc.Unlock()
+		vc.send(Resume)
+
+	case "seek", "forward", "ff", "rewind", "rw":
+		// move to another position in the current song
+		if !vcok {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.novoice"))
 			return
 		}
-		vc.control <- Resume
+		p.seekCommand(bot, vc, service, message, parts)
 
 	case "info", "np":
 		// report player settings, queue info, and current song
 
 		if vc == nil {
-			service.SendMessage(message.Channel(), "I'm not in a voice channel!")
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.novoice"))
 			break
 		}
 
-		msg := fmt.Sprintf("`Voice Channel:` %s\n", vc.ChannelID)
-		msg += fmt.Sprintf("`Queue Size:` %d\n", len(vc.Queue))
+		p.nowPlaying(bot, vc, service, message)
 
-		if vc.playing == nil {
-			service.SendMessage(message.Channel(), msg)
-			break
-		}
-
-		msg += fmt.Sprintf("`Now Playing:`\n")
-		msg += fmt.Sprintf("`ID:` %s\n", vc.playing.ID)
-		msg += fmt.Sprintf("`Title:` %s\n", vc.playing.Title)
-		msg += fmt.Sprintf("`Duration:` %ds\n", vc.playing.Duration)
-		msg += fmt.Sprintf("`Remaining:` %ds\n", vc.playing.Remaining)
-		msg += fmt.Sprintf("`Source URL:` <%s>\n", vc.playing.URL)
-		msg += fmt.Sprintf("`Thumbnail:` %s\n", vc.playing.Thumbnail)
-		service.SendMessage(message.Channel(), msg)
+	case "controls":
+		// show or set whether the now playing panel has reaction controls
+		p.controlsCommand(bot, service, message, channel.GuildID, parts)
 
 	case "stats":
 		p.Lock()
@@ -389,37 +599,44 @@ func (p *MusicPlugin) Message(bot *rikka.Bot, service rikka.Service, message rik
 				l += time.Duration(q.Duration)
 			}
 		}
+		stats := p.ListeningStats[channel.GuildID]
+		plays, total := 0, time.Duration(0)
+		if stats != nil {
+			plays, total = stats.Plays, stats.Time
+		}
 		p.Unlock()
-		msg := fmt.Sprintf("Music stats:\n")
-		msg += fmt.Sprintf("`Total connections:`\t%v\n", c)
-		msg += fmt.Sprintf("`Total songs queued:`\t%v\n", songsAdded)
-		msg += fmt.Sprintf("`Current songs queued:`\t%v\n", s)
-		msg += fmt.Sprintf("`Current time queued:`\t%v", time.Duration(l*time.Second).String())
+		msg := bot.Translate(service, message, "music.stats", c, songsAdded, s, time.Duration(l*time.Second).String())
+		msg += "\n" + bot.TranslatePlural(service, message, "music.top.total", plays, plays, formatListeningTime(total))
 		service.SendMessage(message.Channel(), msg)
 
 	case "list", "queue":
 		// list top items in the queue
 		if !vcok {
-			service.SendMessage(message.Channel(), "There is no voice connection for this Guild.")
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.novoice"))
 			return
 		}
 
-		if len(vc.Queue) == 0 {
-			service.SendMessage(message.Channel(), "The music queue is empty.")
+		vc.Lock()
+		playing := vc.Playing
+		queue := append([]song{}, vc.Queue...)
+		vc.Unlock()
+
+		if playing == nil && len(queue) == 0 {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.queue.empty"))
 			return
 		}
 
 		var msg string
+		if playing != nil {
+			d := time.Duration(playing.Duration) * time.Second
+			msg += fmt.Sprintf("`NOW:%.15s` **%s** [%s] - *%s* %s\n", playing.ID, playing.Title, d.String(), playing.AddedBy, bot.Translate(service, message, "music.queue.playing"))
+		}
 
 		i := 1
 		i2 := 0
-		for k, v := range vc.Queue {
-			np := ""
-			if k == 0 {
-				np = "**(Now Playing)**"
-			}
+		for k, v := range queue {
 			d := time.Duration(v.Duration) * time.Second
-			msg += fmt.Sprintf("`%.3d:%.15s` **%s** [%s] - *%s* %s\n", k, v.ID, v.Title, d.String(), v.AddedBy, np)
+			msg += fmt.Sprintf("`%.3d:%.15s` **%s** [%s] - *%s*\n", k+1, v.ID, v.Title, d.String(), v.AddedBy)
 
 			if i >= 15 {
 				service.SendMessage(message.Channel(), msg)
@@ -440,7 +657,7 @@ func (p *MusicPlugin) Message(bot *rikka.Bot, service rikka.Service, message rik
 	case "loop", "l":
 		// loop the queue
 		if !vcok {
-			service.SendMessage(message.Channel(), "There is no voice connection for this Guild.")
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.novoice"))
 			return
 		}
 		vc.Lock()
@@ -448,18 +665,18 @@ func (p *MusicPlugin) Message(bot *rikka.Bot, service rikka.Service, message rik
 		if vc.Repeat {
 			vc.Repeat = false
 			vc.Loop = !vc.Loop
-			go service.SendMessage(message.Channel(), fmt.Sprintf("Disabled repeat and set looping to `%v`", vc.Loop))
+			go service.SendMessage(message.Channel(), bot.Translate(service, message, "music.loop.norepeat", vc.Loop))
 			return
 		}
 
 		vc.Loop = !vc.Loop
-		go service.SendMessage(message.Channel(), fmt.Sprintf("Looping set to `%v`", vc.Loop))
+		go service.SendMessage(message.Channel(), bot.Translate(service, message, "music.loop", vc.Loop))
 		return
 
 	case "repeat", "r":
 		// repeat current song
 		if !vcok {
-			service.SendMessage(message.Channel(), "There is no voice connection for this Guild.")
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.novoice"))
 			return
 		}
 		vc.Lock()
@@ -467,40 +684,40 @@ func (p *MusicPlugin) Message(bot *rikka.Bot, service rikka.Service, message rik
 		if vc.Loop {
 			vc.Loop = false
 			vc.Repeat = !vc.Repeat
-			go service.SendMessage(message.Channel(), fmt.Sprintf("Disabled looping and set repeat to `%v`", vc.Repeat))
+			go service.SendMessage(message.Channel(), bot.Translate(service, message, "music.repeat.noloop", vc.Repeat))
 			return
 		}
 
 		vc.Repeat = !vc.Repeat
-		go service.SendMessage(message.Channel(), fmt.Sprintf("Repeat set to `%v`", vc.Repeat))
+		go service.SendMessage(message.Channel(), bot.Translate(service, message, "music.repeat", vc.Repeat))
 		return
 
 	case "clear":
 		// clear all items from the queue
 		if !vcok {
-			service.SendMessage(message.Channel(), "There is no voice connection for this Guild.")
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.novoice"))
 			return
 		}
 
 		vc.Lock()
 		vc.Queue = []song{}
 		vc.Unlock()
-		service.SendMessage(message.Channel(), "Queue cleared")
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.clear"))
 
 	case "announce":
 		// toggle song announcements
 		if !vcok {
-			service.SendMessage(message.Channel(), "There is no voice connection for this Guild.")
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.novoice"))
 			return
 		}
 
 		vc.Lock()
 		vc.Announce = !vc.Announce
-		go service.SendMessage(message.Channel(), fmt.Sprintf("Song announcements set to `%v`", vc.Announce))
+		go service.SendMessage(message.Channel(), bot.Translate(service, message, "music.announce", vc.Announce))
 		vc.Unlock()
 
 	default:
-		service.SendMessage(message.Channel(), "Unknown music command, try `help music`")
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.unknown"))
 	}
 }
 
@@ -525,23 +742,20 @@ func (p *MusicPlugin) join(cID string) (vc *voiceConnection, err error) {
 		vc.Announce = true
 		p.VoiceConnections[c.GuildID] = vc
 	}
+	if vc.wake == nil {
+		vc.wake = make(chan struct{}, 1)
+	}
 	p.Unlock()
 
 	// default song announcements to true
 	vc.Announce = true
 
-	guild, err := p.discord.Guild(c.GuildID)
-	if err != nil {
-		return
-	}
-
-	gID, err := strconv.Atoi(guild.ID)
-	if err != nil {
+	if _, err = p.discord.Guild(c.GuildID); err != nil {
 		return
 	}
 
 	// NOTE: Setting mute to false, deaf to true.
-	vc.conn, err = p.discord.Sessions[(gID>>22)%len(p.discord.Sessions)].ChannelVoiceJoin(c.GuildID, cID, false, true)
+	vc.conn, err = p.discord.SessionForGuild(c.GuildID).ChannelVoiceJoin(c.GuildID, cID, false, true)
 	if err != nil {
 		return
 	}
@@ -564,87 +778,42 @@ func (p *MusicPlugin) enqueue(bot *rikka.Bot, vc *voiceConnection, url string, s
 	}
 
 	// TODO //////////////////////////////////////////////////////////////////
-	// need to parse the url and have a way to know what we're doing
-	// 1) option to queue local files
-	// 2) option to queue saved playlists
-	// 3) option to queue URL that can be passed directly to ffmpeg without youtube-dl
-	// 4) option to queue youtube-dl playlist
-	// 5) option to queue youtube-dl song
-	// 6) option to queue youtube-dl search result
-
-	// right now option 4 and 5 work, only.
+	// enqueue only handles the songs, playlists and searches of the query resolver, youtube-dl by default.
+	// Local files, direct URLs and attachments are queued by local, stream and attachments,
+	// and saved playlists by playlistCommand.
 	//////////////////////////////////////////////////////////////////////////
 
-	var cmd *exec.Cmd
-	if search {
-		cmd = exec.Command("youtube-dl", "-i", "-j", "--youtube-skip-dash-manifest", fmt.Sprintf(`ytsearch5:%s`, url))
-	} else {
-		cmd = exec.Command("youtube-dl", "-i", "-j", "--youtube-skip-dash-manifest", url)
-	}
-
+	service.Typing(message.Channel())
+	res, err := p.query().Resolve(url, search)
 	if vc.debug {
-		cmd.Stderr = os.Stderr
+		log.Printf("musicplugin: resolved %d songs for %q, err: %v", len(res), url, err)
 	}
-
-	output, err := cmd.StdoutPipe()
-	if err != nil {
-		log.Println(err)
-		service.SendMessage(message.Channel(), fmt.Sprintf("Error adding song to playlist."))
-		return
+	if err == ErrNoResults && search {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.search.none", url))
+		return nil
 	}
-
-	err = cmd.Start()
 	if err != nil {
-		log.Println(err)
-		service.SendMessage(message.Channel(), fmt.Sprintf("Error adding song to playlist."))
+		log.Println("musicplugin: resolve err:", err)
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.add.error"))
 		return
 	}
-	defer func() {
-		go cmd.Wait()
-	}()
-
-	scanner := bufio.NewScanner(output)
 
 	if search {
-		// TODO: turn this all into a single function
-		res := []song{}
-		for scanner.Scan() {
-			s := song{}
-			err = json.Unmarshal(scanner.Bytes(), &s)
-			if err != nil {
-				log.Println(err)
-				continue
-			}
-
-			s.TextChannelID = message.Channel()
-			s.AddedBy = message.UserName()
-
-			res = append(res, s)
-		}
-		if len(res) < 1 {
-			service.SendMessage(message.Channel(), fmt.Sprintf("Your search term `%s` returned no results", url))
-			return
-		}
 		if len(res) == 1 {
-			vc.Lock()
-			vc.Queue = append(vc.Queue, res[0])
-			vcLen := len(vc.Queue)
-			vc.Unlock()
-			res[0].announceSongAdded(service, message.Channel(), vcLen)
+			p.addSong(bot, vc, service, message, res[0])
 			return
 		}
 		msg := []string{}
 		msg = append(msg, []string{
 			"```rb",
-			"Please select the song you would like to play.\n",
+			bot.Translate(service, message, "music.search.select") + "\n",
 		}...)
 		for i, e := range res {
 			i++
 			msg = append(msg, fmt.Sprintf("[%v] # %s", i, e.Title))
 		}
 		msg = append(msg, []string{
-			"\nType the appropriate number to select the song.",
-			"Type 'exit' to leave the menu.",
+			"\n" + bot.Translate(service, message, "music.search.help"),
 			"```",
 		}...)
 
@@ -656,7 +825,7 @@ func (p *MusicPlugin) enqueue(bot *rikka.Bot, vc *voiceConnection, url string, s
 
 		m, err := bot.MakeCallback(service, message.UserID())
 		if err != nil {
-			service.SendMessage(message.Channel(), "A menu already exists")
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "menu.exists"))
 			return nil
 		}
 		defer bot.CloseCallback(service, message.UserID())
@@ -669,83 +838,86 @@ func (p *MusicPlugin) enqueue(bot *rikka.Bot, vc *voiceConnection, url string, s
 				}
 
 				if strings.ToLower(ms.Message()) == "exit" {
-					service.SendMessage(message.Channel(), "Exiting menu")
+					service.SendMessage(message.Channel(), bot.Translate(service, message, "menu.exit"))
 					return nil
 				}
 
 				n, err := strconv.Atoi(ms.Message())
 				if e >= 5 {
-					service.SendMessage(message.Channel(), "BAKA!! Seems you cant type a correct response. Exiting menu")
+					service.SendMessage(message.Channel(), bot.Translate(service, message, "menu.invalid"))
 					return nil
 				}
 				if err != nil {
-					service.SendMessage(message.Channel(), fmt.Sprintf("Please type a number between 1 and 5. You typed `%s`.", ms.Message()))
+					service.SendMessage(message.Channel(), bot.Translate(service, message, "music.search.invalid", ms.Message()))
 					e++
 					continue
 				}
 
-				if n > 5 || n < 1 {
-					service.SendMessage(message.Channel(), fmt.Sprintf("Please type a number between 1 and 5. You typed `%s`.", ms.Message()))
+				if n > len(res) || n < 1 {
+					service.SendMessage(message.Channel(), bot.Translate(service, message, "music.search.invalid", ms.Message()))
 					e++
 					continue
 				}
 
-				service.SendMessage(message.Channel(), fmt.Sprintf("You picked number %v.", n))
-				s := res[n-1]
-				if s.Duration > 18000 {
-					service.SendMessage(message.Channel(), "Sorry, but Rikka does not currently allow songs longer than 5 hours")
-					return nil
-				}
-				s.TextChannelID = message.Channel()
-				s.AddedBy = message.UserName()
-
-				vc.Lock()
-				vc.Queue = append(vc.Queue, s)
-				vcLen := len(vc.Queue)
-				vc.Unlock()
-				s.announceSongAdded(service, message.Channel(), vcLen)
-				songsAdded++
+				service.SendMessage(message.Channel(), bot.Translate(service, message, "music.search.picked", n))
+				p.addSong(bot, vc, service, message, res[n-1])
 				return nil
 			case <-timeout.C:
-				service.SendMessage(message.Channel(), "Menu timed out")
+				service.SendMessage(message.Channel(), bot.Translate(service, message, "menu.timeout"))
 				return nil
 			}
 		}
 	}
 
-	for scanner.Scan() {
-		s := song{}
-		err = json.Unmarshal(scanner.Bytes(), &s)
-		if err != nil {
-			log.Println(err)
-			continue
-		}
-
-		if s.Duration > 18000 {
-			service.SendMessage(message.Channel(), "Sorry, but Rikka does not currently allow songs longer than 5 hours")
+	// Playlists are cut to the limit of the guild.
+	if limit := p.settings(vc.GuildID).MaxPlaylist; len(res) > limit {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.limit.playlist", limit, len(res)))
+		res = res[:limit]
+	}
+	for _, s := range res {
+		if !p.addSong(bot, vc, service, message, s) {
 			return nil
 		}
+	}
+	return
+}
 
-		s.TextChannelID = message.Channel()
-		s.AddedBy = message.UserName()
+// addSong adds a song to the queue as requested by the sender of a message, and announces it.
+// Songs that are too long are not added. It returns false when the queue, or the sender's share of it, is full,
+// so no more songs should be added.
+func (p *MusicPlugin) addSong(bot *rikka.Bot, vc *voiceConnection, service rikka.Service, message rikka.Message, s song) bool {
+	limits := p.settings(vc.GuildID)
+	if s.Duration > limits.MaxDuration {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.add.toolong", s.Title, formatTimestamp(time.Duration(limits.MaxDuration)*time.Second)))
+		return true
+	}
 
-		vc.Lock()
-		vc.Queue = append(vc.Queue, s)
-		vcLen := len(vc.Queue)
+	s.TextChannelID = message.Channel()
+	s.AddedBy = message.UserName()
+	s.AddedByID = message.UserID()
+
+	vc.Lock()
+	if room, key := vc.room(s.AddedByID, limits); room == 0 {
 		vc.Unlock()
-		s.announceSongAdded(service, message.Channel(), vcLen)
-		songsAdded++
+		service.SendMessage(message.Channel(), p.limitMessage(bot, service, message, limits, key))
+		return false
 	}
-	return
+	vc.Queue = append(vc.Queue, s)
+	vcLen := len(vc.Queue)
+	vc.Unlock()
+	vc.notify()
+	s.announceSongAdded(bot, service, message, vcLen)
+	songsAdded++
+	return true
 }
 
 // i had a bunch of different ones scattered around so hopefully this will clean things up in terms of consistency
-func (s *song) announceSongAdded(service rikka.Service, channel string, vcLen int) {
-	service.SendMessage(channel, fmt.Sprintf("Added *%s* to the queue as requested by %s.\nThere are now `%v` songs in the queue", s.Title, s.AddedBy, vcLen))
+func (s *song) announceSongAdded(bot *rikka.Bot, service rikka.Service, message rikka.Message, vcLen int) {
+	service.SendMessage(message.Channel(), bot.TranslatePlural(service, message, "music.added", vcLen, s.Title, s.AddedBy, vcLen))
 }
 
-func (s *song) announceSongPlaying(service rikka.Service, channel string, vcLen int, timeLeft string) {
-	service.SendMessage(channel, fmt.Sprintf("Now playing *%s* as requested by *%s*\nSongs left in queue: `%v` `[%s total]`", s.Title, s.AddedBy, vcLen, timeLeft))
+func (s *song) announceSongPlaying(bot *rikka.Bot, service rikka.Service, guildID string, vcLen int, timeLeft string) {
+	service.SendMessage(s.TextChannelID, rikka.LocalizePlural(bot.Language(service, guildID, ""), "music.playing", vcLen, s.Title, s.AddedBy, vcLen, timeLeft))
 }
 
 // little wrapper function for start() to fire it off in a
@@ -774,6 +946,20 @@ func (p *MusicPlugin) gostart(vc *voiceConnection, service rikka.Service) (err e
 	return
 }
 
+// stop stops the queue player of a voice connection.
+// Only the close channel is closed, the control channel is left for the garbage collector,
+// so control messages that are being sent give up instead of sending on a closed channel.
+func (vc *voiceConnection) stop() {
+	vc.Lock()
+	defer vc.Unlock()
+
+	if vc.close != nil {
+		close(vc.close)
+		vc.close = nil
+	}
+	vc.control = nil
+}
+
 // "start" is a goroutine function that loops though the music queue and
 // plays songs as they are added
 func (p *MusicPlugin) start(vc *voiceConnection, close <-chan struct{}, control <-chan controlMessage, service rikka.Service) {
@@ -783,9 +969,6 @@ func (p *MusicPlugin) start(vc *voiceConnection, close <-chan struct{}, control
 		return
 	}
 
-	var i int
-	var s song
-
 	// main loop keeps this going until close
 	for {
 
@@ -797,105 +980,165 @@ func (p *MusicPlugin) start(vc *voiceConnection, close <-chan struct{}, control
 		default:
 		}
 
-		// loop until voice connection is ready and songs are in the queue.
-		if vc.conn == nil || vc.conn.Ready == false || len(vc.Queue) < 1 {
-			time.Sleep(1 * time.Second)
+		// wait until the voice connection is ready and songs are in the queue.
+		if vc.conn == nil || vc.conn.Ready == false {
+			select {
+			case <-close:
+				log.Println("musicplugin: start() exited due to close channel.")
+				return
+			case <-time.After(1 * time.Second):
+			}
+			continue
+		}
+		vc.Lock()
+		empty := vc.Playing == nil && len(vc.Queue) < 1
+		vc.Unlock()
+		if empty {
+			select {
+			case <-close:
+				log.Println("musicplugin: start() exited due to close channel.")
+				return
+			case <-vc.wake:
+			}
 			continue
 		}
 
-		// Get song to play and store it in local Song var
+		// Take the next song from the queue, unless a song was playing when the queue was stopped.
 		vc.Lock()
+		if vc.Playing == nil {
+			s := vc.Queue[0]
+			vc.Queue = vc.Queue[1:]
+			vc.Playing = &s
+		}
+		s := *vc.Playing
+
 		if vc.Loop && vc.Repeat {
-			service.SendMessage(vc.playing.TextChannelID, "There was an error. Resetting loop and repeat")
+			service.SendMessage(s.TextChannelID, p.bot.TranslateGuild(service, vc.GuildID, "", "music.error.loop"))
 			vc.Loop = false
 			vc.Repeat = false
 		}
 
 		vcLen := len(vc.Queue)
-		if vcLen-1 >= i {
-			s = vc.Queue[i]
-		} else {
-			i = 0
-			vc.Unlock()
-			continue
-		}
-		vc.Unlock()
-
-		vc.playing = &s
 		timeLeft := time.Duration(0)
 		for _, v := range vc.Queue {
 			timeLeft += time.Duration(v.Duration)
 		}
+		vc.Unlock()
+
 		timeLeft *= time.Second
 		if vc.Announce {
-			s.announceSongPlaying(service, vc.playing.TextChannelID, vcLen, timeLeft.String())
+			s.announceSongPlaying(p.bot, service, vc.GuildID, vcLen, timeLeft.String())
+		}
+		started := time.Now()
+		skipped, played := p.play(vc, close, control, s)
+		// Songs that couldn't be played aren't history.
+		if played > 0 {
+			p.record(vc.GuildID, s, started, played, skipped)
 		}
-		p.play(vc, close, control, s)
-		vc.playing = nil
 
 		vc.Lock()
-		if len(vc.Queue) > 0 {
-			if !vc.Repeat && !vc.Loop {
-				vc.Queue = append(vc.Queue[:i], vc.Queue[i+1:]...)
-			}
-			if vc.Repeat {
-				// do nothing
-			}
-			if vc.Loop {
-				var pop song
-				pop, vc.Queue = vc.Queue[0], vc.Queue[1:]
-				vc.Queue = append(vc.Queue, pop)
-			}
-		}
+		vc.finish(s, skipped)
 		vc.Unlock()
 	}
 }
 
-// play an individual song
-func (p *MusicPlugin) play(vc *voiceConnection, close <-chan struct{}, control <-chan controlMessage, s song) {
-	var err error
+// finish moves on from a song that finished playing or was skipped, the voice connection must be locked.
+func (vc *voiceConnection) finish(s song, skipped bool) {
+	vc.votes = nil
+	// The next song starts playing.
+	vc.paused = false
+	vc.autoPaused = false
+	switch {
+	case vc.Repeat && !skipped:
+		// The song stays as the one playing, so it plays again from the start.
+		vc.Playing.Position = 0
+	case vc.Loop:
+		s.Position = 0
+		vc.Queue = append(vc.Queue, s)
+		vc.Queue = append(vc.Queue, vc.skipped...)
+		vc.Playing = nil
+	default:
+		vc.Playing = nil
+	}
+	vc.skipped = nil
+}
 
+// play an individual song
+// It returns whether the song was skipped, and how long it played.
+func (p *MusicPlugin) play(vc *voiceConnection, close <-chan struct{}, control <-chan controlMessage, s song) (skipped bool, played time.Duration) {
 	if close == nil || control == nil || vc == nil || vc.conn == nil {
 		log.Println("musicplugin: play exited because [close|control|vc|vc.conn] is nil.")
 		return
 	}
 
-	options := dca.StdEncodeOptions
-	options.RawOutput = true
-	options.Bitrate = 64
-	options.Application = "lowdelay"
+	// The song is encoded again from where it is when the volume, bitrate or filter changes, or from where it is moved to.
+	// A song that was playing before a restart continues from where it was.
+	position := s.Position
+	paused := false
+	for {
+		var restart bool
+		var d time.Duration
+		restart, skipped, paused, position, d = p.encode(vc, close, control, s, position, paused)
+		played += d
+		if !restart {
+			return skipped, played
+		}
+	}
+}
+
+// encode encodes and sends a song from a position, in the settings of the guild.
+// It returns whether the song should be encoded again, whether it was skipped, whether it is paused, its position,
+// and how long it played.
+func (p *MusicPlugin) encode(vc *voiceConnection, close <-chan struct{}, control <-chan controlMessage, s song, position time.Duration, paused bool) (restart, skipped, stillPaused bool, at, played time.Duration) {
+	var err error
+	at = position
+
+	settings := p.settings(vc.GuildID)
+	options := settings.encodeOptions(position)
+	speed := settings.speed()
+	// ffmpeg starts at a whole second, the position is counted from there by the frames that are sent.
+	position = time.Duration(options.StartTime) * time.Second
 
-	ytdl := exec.Command("youtube-dl", "-v", "-f", "bestaudio", "-o", "-", s.URL)
-	ytdlout, err := ytdl.StdoutPipe()
+	resolver, err := p.resolver(s.Source)
 	if err != nil {
-		log.Println("ytdl StdoutPipe err:", err)
+		log.Println("musicplugin: song resolver err:", err)
 		return
 	}
-	ytdlbuf := bufio.NewReaderSize(ytdlout, 16384)
-
-	encodingSession, err := dca.EncodeMem(ytdlbuf, options)
+	audio, err := resolver.Open(s)
 	if err != nil {
-		fmt.Println("error creating encoding session", err.Error())
+		log.Println("musicplugin: open song err:", err)
 		return
 	}
-	defer encodingSession.Cleanup()
+	defer audio.Close()
 
-	err = ytdl.Start()
+	// Files and URLs are read by ffmpeg itself, so it can seek in them.
+	var encodingSession *dca.EncodeSession
+	if audio.Input != "" {
+		encodingSession, err = dca.EncodeFile(audio.Input, options)
+	} else {
+		encodingSession, err = dca.EncodeMem(audio.Reader, options)
+	}
 	if err != nil {
-		log.Println("ytdl Start err:", err)
+		fmt.Println("error creating encoding session", err.Error())
 		return
 	}
-	defer func() {
-		go ytdl.Wait()
-	}()
+	defer encodingSession.Cleanup()
 
 	vc.conn.Speaking(true)
 	defer vc.conn.Speaking(false)
 
 	d := make(chan error)
 	stream := dca.NewStream(encodingSession, vc.conn, d)
+	stream.SetPaused(paused)
+	defer func() {
+		played = stream.PlaybackPosition()
+	}()
+
+	// elapsed is the position in the song, filters that change the speed play it faster or slower.
+	elapsed := func() time.Duration {
+		return position + time.Duration(float64(stream.PlaybackPosition())*speed)
+	}
 
-	start := time.Now()
 	for {
 		select {
 		case <-close:
@@ -911,36 +1154,57 @@ func (p *MusicPlugin) play(vc *voiceConnection, close <-chan struct{}, control <
 			}
 		default:
 		}
-		select {
-		case ctl := <-control:
+		// Nothing is played while paused, so the next control message is waited for, which also lets it be resumed
+		// after a restart or seek that happened while it was paused.
+		var ctl controlMessage
+		received := false
+		if paused {
+			select {
+			case <-close:
+				return
+			case c, ok := <-control:
+				if !ok {
+					return
+				}
+				ctl, received = c, true
+			}
+		} else {
+			select {
+			case c, ok := <-control:
+				if !ok {
+					return
+				}
+				ctl, received = c, true
+			default:
+			}
+		}
+
+		if received {
 			switch ctl {
 			case Skip:
-				return
+				return false, true, false, elapsed(), 0
+			case Restart:
+				return true, false, paused, elapsed(), 0
+			case Seek:
+				return true, false, paused, vc.seekPosition(), 0
 			case Pause:
+				paused = true
 				stream.SetPaused(true)
-				b := false
-				for {
-					ctl, ok := <-control
-					if !ok {
-						return
-					}
-					switch ctl {
-					case Skip:
-						return
-					case Resume:
-						stream.SetPaused(false)
-						b = true
-						break
-					}
-					if b == true {
-						break
-					}
-				}
+			case Resume:
+				paused = false
+				stream.SetPaused(false)
 			}
-		default:
 		}
 
-		vc.playing.Remaining = (vc.playing.Duration - int(time.Since(start).Seconds()))
+		// Streams without an end, like radio, have no remaining time.
+		vc.Lock()
+		if vc.Playing != nil {
+			vc.Playing.Position = elapsed()
+			if vc.Playing.Duration > 0 {
+				vc.Playing.Remaining = (vc.Playing.Duration - int(vc.Playing.Position.Seconds()))
+			}
+		}
+		vc.Unlock()
 		time.Sleep(500 * time.Millisecond)
 	}
 }
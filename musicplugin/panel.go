@@ -0,0 +1,268 @@
+package musicplugin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ThyLeader/rikka"
+	"github.com/bwmarrin/discordgo"
+)
+
+// How often the now playing panel is updated.
+const panelInterval = 5 * time.Second
+
+// The number of songs shown as up next on the now playing panel.
+const panelUpNext = 3
+
+// The width of the progress bar, in characters.
+const progressWidth = 20
+
+// The reactions that control the music from the now playing panel.
+const (
+	reactionPause = "⏯"
+	reactionSkip  = "⏭"
+	reactionLoop  = "🔁"
+)
+
+// progressBar draws how far a song has played.
+func progressBar(position, duration time.Duration) string {
+	if duration <= 0 {
+		return strings.Repeat("▬", progressWidth)
+	}
+	i := int(int64(position) * progressWidth / int64(duration))
+	if i >= progressWidth {
+		i = progressWidth - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	return strings.Repeat("▬", i) + "🔘" + strings.Repeat("▬", progressWidth-1-i)
+}
+
+// onOff returns the translation of a setting being on or off.
+func onOff(bot *rikka.Bot, service rikka.Service, guildID string, on bool) string {
+	if on {
+		return bot.TranslateGuild(service, guildID, "", "music.panel.on")
+	}
+	return bot.TranslateGuild(service, guildID, "", "music.panel.off")
+}
+
+// panel returns the now playing panel of a voice connection, or nil if nothing is playing.
+func (p *MusicPlugin) panel(bot *rikka.Bot, service rikka.Service, vc *voiceConnection) *discordgo.MessageEmbed {
+	settings := p.settings(vc.GuildID)
+
+	vc.Lock()
+	defer vc.Unlock()
+
+	if vc.Playing == nil {
+		return nil
+	}
+	s := vc.Playing
+	t := func(key string, args ...interface{}) string {
+		return bot.TranslateGuild(service, vc.GuildID, "", key, args...)
+	}
+
+	state := "▶"
+	if vc.paused {
+		state = "⏸"
+	}
+	progress := formatTimestamp(s.Position)
+	if s.Duration > 0 {
+		progress += " / " + formatTimestamp(time.Duration(s.Duration)*time.Second)
+	} else {
+		progress += " / " + t("music.panel.live")
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       s.Title,
+		Description: fmt.Sprintf("%s %s `%s`", state, progressBar(s.Position, time.Duration(s.Duration)*time.Second), progress),
+		Color:       0x3498db,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: t("music.panel.requester"), Value: s.AddedBy, Inline: true},
+			{Name: t("music.panel.loop"), Value: onOff(bot, service, vc.GuildID, vc.Loop), Inline: true},
+			{Name: t("music.panel.repeat"), Value: onOff(bot, service, vc.GuildID, vc.Repeat), Inline: true},
+		},
+	}
+	if strings.HasPrefix(s.URL, "http") {
+		embed.URL = s.URL
+	}
+	if s.Thumbnail != "" {
+		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: s.Thumbnail}
+	}
+	if s.AddedBy == "" {
+		embed.Fields[0].Value = "-"
+	}
+	if settings.Filter != "" || settings.Volume != 100 {
+		filter := settings.Filter
+		if filter == "" {
+			filter = t("music.panel.off")
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: t("music.panel.sound"), Value: t("music.panel.sound.value", settings.Volume, filter), Inline: true})
+	}
+
+	upNext := []string{}
+	remaining := time.Duration(0)
+	for i, q := range vc.Queue {
+		if i < panelUpNext {
+			upNext = append(upNext, fmt.Sprintf("%d. %s", i+1, q.Title))
+		}
+		remaining += time.Duration(q.Duration) * time.Second
+	}
+	if len(upNext) == 0 {
+		upNext = append(upNext, t("music.panel.empty"))
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: t("music.panel.next"), Value: strings.Join(upNext, "\n")})
+	embed.Footer = &discordgo.MessageEmbedFooter{Text: rikka.LocalizePlural(bot.Language(service, vc.GuildID, ""), "music.panel.queue", len(vc.Queue), len(vc.Queue), remaining.String())}
+
+	return embed
+}
+
+// nowPlaying sends the now playing panel and keeps it updated, the panel it replaces stops being updated.
+func (p *MusicPlugin) nowPlaying(bot *rikka.Bot, vc *voiceConnection, service rikka.Service, message rikka.Message) {
+	embed := p.panel(bot, service, vc)
+	if embed == nil {
+		vc.Lock()
+		n := len(vc.Queue)
+		vc.Unlock()
+		service.SendMessage(message.Channel(), bot.TranslatePlural(service, message, "music.panel.nothing", n, n))
+		return
+	}
+
+	m, err := service.SendMessageEmbed(message.Channel(), embed)
+	if err != nil {
+		return
+	}
+
+	vc.Lock()
+	vc.panelMessageID = m.ID
+	vc.Unlock()
+
+	if p.settings(vc.GuildID).Controls {
+		session := p.discord.SessionForChannel(m.ChannelID)
+		for _, r := range []string{reactionPause, reactionSkip, reactionLoop} {
+			session.MessageReactionAdd(m.ChannelID, m.ID, r)
+		}
+	}
+
+	go p.updatePanel(bot, vc, service, m.ChannelID, m.ID)
+}
+
+// updatePanel updates a now playing panel until the song stops playing, the bot leaves, or a newer panel is sent.
+func (p *MusicPlugin) updatePanel(bot *rikka.Bot, vc *voiceConnection, service rikka.Service, channelID, messageID string) {
+	ticker := time.NewTicker(panelInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if !p.refreshPanel(bot, vc, service, channelID, messageID) {
+			return
+		}
+	}
+}
+
+// refreshPanel edits a now playing panel, it returns false if it shouldn't be updated anymore.
+func (p *MusicPlugin) refreshPanel(bot *rikka.Bot, vc *voiceConnection, service rikka.Service, channelID, messageID string) bool {
+	p.Lock()
+	current := p.VoiceConnections[vc.GuildID] == vc
+	p.Unlock()
+
+	vc.Lock()
+	current = current && vc.panelMessageID == messageID
+	vc.Unlock()
+	if !current {
+		return false
+	}
+
+	embed := p.panel(bot, service, vc)
+	stopped := embed == nil
+	if stopped {
+		// The last song finished, the panel says so instead of showing it forever.
+		embed = &discordgo.MessageEmbed{Description: bot.TranslateGuild(service, vc.GuildID, "", "music.panel.stopped")}
+	}
+	if _, err := service.EditMessageEmbed(channelID, messageID, embed); err != nil || stopped {
+		vc.Lock()
+		if vc.panelMessageID == messageID {
+			vc.panelMessageID = ""
+		}
+		vc.Unlock()
+		return false
+	}
+	return true
+}
+
+// onReactionAdd controls the music with the reactions on a now playing panel.
+func (p *MusicPlugin) onReactionAdd(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	defer rikka.MessageRecover()
+
+	if s.State.User == nil || r.UserID == s.State.User.ID {
+		return
+	}
+
+	p.Lock()
+	vcs := []*voiceConnection{}
+	for _, vc := range p.VoiceConnections {
+		vcs = append(vcs, vc)
+	}
+	p.Unlock()
+
+	var vc *voiceConnection
+	for _, v := range vcs {
+		v.Lock()
+		if v.panelMessageID == r.MessageID {
+			vc = v
+		}
+		v.Unlock()
+	}
+	if vc == nil || !p.settings(vc.GuildID).Controls {
+		return
+	}
+
+	// The reaction is removed, so it can be used again.
+	s.MessageReactionRemove(r.ChannelID, r.MessageID, r.Emoji.Name, r.UserID)
+
+	service := p.discord
+	message := &rikka.DiscordMessage{
+		Discord: p.discord,
+		DiscordgoMessage: &discordgo.Message{
+			ID:        r.MessageID,
+			ChannelID: r.ChannelID,
+			Author:    &discordgo.User{ID: r.UserID},
+		},
+		MessageType: rikka.MessageTypeCreate,
+	}
+
+	// Only listeners and DJs control the music.
+	if !p.listeners(vc)[r.UserID] && !p.isDJ(service, message, vc.GuildID) {
+		return
+	}
+
+	switch r.Emoji.Name {
+	case reactionPause:
+		vc.Lock()
+		vc.paused = !vc.paused
+		vc.autoPaused = false
+		c := Resume
+		if vc.paused {
+			c = Pause
+		}
+		vc.Unlock()
+		vc.send(c)
+
+	case reactionSkip:
+		p.skip(p.bot, vc, service, message)
+
+	case reactionLoop:
+		if !p.isDJ(service, message, vc.GuildID) {
+			return
+		}
+		vc.Lock()
+		vc.Repeat = false
+		vc.Loop = !vc.Loop
+		vc.Unlock()
+
+	default:
+		return
+	}
+
+	p.refreshPanel(p.bot, vc, service, r.ChannelID, r.MessageID)
+}
@@ -0,0 +1,38 @@
+package musicplugin
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressBar(t *testing.T) {
+	tests := []struct {
+		position, duration time.Duration
+		// knob is the index of the knob, or -1 if there is none.
+		knob int
+	}{
+		{0, time.Minute, 0},
+		{30 * time.Second, time.Minute, progressWidth / 2},
+		{time.Minute, time.Minute, progressWidth - 1},
+		{2 * time.Minute, time.Minute, progressWidth - 1},
+		{-time.Second, time.Minute, 0},
+		{30 * time.Second, 0, -1},
+	}
+
+	for _, test := range tests {
+		bar := []rune(progressBar(test.position, test.duration))
+		if len(bar) != progressWidth {
+			t.Errorf("progressBar(%v, %v) is %d characters, want %d", test.position, test.duration, len(bar), progressWidth)
+			continue
+		}
+		if knob := strings.IndexRune(string(bar), '🔘'); knob >= 0 {
+			knob = len([]rune(string(bar)[:knob]))
+			if knob != test.knob {
+				t.Errorf("progressBar(%v, %v) has its knob at %d, want %d", test.position, test.duration, knob, test.knob)
+			}
+		} else if test.knob != -1 {
+			t.Errorf("progressBar(%v, %v) has no knob", test.position, test.duration)
+		}
+	}
+}
@@ -0,0 +1,826 @@
+package musicplugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ThyLeader/rikka"
+	"github.com/go-redis/redis"
+)
+
+// ErrPlaylistFormat is returned when an imported playlist has no entries.
+var ErrPlaylistFormat = errors.New("no songs found")
+
+// The most playlists a user or guild can have.
+const maxPlaylists = 25
+
+// The most songs a playlist can have.
+const maxPlaylistSongs = 500
+
+// The longest a playlist name can be.
+const maxPlaylistName = 32
+
+// The largest playlist file that is imported.
+const maxPlaylistFile = 1 << 20
+
+// The most entries of an imported playlist that are looked up with youtube-dl, entries with #EXTINF information aren't looked up.
+const maxImportLookups = 50
+
+// imports limits how many playlists are imported at the same time.
+var imports = make(chan struct{}, 2)
+
+// The number of songs shown by playlist show.
+const playlistShowSize = 20
+
+// userPlaylistsKey is the Redis hash of the playlists of users, by user ID.
+const userPlaylistsKey = "music:playlists"
+
+var client = redis.NewClient(&redis.Options{
+	Addr:     "localhost:6379",
+	Password: "",
+	DB:       0,
+})
+
+var playlistMentionRegexp = regexp.MustCompile(`^<@!?([0-9]+)>$`)
+
+// A playlist is a saved list of songs, the songs keep their metadata so loading them doesn't resolve them again.
+type playlist struct {
+	Name string
+	// Shared playlists of users can be loaded by others, guild playlists are always shared in their guild.
+	Shared  bool
+	Songs   []song
+	Updated time.Time
+}
+
+func (pl *playlist) duration() time.Duration {
+	d := 0
+	for _, s := range pl.Songs {
+		d += s.Duration
+	}
+	return time.Duration(d) * time.Second
+}
+
+// playlistRef is the owner and name of a playlist, as given in a playlist command.
+type playlistRef struct {
+	guild bool
+	owner string
+	// other is whether the playlist belongs to another user.
+	other bool
+	name  string
+}
+
+// playlists returns the playlists of a user or guild, changes to them are saved with storePlaylists. The lock must be held.
+// The playlists of users are stored in Redis, because users can load them in guilds that are run by other processes.
+func (p *MusicPlugin) playlists(guild bool, owner string) (map[string]*playlist, error) {
+	if guild {
+		if p.GuildPlaylists[owner] == nil {
+			p.GuildPlaylists[owner] = map[string]*playlist{}
+		}
+		return p.GuildPlaylists[owner], nil
+	}
+
+	playlists := map[string]*playlist{}
+	b, err := client.HGet(userPlaylistsKey, owner).Bytes()
+	if err == redis.Nil {
+		return playlists, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &playlists); err != nil {
+		return nil, err
+	}
+	return playlists, nil
+}
+
+// storePlaylists saves the playlists of a user or guild after they were changed. The lock must be held.
+func (p *MusicPlugin) storePlaylists(guild bool, owner string, playlists map[string]*playlist) error {
+	if guild {
+		return nil
+	}
+	if len(playlists) == 0 {
+		return client.HDel(userPlaylistsKey, owner).Err()
+	}
+	b, err := json.Marshal(playlists)
+	if err != nil {
+		return err
+	}
+	return client.HSet(userPlaylistsKey, owner, string(b)).Err()
+}
+
+// migratePlaylists moves the playlists of users from older saves into Redis.
+func (p *MusicPlugin) migratePlaylists() {
+	for owner, playlists := range p.UserPlaylists {
+		b, err := json.Marshal(playlists)
+		if err != nil {
+			continue
+		}
+		if err := client.HSetNX(userPlaylistsKey, owner, string(b)).Err(); err != nil {
+			log.Println("musicplugin: migrating playlists err:", err)
+			continue
+		}
+		delete(p.UserPlaylists, owner)
+	}
+}
+
+// playlist returns a copy of a playlist, or nil if it doesn't exist.
+func (p *MusicPlugin) playlist(ref playlistRef) *playlist {
+	p.Lock()
+	defer p.Unlock()
+
+	playlists, err := p.playlists(ref.guild, ref.owner)
+	if err != nil {
+		log.Println("musicplugin: loading playlists err:", err)
+		return nil
+	}
+	pl := playlists[strings.ToLower(ref.name)]
+	if pl == nil || (ref.other && !pl.Shared) {
+		return nil
+	}
+	c := *pl
+	c.Songs = append([]song{}, pl.Songs...)
+	return &c
+}
+
+// savePlaylist saves a playlist, replacing any playlist with the same name.
+func (p *MusicPlugin) savePlaylist(ref playlistRef, songs []song) error {
+	p.Lock()
+	defer p.Unlock()
+
+	playlists, err := p.playlists(ref.guild, ref.owner)
+	if err != nil {
+		return err
+	}
+	key := strings.ToLower(ref.name)
+	pl := playlists[key]
+	if pl == nil {
+		if len(playlists) >= maxPlaylists {
+			return errTooManyPlaylists
+		}
+		pl = &playlist{}
+		playlists[key] = pl
+	}
+
+	pl.Name = ref.name
+	pl.Songs = make([]song, len(songs))
+	for i, s := range songs {
+		// Who queued a song and where is not part of the playlist.
+		s.TextChannelID = ""
+		s.AddedBy = ""
+		s.AddedByID = ""
+		s.Remaining = 0
+		pl.Songs[i] = s
+	}
+	pl.Updated = time.Now()
+	return p.storePlaylists(ref.guild, ref.owner, playlists)
+}
+
+var errTooManyPlaylists = errors.New("too many playlists")
+
+// parsePlaylistRef parses the owner and name of a playlist, eg. "name", "guild name" or "@user name".
+// It returns the number of parts that were used.
+func parsePlaylistRef(message rikka.Message, guildID string, parts []string) (playlistRef, int) {
+	ref := playlistRef{owner: message.UserID()}
+	n := 0
+	if len(parts) > 0 {
+		if strings.ToLower(parts[0]) == "guild" || strings.ToLower(parts[0]) == "server" {
+			ref.guild, ref.owner = true, guildID
+			n++
+		} else if m := playlistMentionRegexp.FindStringSubmatch(parts[0]); m != nil {
+			ref.owner, ref.other = m[1], m[1] != message.UserID()
+			n++
+		}
+	}
+	if n < len(parts) {
+		ref.name = parts[n]
+		n++
+	}
+	return ref, n
+}
+
+func validPlaylistName(name string) bool {
+	return name != "" && len(name) <= maxPlaylistName && !strings.ContainsAny(name, "`*_~|/\\")
+}
+
+// add adds songs to the queue for a user, load replaces the songs that are waiting to be played.
+// Only the songs that fit in the limits of the guild are added.
+// It returns the number of songs that were added, the length of the queue, and the message of the limit that was hit, if any.
+func (vc *voiceConnection) add(songs []song, load bool, userID string, limits guildSettings) (int, int, string) {
+	vc.Lock()
+	if load {
+		vc.Queue = []song{}
+	}
+	room, key := vc.room(userID, limits)
+	if len(songs) > room {
+		songs = songs[:room]
+	} else {
+		key = ""
+	}
+	vc.Queue = append(vc.Queue, songs...)
+	n := len(vc.Queue)
+	vc.Unlock()
+
+	vc.notify()
+	return len(songs), n, key
+}
+
+// playlistCommand handles "music playlist <command> [guild|@user] <name> ...".
+// The parts are raw, so other users' playlists can be referred to by mention.
+func (p *MusicPlugin) playlistCommand(bot *rikka.Bot, vc *voiceConnection, service rikka.Service, message rikka.Message, guildID string, parts []string) {
+	if len(parts) == 0 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.usage", service.CommandPrefix()))
+		return
+	}
+
+	command := strings.ToLower(parts[0])
+	ref, n := parsePlaylistRef(message, guildID, parts[1:])
+	args := parts[1+n:]
+
+	if command == "list" || command == "ls" {
+		p.listPlaylists(bot, service, message, guildID, ref)
+		return
+	}
+
+	if !validPlaylistName(ref.name) {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.name", maxPlaylistName))
+		return
+	}
+
+	switch command {
+	case "save", "import", "rename", "delete", "remove", "share", "unshare":
+		// Users can only change their own playlists, and moderators the playlists of their guild.
+		if ref.other {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.other"))
+			return
+		}
+		if ref.guild && !service.IsModerator(message) {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "error.moderator"))
+			return
+		}
+	}
+
+	switch command {
+	case "save":
+		if vc == nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.novoice"))
+			return
+		}
+		vc.Lock()
+		songs := []song{}
+		if vc.Playing != nil {
+			s := *vc.Playing
+			s.Position = 0
+			songs = append(songs, s)
+		}
+		songs = append(songs, vc.Queue...)
+		vc.Unlock()
+		if len(songs) == 0 {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.nothing"))
+			return
+		}
+		p.save(bot, service, message, ref, songs)
+
+	case "import":
+		// Entries can take a while to resolve, so the playlist is imported in the background.
+		go p.importCommand(bot, service, message, guildID, ref, args)
+
+	case "load", "append", "play":
+		if vc == nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.novoice"))
+			return
+		}
+		// Loading a playlist replaces the queue, so it is a DJ command.
+		if command == "load" && !p.isDJ(service, message, guildID) {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.dj.required", p.roleName(guildID, p.settings(guildID).DJRole)))
+			return
+		}
+		pl := p.playlist(ref)
+		if pl == nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.notfound", ref.name))
+			return
+		}
+
+		limits := p.settings(guildID)
+		songs := []song{}
+		tooLong := 0
+		for _, s := range pl.Songs {
+			if s.Duration > limits.MaxDuration {
+				tooLong++
+				continue
+			}
+			s.TextChannelID = message.Channel()
+			s.AddedBy = message.UserName()
+			s.AddedByID = message.UserID()
+			songs = append(songs, s)
+		}
+		p.gostart(vc, service)
+		added, l, limit := vc.add(songs, command == "load", message.UserID(), limits)
+		songsAdded += added
+
+		key := "music.playlist.appended"
+		if command == "load" {
+			key = "music.playlist.loaded"
+		}
+		msg := bot.Translate(service, message, key, added, pl.Name, l)
+		if tooLong > 0 {
+			msg += "\n" + bot.TranslatePlural(service, message, "music.limit.toolong", tooLong, tooLong, formatTimestamp(time.Duration(limits.MaxDuration)*time.Second))
+		}
+		if limit != "" {
+			msg += "\n" + p.limitMessage(bot, service, message, limits, limit)
+		}
+		service.SendMessage(message.Channel(), msg)
+
+	case "show", "info":
+		pl := p.playlist(ref)
+		if pl == nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.notfound", ref.name))
+			return
+		}
+		lines := []string{bot.Translate(service, message, "music.playlist.show", pl.Name, len(pl.Songs), pl.duration().String())}
+		for i, s := range pl.Songs {
+			if i == playlistShowSize {
+				lines = append(lines, bot.Translate(service, message, "music.library.more", len(pl.Songs)-i))
+				break
+			}
+			lines = append(lines, fmt.Sprintf("`%.3d` **%s** [%s]", i+1, s.Title, (time.Duration(s.Duration)*time.Second).String()))
+		}
+		service.SendMessage(message.Channel(), strings.Join(lines, "\n"))
+
+	case "export":
+		pl := p.playlist(ref)
+		if pl == nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.notfound", ref.name))
+			return
+		}
+		if err := service.SendFile(message.Channel(), pl.Name+".m3u", strings.NewReader(exportPlaylist(pl))); err != nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+		}
+
+	case "rename":
+		if len(args) != 1 || !validPlaylistName(args[0]) {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.name", maxPlaylistName))
+			return
+		}
+		p.Lock()
+		playlists, err := p.playlists(ref.guild, ref.owner)
+		if err != nil {
+			p.Unlock()
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+			return
+		}
+		pl := playlists[strings.ToLower(ref.name)]
+		other := playlists[strings.ToLower(args[0])]
+		switch {
+		case pl == nil:
+			p.Unlock()
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.notfound", ref.name))
+			return
+		case other != nil && other != pl:
+			p.Unlock()
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.exists", args[0]))
+			return
+		}
+		delete(playlists, strings.ToLower(ref.name))
+		pl.Name = args[0]
+		pl.Updated = time.Now()
+		playlists[strings.ToLower(pl.Name)] = pl
+		err = p.storePlaylists(ref.guild, ref.owner, playlists)
+		p.Unlock()
+		if err != nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+			return
+		}
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.renamed", ref.name, args[0]))
+
+	case "delete", "remove":
+		p.Lock()
+		playlists, err := p.playlists(ref.guild, ref.owner)
+		var pl *playlist
+		if err == nil {
+			pl = playlists[strings.ToLower(ref.name)]
+			delete(playlists, strings.ToLower(ref.name))
+			if pl != nil {
+				err = p.storePlaylists(ref.guild, ref.owner, playlists)
+			}
+		}
+		p.Unlock()
+		if err != nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+			return
+		}
+		if pl == nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.notfound", ref.name))
+			return
+		}
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.deleted", pl.Name))
+
+	case "share", "unshare":
+		if ref.guild {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.share.guild"))
+			return
+		}
+		p.Lock()
+		playlists, err := p.playlists(ref.guild, ref.owner)
+		var pl *playlist
+		if err == nil {
+			if pl = playlists[strings.ToLower(ref.name)]; pl != nil {
+				pl.Shared = command == "share"
+				err = p.storePlaylists(ref.guild, ref.owner, playlists)
+			}
+		}
+		p.Unlock()
+		if err != nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+			return
+		}
+		if pl == nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.notfound", ref.name))
+			return
+		}
+		if command == "share" {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.share.on", pl.Name, service.CommandPrefix(), message.UserID(), pl.Name))
+			return
+		}
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.share.off", pl.Name))
+
+	default:
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.usage", service.CommandPrefix()))
+	}
+}
+
+// save saves songs as a playlist and reports it, it returns false if the playlist couldn't be saved.
+func (p *MusicPlugin) save(bot *rikka.Bot, service rikka.Service, message rikka.Message, ref playlistRef, songs []song) bool {
+	truncated := len(songs) > maxPlaylistSongs
+	if truncated {
+		songs = songs[:maxPlaylistSongs]
+	}
+
+	if err := p.savePlaylist(ref, songs); err == errTooManyPlaylists {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.toomany", maxPlaylists))
+		return false
+	} else if err != nil {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+		return false
+	}
+
+	msg := bot.Translate(service, message, "music.playlist.saved", len(songs), ref.name)
+	if truncated {
+		msg += " " + bot.Translate(service, message, "music.playlist.truncated", maxPlaylistSongs)
+	}
+	service.SendMessage(message.Channel(), msg)
+	return true
+}
+
+// listPlaylists lists the playlists of the sender and the guild, or the shared playlists of another user.
+func (p *MusicPlugin) listPlaylists(bot *rikka.Bot, service rikka.Service, message rikka.Message, guildID string, ref playlistRef) {
+	type section struct {
+		title     string
+		guild     bool
+		owner     string
+		onlyShare bool
+	}
+	sections := []section{
+		{bot.Translate(service, message, "music.playlist.list.user"), false, message.UserID(), false},
+		{bot.Translate(service, message, "music.playlist.list.guild"), true, guildID, false},
+	}
+	if ref.guild {
+		sections = sections[1:]
+	} else if ref.other {
+		sections = []section{{bot.Translate(service, message, "music.playlist.list.other", ref.owner), false, ref.owner, true}}
+	}
+
+	lines := []string{}
+	p.Lock()
+	for _, s := range sections {
+		playlists, err := p.playlists(s.guild, s.owner)
+		if err != nil {
+			log.Println("musicplugin: loading playlists err:", err)
+			continue
+		}
+		names := []string{}
+		for _, pl := range playlists {
+			if s.onlyShare && !pl.Shared {
+				continue
+			}
+			shared := ""
+			if pl.Shared {
+				shared = bot.Translate(service, message, "music.playlist.shared")
+			}
+			names = append(names, bot.Translate(service, message, "music.playlist.line", pl.Name, len(pl.Songs), pl.duration().String(), shared))
+		}
+		if len(names) == 0 {
+			continue
+		}
+		sort.Strings(names)
+		lines = append(lines, s.title)
+		lines = append(lines, names...)
+	}
+	p.Unlock()
+
+	if len(lines) == 0 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.none"))
+		return
+	}
+	service.SendMessage(message.Channel(), strings.Join(lines, "\n"))
+}
+
+// A playlistEntry is a line of an M3U or plain text playlist, with its #EXTINF information if it had any.
+type playlistEntry struct {
+	Location string
+	Title    string
+	Duration int
+}
+
+// parsePlaylist reads an M3U playlist, or a plain list of URLs and library paths with one per line.
+func parsePlaylist(r io.Reader) []playlistEntry {
+	entries := []playlistEntry{}
+	var info playlistEntry
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "#EXTINF:"):
+			// #EXTINF:<seconds>,<title>
+			info = playlistEntry{}
+			fields := strings.SplitN(strings.TrimPrefix(line, "#EXTINF:"), ",", 2)
+			if f := strings.Fields(fields[0]); len(f) > 0 {
+				if d, err := strconv.Atoi(f[0]); err == nil && d > 0 {
+					info.Duration = d
+				}
+			}
+			if len(fields) == 2 {
+				info.Title = strings.TrimSpace(fields[1])
+			}
+		case strings.HasPrefix(line, "#"):
+		default:
+			info.Location = line
+			entries = append(entries, info)
+			info = playlistEntry{}
+		}
+	}
+	return entries
+}
+
+// exportPlaylist writes a playlist as an extended M3U playlist.
+func exportPlaylist(pl *playlist) string {
+	lines := []string{"#EXTM3U"}
+	for _, s := range pl.Songs {
+		duration := s.Duration
+		if duration == 0 {
+			duration = -1
+		}
+		lines = append(lines, fmt.Sprintf("#EXTINF:%d,%s", duration, s.Title), s.URL)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// importCommand imports a playlist and saves it.
+func (p *MusicPlugin) importCommand(bot *rikka.Bot, service rikka.Service, message rikka.Message, guildID string, ref playlistRef, args []string) {
+	defer rikka.MessageRecover()
+
+	service.Typing(message.Channel())
+	entries, err := p.fetchPlaylist(message, args)
+	if err != nil {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.import.error", err.Error()))
+		return
+	}
+	service.SendMessage(message.Channel(), bot.TranslatePlural(service, message, "music.playlist.reading", len(entries), len(entries)))
+
+	imports <- struct{}{}
+	res := p.resolveEntries(entries, p.settings(guildID).MaxPlaylist)
+	<-imports
+
+	if len(res.songs) == 0 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.import.error", ErrPlaylistFormat.Error()))
+		return
+	}
+	if !p.save(bot, service, message, ref, res.songs) {
+		return
+	}
+	if res.skipped > 0 {
+		service.SendMessage(message.Channel(), bot.TranslatePlural(service, message, "music.playlist.skipped", res.skipped, res.skipped))
+	}
+	if res.unresolved > 0 {
+		service.SendMessage(message.Channel(), bot.TranslatePlural(service, message, "music.playlist.lookups", res.unresolved, res.unresolved, maxImportLookups))
+	}
+	if res.truncated {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.playlist.truncated", p.settings(guildID).MaxPlaylist))
+	}
+}
+
+// fetchPlaylist reads the entries of a playlist from a file attached to a message, or from an http or https URL.
+func (p *MusicPlugin) fetchPlaylist(message rikka.Message, args []string) ([]playlistEntry, error) {
+	location := ""
+	if m, ok := message.(*rikka.DiscordMessage); ok {
+		for _, a := range m.DiscordgoMessage.Attachments {
+			switch strings.ToLower(path.Ext(a.Filename)) {
+			case ".m3u", ".m3u8", ".txt":
+				location = a.URL
+			}
+		}
+	}
+	if location == "" && len(args) > 0 {
+		location = args[0]
+	}
+	if location == "" {
+		return nil, ErrPlaylistFormat
+	}
+
+	u, err := publicURL(location)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := publicClient.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+
+	entries := parsePlaylist(io.LimitReader(resp.Body, maxPlaylistFile))
+	if len(entries) == 0 {
+		return nil, ErrPlaylistFormat
+	}
+	return entries, nil
+}
+
+// An importResult is the songs of an imported playlist.
+type importResult struct {
+	songs []song
+	// skipped is the number of entries that couldn't be read, and unresolved the number that weren't looked up
+	// because the import already looked up maxImportLookups entries.
+	skipped    int
+	unresolved int
+	// truncated is whether songs were left out because of the playlist limit of the guild.
+	truncated bool
+}
+
+// resolveEntries returns the songs of the entries of a playlist, up to limit songs.
+func (p *MusicPlugin) resolveEntries(entries []playlistEntry, limit int) importResult {
+	res := importResult{}
+	lookups := 0
+	for _, e := range entries {
+		if len(res.songs) >= limit {
+			res.truncated = true
+			break
+		}
+		if needsLookup(e) {
+			if lookups == maxImportLookups {
+				res.unresolved++
+				continue
+			}
+			lookups++
+		}
+		s, err := p.resolveEntry(e)
+		if err != nil {
+			res.skipped++
+			continue
+		}
+		res.songs = append(res.songs, s...)
+	}
+	// An entry can be a playlist of many songs.
+	if len(res.songs) > limit {
+		res.songs = res.songs[:limit]
+		res.truncated = true
+	}
+	return res
+}
+
+// needsLookup returns whether a playlist entry has to be looked up with youtube-dl.
+func needsLookup(e playlistEntry) bool {
+	u, err := url.ParseRequestURI(e.Location)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && !isAudioFile(u.Path) && e.Title == ""
+}
+
+// resolveEntry returns the songs of a playlist entry.
+// Entries with #EXTINF information are not resolved again, others are read with the youtube-dl resolver.
+func (p *MusicPlugin) resolveEntry(e playlistEntry) ([]song, error) {
+	u, err := url.ParseRequestURI(e.Location)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		// Anything that isn't a URL is a path in the music library.
+		return fileResolver{p}.Resolve(e.Location, false)
+	}
+
+	// The songs are played from the URLs later, so they can't point into the network the bot runs in either.
+	if _, err := publicURL(u.String()); err != nil {
+		return nil, err
+	}
+
+	if isAudioFile(u.Path) {
+		s := song{
+			ID:       path.Base(u.Path),
+			Title:    e.Title,
+			URL:      u.String(),
+			Duration: e.Duration,
+			Source:   sourceURL,
+		}
+		if s.Title == "" {
+			s.Title = s.ID
+		}
+		return []song{s}, nil
+	}
+
+	if e.Title != "" {
+		return []song{{
+			Title:    e.Title,
+			URL:      u.String(),
+			Duration: e.Duration,
+		}}, nil
+	}
+	return youtubeDLResolver{binary: p.config.YoutubeDL}.Resolve(u.String(), false)
+}
+
+var (
+	errPlaylistURL    = errors.New("only http and https URLs can be imported")
+	errPrivateAddress = errors.New("private and internal addresses can't be imported")
+)
+
+// privateNetworks are the networks songs and playlists can't be played or imported from, so users can't make the bot reach the network it runs in.
+var privateNetworks = func() []*net.IPNet {
+	networks := []*net.IPNet{}
+	for _, cidr := range []string{
+		"0.0.0.0/8",
+		"10.0.0.0/8",
+		"100.64.0.0/10",
+		"127.0.0.0/8",
+		"169.254.0.0/16",
+		"172.16.0.0/12",
+		"192.168.0.0/16",
+		"::1/128",
+		"fc00::/7",
+		"fe80::/10",
+	} {
+		_, n, _ := net.ParseCIDR(cidr)
+		networks = append(networks, n)
+	}
+	return networks
+}()
+
+// publicIP returns whether an address is on the internet.
+func publicIP(ip net.IP) bool {
+	if ip.IsUnspecified() || ip.IsMulticast() {
+		return false
+	}
+	for _, n := range privateNetworks {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// publicURL parses an http or https URL, and returns an error if its host has a private or internal address.
+func publicURL(location string) (*url.URL, error) {
+	u, err := url.Parse(location)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return nil, errPlaylistURL
+	}
+	ips, err := net.LookupIP(u.Hostname())
+	if err != nil {
+		return nil, err
+	}
+	for _, ip := range ips {
+		if !publicIP(ip) {
+			return nil, errPrivateAddress
+		}
+	}
+	return u, nil
+}
+
+// publicClient downloads playlists and checks streams, it checks every address it connects to so redirects can't reach private addresses either.
+var publicClient = &http.Client{
+	Timeout: 30 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 10 * time.Second,
+			Control: func(network, address string, c syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+				if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+					return errPrivateAddress
+				}
+				return nil
+			},
+		}).DialContext,
+	},
+}
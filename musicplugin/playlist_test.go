@@ -0,0 +1,106 @@
+package musicplugin
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePlaylist(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		entries []playlistEntry
+	}{
+		{
+			"plain",
+			"https://example.com/a\n\nhttps://example.com/b\n",
+			[]playlistEntry{{Location: "https://example.com/a"}, {Location: "https://example.com/b"}},
+		},
+		{
+			"extended",
+			"\ufeff#EXTM3U\n#EXTINF:123,Artist - Title\nhttps://example.com/a\n# comment\nsongs/b.mp3\n",
+			[]playlistEntry{{Location: "https://example.com/a", Title: "Artist - Title", Duration: 123}, {Location: "songs/b.mp3"}},
+		},
+		{
+			"unknown duration",
+			"#EXTINF:-1,Radio\nhttp://example.com/stream\n",
+			[]playlistEntry{{Location: "http://example.com/stream", Title: "Radio"}},
+		},
+		{
+			"info without entry",
+			"#EXTM3U\n#EXTINF:10,Nothing\n",
+			[]playlistEntry{},
+		},
+	}
+
+	for _, test := range tests {
+		entries := parsePlaylist(strings.NewReader(test.content))
+		if !reflect.DeepEqual(entries, test.entries) {
+			t.Errorf("%s: parsePlaylist = %+v, want %+v", test.name, entries, test.entries)
+		}
+	}
+}
+
+func TestExportPlaylist(t *testing.T) {
+	pl := &playlist{Songs: []song{
+		{Title: "First", URL: "https://example.com/a", Duration: 60},
+		{Title: "Radio", URL: "http://example.com/stream"},
+	}}
+
+	exported := exportPlaylist(pl)
+	want := "#EXTM3U\n#EXTINF:60,First\nhttps://example.com/a\n#EXTINF:-1,Radio\nhttp://example.com/stream\n"
+	if exported != want {
+		t.Errorf("exportPlaylist = %q, want %q", exported, want)
+	}
+
+	// An exported playlist is imported without looking its songs up again.
+	entries := parsePlaylist(strings.NewReader(exported))
+	if len(entries) != len(pl.Songs) {
+		t.Fatalf("parsePlaylist read %d entries, want %d", len(entries), len(pl.Songs))
+	}
+	for i, e := range entries {
+		s := pl.Songs[i]
+		if e.Location != s.URL || e.Title != s.Title || e.Duration != s.Duration || needsLookup(e) {
+			t.Errorf("entry %d = %+v, want the song %+v", i, e, s)
+		}
+	}
+}
+
+func TestPublicIP(t *testing.T) {
+	tests := []struct {
+		ip     string
+		public bool
+	}{
+		{"8.8.8.8", true},
+		{"2606:4700::1111", true},
+		{"127.0.0.1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::1", false},
+		{"fd00::1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, test := range tests {
+		if public := publicIP(net.ParseIP(test.ip)); public != test.public {
+			t.Errorf("publicIP(%s) = %v, want %v", test.ip, public, test.public)
+		}
+	}
+}
+
+func TestPublicURLScheme(t *testing.T) {
+	for _, location := range []string{"file:///etc/passwd", "ftp://example.com/a.m3u", "example.com/a.m3u", "http://"} {
+		if _, err := publicURL(location); err != errPlaylistURL {
+			t.Errorf("publicURL(%q) returned %v, want errPlaylistURL", location, err)
+		}
+	}
+	if _, err := publicURL("http://127.0.0.1/a.m3u"); err != errPrivateAddress {
+		t.Errorf("publicURL of a loopback address returned %v, want errPrivateAddress", err)
+	}
+}
@@ -0,0 +1,197 @@
+package musicplugin
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/ThyLeader/rikka"
+)
+
+// parsePosition parses a 1-based position in a queue of n songs.
+func parsePosition(s string, n int) (int, bool) {
+	i, err := strconv.Atoi(s)
+	return i, err == nil && i >= 1 && i <= n
+}
+
+// parseRange parses a position or a range of positions like "3-7" in a queue of n songs.
+func parseRange(s string, n int) (int, int, bool) {
+	parts := strings.SplitN(s, "-", 2)
+	from, ok := parsePosition(parts[0], n)
+	if !ok {
+		return 0, 0, false
+	}
+	if len(parts) == 1 {
+		return from, from, true
+	}
+	to, ok := parsePosition(parts[1], n)
+	if !ok || to < from {
+		return 0, 0, false
+	}
+	return from, to, true
+}
+
+// removeIf removes the waiting songs that match, it returns the number of songs that were removed.
+// The lock must be held.
+func (vc *voiceConnection) removeIf(match func(i int, s song) bool) int {
+	queue := vc.Queue[:0]
+	removed := 0
+	for i, s := range vc.Queue {
+		if match(i, s) {
+			removed++
+			continue
+		}
+		queue = append(queue, s)
+	}
+	vc.Queue = queue
+	return removed
+}
+
+// queueCommand handles the commands that change the waiting songs: remove, move, shuffle, skipto, dedupe and removeabsent.
+// It returns whether the song that is playing should be skipped.
+func (p *MusicPlugin) queueCommand(bot *rikka.Bot, vc *voiceConnection, service rikka.Service, message rikka.Message, parts []string) bool {
+	// The reply is sent once the queue is unlocked, as sending can wait for the rate limit of the channel.
+	reply, skip := p.editQueue(bot, vc, service, message, parts[0], parts[1:])
+	if reply != "" {
+		service.SendMessage(message.Channel(), reply)
+	}
+	return skip
+}
+
+// editQueue changes the queue, and returns the reply and whether the song that is playing should be skipped.
+func (p *MusicPlugin) editQueue(bot *rikka.Bot, vc *voiceConnection, service rikka.Service, message rikka.Message, command string, args []string) (reply string, skip bool) {
+	// The users in the voice channel are looked up before the queue is locked.
+	var listeners map[string]bool
+	if command == "removeabsent" {
+		listeners = p.listeners(vc)
+	}
+
+	vc.Lock()
+	defer vc.Unlock()
+
+	n := len(vc.Queue)
+
+	switch command {
+	case "remove", "rm":
+		if len(args) == 0 {
+			return bot.Translate(service, message, "music.queue.remove.usage", service.CommandPrefix()), false
+		}
+
+		// Songs added by mentioned users.
+		if mentions := message.Mentions(); len(mentions) > 0 && strings.HasPrefix(args[0], "@") {
+			users := map[string]bool{}
+			for _, u := range mentions {
+				// The bot is mentioned when it is used as the command prefix.
+				if u.ID != service.UserID() {
+					users[u.ID] = true
+				}
+			}
+			removed := vc.removeIf(func(i int, s song) bool {
+				return users[s.AddedByID]
+			})
+			return bot.TranslatePlural(service, message, "music.queue.removed", removed, removed), false
+		}
+
+		from, to, ok := parseRange(args[0], n)
+		if !ok {
+			return bot.Translate(service, message, "music.queue.position", args[0], n), false
+		}
+		title := vc.Queue[from-1].Title
+		removed := vc.removeIf(func(i int, s song) bool {
+			return i >= from-1 && i <= to-1
+		})
+		if removed == 1 {
+			return bot.Translate(service, message, "music.queue.removed.song", title), false
+		}
+		reply = bot.TranslatePlural(service, message, "music.queue.removed", removed, removed)
+
+	case "move", "mv":
+		if len(args) != 2 {
+			return bot.Translate(service, message, "music.queue.move.usage", service.CommandPrefix()), false
+		}
+		from, ok := parsePosition(args[0], n)
+		if !ok {
+			return bot.Translate(service, message, "music.queue.position", args[0], n), false
+		}
+		to, ok := parsePosition(args[1], n)
+		if !ok {
+			return bot.Translate(service, message, "music.queue.position", args[1], n), false
+		}
+
+		s := vc.Queue[from-1]
+		vc.Queue = append(vc.Queue[:from-1], vc.Queue[from:]...)
+		vc.Queue = append(vc.Queue[:to-1], append([]song{s}, vc.Queue[to-1:]...)...)
+		reply = bot.Translate(service, message, "music.queue.moved", s.Title, to)
+
+	case "shuffle":
+		queue := make([]song, n)
+		for i, j := range rand.Perm(n) {
+			queue[i] = vc.Queue[j]
+		}
+		vc.Queue = queue
+		reply = bot.TranslatePlural(service, message, "music.queue.shuffled", n, n)
+
+	case "skipto":
+		if len(args) != 1 {
+			return bot.Translate(service, message, "music.queue.skipto.usage", service.CommandPrefix()), false
+		}
+		to, ok := parsePosition(args[0], n)
+		if !ok {
+			return bot.Translate(service, message, "music.queue.position", args[0], n), false
+		}
+
+		skipped := append([]song{}, vc.Queue[:to-1]...)
+		vc.Queue = vc.Queue[to-1:]
+		if vc.Loop {
+			// A looping queue keeps the songs that were skipped, at its end after the song that is playing.
+			if vc.Playing != nil {
+				vc.skipped = append(vc.skipped, skipped...)
+			} else {
+				vc.Queue = append(vc.Queue, skipped...)
+			}
+		}
+		return bot.Translate(service, message, "music.queue.skipto", vc.Queue[0].Title), vc.Playing != nil
+
+	case "dedupe":
+		seen := map[string]bool{}
+		if vc.Playing != nil {
+			seen[vc.Playing.Source+vc.Playing.URL] = true
+		}
+		removed := vc.removeIf(func(i int, s song) bool {
+			key := s.Source + s.URL
+			if seen[key] {
+				return true
+			}
+			seen[key] = true
+			return false
+		})
+		reply = bot.TranslatePlural(service, message, "music.queue.deduped", removed, removed)
+
+	case "removeabsent":
+		if listeners == nil {
+			return bot.Translate(service, message, "music.novoice"), false
+		}
+		removed := vc.removeIf(func(i int, s song) bool {
+			// Songs from before requesters were recorded are kept.
+			return s.AddedByID != "" && !listeners[s.AddedByID]
+		})
+		reply = bot.TranslatePlural(service, message, "music.queue.absent", removed, removed)
+	}
+	return reply, false
+}
+
+// listeners returns the users in the voice channel of a voice connection, or nil if its guild isn't known.
+func (p *MusicPlugin) listeners(vc *voiceConnection) map[string]bool {
+	g, err := p.discord.Guild(vc.GuildID)
+	if err != nil {
+		return nil
+	}
+
+	listeners := map[string]bool{}
+	for _, v := range g.VoiceStates {
+		if v.ChannelID == vc.ChannelID {
+			listeners[v.UserID] = true
+		}
+	}
+	return listeners
+}
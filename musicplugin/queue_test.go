@@ -0,0 +1,121 @@
+package musicplugin
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ThyLeader/rikka"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		s        string
+		n        int
+		from, to int
+		ok       bool
+	}{
+		{"1", 5, 1, 1, true},
+		{"5", 5, 5, 5, true},
+		{"2-4", 5, 2, 4, true},
+		{"3-3", 5, 3, 3, true},
+		{"0", 5, 0, 0, false},
+		{"6", 5, 0, 0, false},
+		{"4-2", 5, 0, 0, false},
+		{"2-6", 5, 0, 0, false},
+		{"a", 5, 0, 0, false},
+		{"1-", 5, 0, 0, false},
+		{"1", 0, 0, 0, false},
+	}
+
+	for _, test := range tests {
+		from, to, ok := parseRange(test.s, test.n)
+		if from != test.from || to != test.to || ok != test.ok {
+			t.Errorf("parseRange(%q, %d) = %d, %d, %v, want %d, %d, %v", test.s, test.n, from, to, ok, test.from, test.to, test.ok)
+		}
+	}
+}
+
+// fakeSongs resolves songs with the fake resolver, as if they were added by a user.
+func fakeSongs(t *testing.T, userID string, n int) []song {
+	r := &FakeResolver{}
+	songs := []song{}
+	for i := 0; i < n; i++ {
+		s, err := r.Resolve(fmt.Sprintf("%s %d", userID, i), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s[0].AddedByID = userID
+		songs = append(songs, s[0])
+	}
+	return songs
+}
+
+func TestQueueAdd(t *testing.T) {
+	vc := &voiceConnection{}
+	limits := guildSettings{MaxQueue: 5, MaxShare: 100}
+
+	added, n, key := vc.add(fakeSongs(t, "a", 3), false, "a", limits)
+	if added != 3 || n != 3 || key != "" {
+		t.Errorf("add = %d, %d, %q, want 3, 3, \"\"", added, n, key)
+	}
+
+	added, n, key = vc.add(fakeSongs(t, "b", 3), false, "b", limits)
+	if added != 2 || n != 5 || key != "music.limit.queue" {
+		t.Errorf("add over the queue limit = %d, %d, %q, want 2, 5, music.limit.queue", added, n, key)
+	}
+
+	added, n, _ = vc.add(fakeSongs(t, "c", 2), true, "c", limits)
+	if added != 2 || n != 2 || vc.Queue[0].AddedByID != "c" {
+		t.Errorf("load = %d, %d, want the queue to be replaced", added, n)
+	}
+}
+
+func TestQueueShare(t *testing.T) {
+	vc := &voiceConnection{}
+	limits := guildSettings{MaxQueue: 10, MaxShare: 30}
+
+	added, _, key := vc.add(fakeSongs(t, "a", 5), false, "a", limits)
+	if added != 3 || key != "music.limit.share" {
+		t.Errorf("add over the share limit = %d, %q, want 3, music.limit.share", added, key)
+	}
+	if room, _ := vc.room("b", limits); room != 3 {
+		t.Errorf("room of another user = %d, want 3", room)
+	}
+}
+
+func TestQueueRemoveIf(t *testing.T) {
+	vc := &voiceConnection{Queue: append(fakeSongs(t, "a", 2), fakeSongs(t, "b", 2)...)}
+
+	removed := vc.removeIf(func(i int, s song) bool {
+		return s.AddedByID == "a"
+	})
+	if removed != 2 || len(vc.Queue) != 2 {
+		t.Fatalf("removeIf removed %d songs and left %d, want 2 and 2", removed, len(vc.Queue))
+	}
+	for _, s := range vc.Queue {
+		if s.AddedByID != "b" {
+			t.Errorf("a song of a was left in the queue: %+v", s)
+		}
+	}
+}
+
+func TestQueueSkiptoLoop(t *testing.T) {
+	songs := fakeSongs(t, "a", 4)
+	vc := &voiceConnection{Loop: true, Playing: &songs[0], Queue: append([]song{}, songs[1:]...)}
+	p := New(nil, Config{}).(*MusicPlugin)
+
+	if _, skip := p.editQueue(rikka.NewBot(), vc, &testService{}, &testMessage{userID: "a"}, "skipto", []string{"3"}); !skip {
+		t.Fatalf("skipto didn't skip the song that is playing")
+	}
+	vc.finish(*vc.Playing, true)
+
+	want := []string{songs[3].Title, songs[0].Title, songs[1].Title, songs[2].Title}
+	got := []string{}
+	for _, s := range vc.Queue {
+		got = append(got, s.Title)
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") || vc.Playing != nil {
+		t.Errorf("the looping queue is %q, want %q", got, want)
+	}
+}
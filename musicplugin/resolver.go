@@ -0,0 +1,212 @@
+package musicplugin
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os/exec"
+)
+
+// ErrNoResults is returned by resolvers when nothing is found.
+var ErrNoResults = errors.New("no songs found")
+
+// ErrNoResolver is returned for songs from a source that no resolver opens.
+var ErrNoResolver = errors.New("no resolver for song")
+
+// The number of results of a search.
+const searchResults = 5
+
+// A Resolver finds songs and opens their audio.
+type Resolver interface {
+	// Source is saved with the songs a resolver finds, so they are opened by the same resolver after a restart.
+	Source() string
+	// Resolve returns the songs for a URL or path, or the results of a search.
+	Resolve(query string, search bool) ([]song, error)
+	// Open returns the audio of a song, it must be closed when it isn't played anymore.
+	Open(s song) (*Audio, error)
+}
+
+// Audio is the audio of a song, ffmpeg reads it from Input or, when it is empty, from Reader.
+type Audio struct {
+	// Input is a file or URL that ffmpeg reads itself, so it can seek in it.
+	Input string
+	// Reader is audio in any format ffmpeg understands.
+	Reader io.Reader
+
+	close func()
+}
+
+// Close stops whatever the audio is read from.
+func (a *Audio) Close() {
+	if a.close != nil {
+		a.close()
+	}
+}
+
+// resolver returns the resolver that opens songs from a source.
+func (p *MusicPlugin) resolver(source string) (Resolver, error) {
+	if p.config.Resolver != nil && p.config.Resolver.Source() == source {
+		return p.config.Resolver, nil
+	}
+	switch source {
+	case sourceYoutubeDL:
+		return youtubeDLResolver{binary: p.config.YoutubeDL}, nil
+	case sourceURL:
+		return httpResolver{}, nil
+	case sourceFile:
+		return fileResolver{p}, nil
+	}
+	return nil, ErrNoResolver
+}
+
+// query returns the resolver that finds songs that are played or searched for.
+func (p *MusicPlugin) query() Resolver {
+	if p.config.Resolver != nil {
+		return p.config.Resolver
+	}
+	return youtubeDLResolver{binary: p.config.YoutubeDL}
+}
+
+// youtubeDLResolver finds and downloads songs with youtube-dl, or a fork with the same options like yt-dlp.
+type youtubeDLResolver struct {
+	binary string
+}
+
+func (r youtubeDLResolver) Source() string {
+	return sourceYoutubeDL
+}
+
+func (r youtubeDLResolver) command(args ...string) *exec.Cmd {
+	binary := r.binary
+	if binary == "" {
+		binary = "youtube-dl"
+	}
+	return exec.Command(binary, args...)
+}
+
+func (r youtubeDLResolver) Resolve(query string, search bool) ([]song, error) {
+	if search {
+		query = fmt.Sprintf("ytsearch%d:%s", searchResults, query)
+	}
+
+	// Songs that can't be read are skipped, so the output is used even when there is an error.
+	stderr := &bytes.Buffer{}
+	cmd := r.command("-i", "-j", "--youtube-skip-dash-manifest", query)
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil && len(out) == 0 {
+		log.Println("musicplugin: youtube-dl err:", err, stderr.String())
+		if search {
+			return nil, ErrNoResults
+		}
+		return nil, err
+	}
+
+	songs := []song{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		s := song{}
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			log.Println("musicplugin: youtube-dl output err:", err)
+			continue
+		}
+		songs = append(songs, s)
+	}
+	if len(songs) == 0 {
+		return nil, ErrNoResults
+	}
+	return songs, nil
+}
+
+func (r youtubeDLResolver) Open(s song) (*Audio, error) {
+	ytdl := r.command("-f", "bestaudio", "-o", "-", s.URL)
+	out, err := ytdl.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := ytdl.Start(); err != nil {
+		return nil, err
+	}
+	return &Audio{
+		Reader: bufio.NewReaderSize(out, 16384),
+		close: func() {
+			ytdl.Process.Kill()
+			go ytdl.Wait()
+		},
+	}, nil
+}
+
+// httpResolver plays audio files and streams from HTTP URLs with ffmpeg.
+type httpResolver struct{}
+
+func (r httpResolver) Source() string {
+	return sourceURL
+}
+
+func (r httpResolver) Resolve(query string, search bool) ([]song, error) {
+	if search {
+		return nil, ErrNoResults
+	}
+	u, err := url.ParseRequestURI(query)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, ErrNoResults
+	}
+	// The song is played from the URL, so it can't point into the network the bot runs in.
+	if u, err = publicURL(u.String()); err != nil {
+		return nil, err
+	}
+	return []song{streamSong(u, "")}, nil
+}
+
+func (r httpResolver) Open(s song) (*Audio, error) {
+	return &Audio{Input: s.URL}, nil
+}
+
+// fileResolver plays files in the music library.
+type fileResolver struct {
+	p *MusicPlugin
+}
+
+func (r fileResolver) Source() string {
+	return sourceFile
+}
+
+func (r fileResolver) Resolve(query string, search bool) ([]song, error) {
+	if !search {
+		return r.p.librarySongs(query)
+	}
+
+	results, err := r.p.searchLibrary(query)
+	if err != nil {
+		return nil, err
+	}
+	songs := []song{}
+	for _, name := range results {
+		if len(songs) == searchResults {
+			break
+		}
+		s, err := r.p.librarySongs(name)
+		if err != nil {
+			continue
+		}
+		songs = append(songs, s...)
+	}
+	if len(songs) == 0 {
+		return nil, ErrNoResults
+	}
+	return songs, nil
+}
+
+func (r fileResolver) Open(s song) (*Audio, error) {
+	file, err := r.p.libraryPath(s.URL)
+	if err != nil {
+		return nil, err
+	}
+	return &Audio{Input: file}, nil
+}
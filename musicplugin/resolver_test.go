@@ -0,0 +1,95 @@
+package musicplugin
+
+import (
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestFakeResolverResolve(t *testing.T) {
+	r := &FakeResolver{Duration: 2 * time.Second}
+
+	songs, err := r.Resolve("song", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(songs) != 1 || songs[0].Title != "song" || songs[0].Duration != 2 || songs[0].Source != "fake" {
+		t.Errorf("Resolve(song) = %+v", songs)
+	}
+
+	songs, err = r.Resolve("song", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(songs) != searchResults {
+		t.Errorf("search returned %d songs, want %d", len(songs), searchResults)
+	}
+
+	if _, err := r.Resolve("", false); err != ErrNoResults {
+		t.Errorf("Resolve of an empty query returned %v, want ErrNoResults", err)
+	}
+}
+
+func TestFakeResolverOpen(t *testing.T) {
+	r := &FakeResolver{Duration: time.Second}
+	songs, _ := r.Resolve("song", false)
+
+	audio, err := r.Open(songs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(audio.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 44 + fakeSampleRate*fakeChannels*fakeSampleSize; len(b) != want {
+		t.Errorf("read %d bytes, want %d", len(b), want)
+	}
+	if string(b[:4]) != "RIFF" || string(b[8:12]) != "WAVE" {
+		t.Errorf("the audio isn't a WAV file")
+	}
+}
+
+func TestToneReaderShortBuffer(t *testing.T) {
+	r := &toneReader{frequency: 440, samples: 10}
+
+	if n, err := r.Read(make([]byte, 3)); n != 0 || err != io.ErrShortBuffer {
+		t.Errorf("Read into 3 bytes = %d, %v, want 0, io.ErrShortBuffer", n, err)
+	}
+	if n, err := r.Read(make([]byte, 6)); n != 4 || err != nil {
+		t.Errorf("Read into 6 bytes = %d, %v, want 4, nil", n, err)
+	}
+	if n, err := r.Read(make([]byte, 100)); n != 36 || err != nil {
+		t.Errorf("Read of the rest = %d, %v, want 36, nil", n, err)
+	}
+	if _, err := r.Read(make([]byte, 100)); err != io.EOF {
+		t.Errorf("Read after the end returned %v, want io.EOF", err)
+	}
+}
+
+func TestPluginResolver(t *testing.T) {
+	fake := &FakeResolver{}
+	p := New(nil, Config{Resolver: fake}).(*MusicPlugin)
+
+	if r, err := p.resolver("fake"); err != nil || r != fake {
+		t.Errorf("resolver(fake) = %v, %v, want the fake resolver", r, err)
+	}
+	if p.query() != fake {
+		t.Errorf("query() isn't the fake resolver")
+	}
+	if _, err := p.resolver("unknown"); err != ErrNoResolver {
+		t.Errorf("resolver(unknown) returned %v, want ErrNoResolver", err)
+	}
+}
+
+func TestHTTPResolverPrivateAddress(t *testing.T) {
+	for _, location := range []string{"http://127.0.0.1:8080/a.mp3", "http://169.254.169.254/latest/meta-data", "https://[::1]/stream"} {
+		if _, err := (httpResolver{}).Resolve(location, false); err != errPrivateAddress {
+			t.Errorf("Resolve(%q) returned %v, want errPrivateAddress", location, err)
+		}
+	}
+	if _, err := (httpResolver{}).Resolve("ftp://example.com/a.mp3", false); err != ErrNoResults {
+		t.Errorf("Resolve of an ftp URL returned %v, want ErrNoResults", err)
+	}
+}
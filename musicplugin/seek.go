@@ -0,0 +1,101 @@
+package musicplugin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ThyLeader/rikka"
+)
+
+// How far forward and rewind move when no time is given.
+const defaultSeekStep = 10 * time.Second
+
+// parseTimestamp parses a position like "90", "1:30" or "1:02:30".
+func parseTimestamp(s string) (time.Duration, bool) {
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, false
+	}
+	d := time.Duration(0)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || (i > 0 && n > 59) {
+			return 0, false
+		}
+		d = d*60 + time.Duration(n)
+	}
+	return d * time.Second, true
+}
+
+// formatTimestamp formats a position like "1:30" or "1:02:30".
+func formatTimestamp(d time.Duration) string {
+	seconds := int(d.Seconds())
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// seekPosition returns where the song that is playing is moved to.
+func (vc *voiceConnection) seekPosition() time.Duration {
+	vc.Lock()
+	defer vc.Unlock()
+
+	return vc.seek
+}
+
+// seekCommand handles the seek, forward and rewind commands.
+func (p *MusicPlugin) seekCommand(bot *rikka.Bot, vc *voiceConnection, service rikka.Service, message rikka.Message, parts []string) {
+	command := parts[0]
+
+	var amount time.Duration
+	if len(parts) > 1 {
+		var ok bool
+		if amount, ok = parseTimestamp(parts[1]); !ok {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.seek.invalid", parts[1]))
+			return
+		}
+	} else if command == "seek" {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.seek.usage", service.CommandPrefix()))
+		return
+	} else {
+		amount = defaultSeekStep
+	}
+
+	vc.Lock()
+	if vc.Playing == nil || vc.control == nil {
+		vc.Unlock()
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.seek.nothing"))
+		return
+	}
+	// Streams without an end, like radio, can't be moved in.
+	if vc.Playing.Duration == 0 {
+		vc.Unlock()
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.seek.stream"))
+		return
+	}
+
+	position := amount
+	switch command {
+	case "forward", "ff":
+		position = vc.Playing.Position + amount
+	case "rewind", "rw":
+		position = vc.Playing.Position - amount
+	}
+	if position < 0 {
+		position = 0
+	}
+	duration := time.Duration(vc.Playing.Duration) * time.Second
+	if position >= duration {
+		vc.Unlock()
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.seek.end", formatTimestamp(duration)))
+		return
+	}
+	vc.seek = position
+	vc.Unlock()
+
+	vc.send(Seek)
+	service.SendMessage(message.Channel(), bot.Translate(service, message, "music.seek", formatTimestamp(position), formatTimestamp(duration)))
+}
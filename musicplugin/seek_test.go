@@ -0,0 +1,49 @@
+package musicplugin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		s  string
+		d  time.Duration
+		ok bool
+	}{
+		{"0", 0, true},
+		{"90", 90 * time.Second, true},
+		{"1:30", 90 * time.Second, true},
+		{"1:02:30", time.Hour + 2*time.Minute + 30*time.Second, true},
+		{"0:59", 59 * time.Second, true},
+		{"1:60", 0, false},
+		{"1:1:1:1", 0, false},
+		{"-5", 0, false},
+		{"1:a", 0, false},
+		{"", 0, false},
+	}
+
+	for _, test := range tests {
+		d, ok := parseTimestamp(test.s)
+		if d != test.d || ok != test.ok {
+			t.Errorf("parseTimestamp(%q) = %v, %v, want %v, %v", test.s, d, ok, test.d, test.ok)
+		}
+	}
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	tests := []struct {
+		d time.Duration
+		s string
+	}{
+		{0, "0:00"},
+		{90 * time.Second, "1:30"},
+		{time.Hour + 2*time.Minute + 5*time.Second, "1:02:05"},
+	}
+
+	for _, test := range tests {
+		if s := formatTimestamp(test.d); s != test.s {
+			t.Errorf("formatTimestamp(%v) = %q, want %q", test.d, s, test.s)
+		}
+	}
+}
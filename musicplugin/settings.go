@@ -0,0 +1,270 @@
+package musicplugin
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ThyLeader/rikka"
+	"github.com/jonas747/dca"
+)
+
+// The default bitrate of songs, in kbps, when it isn't configured.
+const defaultBitrate = 64
+
+// The lowest and highest bitrates a guild can choose.
+const (
+	minBitrate = 8
+	maxBitrate = 128
+)
+
+// The highest volume, in percent.
+const maxVolume = 200
+
+// An audioFilter is an ffmpeg audio filter preset.
+type audioFilter struct {
+	// Filter is the ffmpeg filter graph.
+	Filter string
+	// Speed is how much faster than normal the filter plays songs.
+	Speed float64
+}
+
+var audioFilters = map[string]audioFilter{
+	"bassboost": {"bass=g=10,dynaudnorm=f=200", 1},
+	"nightcore": {"asetrate=60000,aresample=48000", 1.25},
+	"vaporwave": {"asetrate=38400,aresample=48000", 0.8},
+	"normalize": {"loudnorm=I=-16:TP=-1.5:LRA=11", 1},
+	"8d":        {"apulsator=hz=0.125", 1},
+}
+
+var audioFilterAliases = map[string]string{
+	"bass":     "bassboost",
+	"loudnorm": "normalize",
+}
+
+// guildSettings are the music settings of a guild, they are kept when the bot leaves the voice channel.
+type guildSettings struct {
+	// Volume is in percent, 0 uses the default of 100.
+	Volume int
+	// Bitrate is in kbps, 0 uses the configured bitrate.
+	Bitrate int
+	Filter  string
+	// DJRole is the role needed for the DJ commands, everyone can use them when it is empty.
+	DJRole string
+	// SkipVotes is the percentage of listeners that have to vote to skip a song, 0 uses the default.
+	SkipVotes int
+	// MaxQueue is the most songs the queue can hold, MaxDuration the longest song in seconds,
+	// MaxShare the percentage of the queue one user can fill, and MaxPlaylist the most songs added from a playlist.
+	// They use the defaults when they are 0.
+	MaxQueue    int
+	MaxDuration int
+	MaxShare    int
+	MaxPlaylist int
+	// Controls is whether the now playing panel has reactions to pause, skip and loop.
+	Controls bool
+}
+
+// settings returns the settings of a guild, with defaults for the settings that weren't changed.
+func (p *MusicPlugin) settings(guildID string) guildSettings {
+	p.Lock()
+	defer p.Unlock()
+
+	s := guildSettings{}
+	if g := p.Settings[guildID]; g != nil {
+		s = *g
+	}
+	if s.Volume == 0 {
+		s.Volume = 100
+	}
+	if s.Bitrate == 0 {
+		s.Bitrate = p.config.Bitrate
+	}
+	if s.Bitrate == 0 {
+		s.Bitrate = defaultBitrate
+	}
+	if s.SkipVotes == 0 {
+		s.SkipVotes = defaultSkipVotes
+	}
+	if s.MaxQueue == 0 {
+		s.MaxQueue = defaultMaxQueue
+	}
+	if s.MaxDuration == 0 {
+		s.MaxDuration = defaultMaxDuration
+	}
+	if s.MaxShare == 0 {
+		s.MaxShare = defaultMaxShare
+	}
+	if s.MaxPlaylist == 0 {
+		s.MaxPlaylist = defaultMaxPlaylist
+	}
+	return s
+}
+
+// updateSettings changes the settings of a guild.
+func (p *MusicPlugin) updateSettings(guildID string, update func(s *guildSettings)) {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.Settings[guildID] == nil {
+		p.Settings[guildID] = &guildSettings{}
+	}
+	update(p.Settings[guildID])
+}
+
+// encodeOptions returns the options songs are encoded with, starting at a position in the song.
+func (s guildSettings) encodeOptions(position time.Duration) *dca.EncodeOptions {
+	options := *dca.StdEncodeOptions
+	options.RawOutput = true
+	options.Bitrate = s.Bitrate
+	options.Application = "lowdelay"
+	// 256 is the normal volume.
+	options.Volume = s.Volume * 256 / 100
+	options.StartTime = int(position.Seconds())
+	if f, ok := audioFilters[s.Filter]; ok {
+		options.AudioFilter = f.Filter
+	}
+	return &options
+}
+
+// speed returns how much faster than normal songs are played.
+func (s guildSettings) speed() float64 {
+	if f, ok := audioFilters[s.Filter]; ok {
+		return f.Speed
+	}
+	return 1
+}
+
+// send sends a control message to the song that is playing, it gives up if the song doesn't read it or the player stops.
+func (vc *voiceConnection) send(c controlMessage) {
+	vc.Lock()
+	control, close := vc.control, vc.close
+	playing := vc.Playing != nil
+	vc.Unlock()
+
+	if control == nil || !playing {
+		return
+	}
+	select {
+	case control <- c:
+	case <-close:
+	case <-time.After(time.Second):
+	}
+}
+
+// restart encodes the song that is playing in a guild again, so changes to the settings are heard.
+func (p *MusicPlugin) restart(guildID string) {
+	p.Lock()
+	vc := p.VoiceConnections[guildID]
+	p.Unlock()
+
+	if vc != nil {
+		vc.send(Restart)
+	}
+}
+
+// settingsCommand handles the volume, bitrate and filter commands.
+func (p *MusicPlugin) settingsCommand(bot *rikka.Bot, service rikka.Service, message rikka.Message, guildID string, parts []string) {
+	command := parts[0]
+	current := p.settings(guildID)
+
+	switch command {
+	case "volume", "vol":
+		if len(parts) == 1 {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.volume", current.Volume))
+			return
+		}
+		v, err := strconv.Atoi(strings.TrimSuffix(parts[1], "%"))
+		if err != nil || v < 1 || v > maxVolume {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.volume.invalid", maxVolume))
+			return
+		}
+		p.updateSettings(guildID, func(s *guildSettings) {
+			s.Volume = v
+		})
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.volume.set", v))
+
+	case "bitrate":
+		if len(parts) == 1 {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.bitrate", current.Bitrate))
+			return
+		}
+		b, err := strconv.Atoi(strings.TrimSuffix(strings.ToLower(parts[1]), "kbps"))
+		if err != nil || b < minBitrate || b > maxBitrate {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.bitrate.invalid", minBitrate, maxBitrate))
+			return
+		}
+		p.updateSettings(guildID, func(s *guildSettings) {
+			s.Bitrate = b
+		})
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.bitrate.set", b))
+
+	case "filter", "filters", "fx":
+		names := []string{}
+		for name := range audioFilters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		if len(parts) == 1 {
+			filter := current.Filter
+			if filter == "" {
+				filter = "off"
+			}
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.filter", filter, strings.Join(names, ", ")))
+			return
+		}
+
+		name := strings.ToLower(parts[1])
+		if alias, ok := audioFilterAliases[name]; ok {
+			name = alias
+		}
+		switch name {
+		case "off", "none", "reset", "clear":
+			name = ""
+		default:
+			if _, ok := audioFilters[name]; !ok {
+				service.SendMessage(message.Channel(), bot.Translate(service, message, "music.filter.unknown", parts[1], strings.Join(names, ", ")))
+				return
+			}
+		}
+		p.updateSettings(guildID, func(s *guildSettings) {
+			s.Filter = name
+		})
+		if name == "" {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.filter.off"))
+		} else {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.filter.set", name))
+		}
+	}
+
+	p.restart(guildID)
+}
+
+// controlsCommand shows or sets whether the now playing panel of a guild has reaction controls.
+func (p *MusicPlugin) controlsCommand(bot *rikka.Bot, service rikka.Service, message rikka.Message, guildID string, parts []string) {
+	if len(parts) == 1 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.controls", p.settings(guildID).Controls))
+		return
+	}
+
+	if !service.IsModerator(message) {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.moderator"))
+		return
+	}
+
+	var on bool
+	switch parts[1] {
+	case "on", "enable", "true":
+		on = true
+	case "off", "disable", "false":
+		on = false
+	default:
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.controls.usage", service.CommandPrefix()))
+		return
+	}
+	p.updateSettings(guildID, func(s *guildSettings) {
+		s.Controls = on
+	})
+	service.SendMessage(message.Channel(), bot.Translate(service, message, "music.controls", on))
+}
@@ -0,0 +1,400 @@
+package musicplugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ThyLeader/rikka"
+)
+
+// ErrNoLibrary is returned when the music library isn't configured.
+var ErrNoLibrary = errors.New("no music library")
+
+// ErrNotInLibrary is returned for paths that aren't in the music library.
+var ErrNotInLibrary = errors.New("not in the music library")
+
+// Where a song is played from.
+const (
+	// sourceYoutubeDL songs are downloaded with youtube-dl, it is the zero value so older queues keep working.
+	sourceYoutubeDL = ""
+	// sourceURL songs are HTTP audio files or streams that are passed to ffmpeg directly.
+	sourceURL = "url"
+	// sourceFile songs are files in the music library, their URL is the path in the library.
+	sourceFile = "file"
+)
+
+// The number of entries shown when browsing or searching the library.
+const libraryListSize = 25
+
+// How long probing an audio file or stream can take.
+const probeTimeout = 15 * time.Second
+
+var audioExtensions = map[string]bool{
+	".mp3":  true,
+	".ogg":  true,
+	".oga":  true,
+	".opus": true,
+	".flac": true,
+	".wav":  true,
+	".m4a":  true,
+	".aac":  true,
+	".wma":  true,
+	".webm": true,
+}
+
+func isAudioFile(name string) bool {
+	return audioExtensions[strings.ToLower(path.Ext(name))]
+}
+
+// probe reads the title and duration of an audio file or URL with ffprobe.
+// Streams without an end, like internet radio, have a duration of 0.
+func probe(input string) (title string, duration int) {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "ffprobe", "-v", "quiet", "-print_format", "json", "-show_format", input).Output()
+	if err != nil {
+		return "", 0
+	}
+
+	info := struct {
+		Format struct {
+			Duration string            `json:"duration"`
+			Tags     map[string]string `json:"tags"`
+		} `json:"format"`
+	}{}
+	if err := json.Unmarshal(out, &info); err != nil {
+		return "", 0
+	}
+
+	if d, err := strconv.ParseFloat(info.Format.Duration, 64); err == nil {
+		duration = int(d)
+	}
+	tags := map[string]string{}
+	for k, v := range info.Format.Tags {
+		tags[strings.ToLower(k)] = v
+	}
+	title = tags["title"]
+	if title == "" {
+		title = tags["icy-name"]
+	}
+	if title != "" && tags["artist"] != "" {
+		title = tags["artist"] + " - " + title
+	}
+	return title, duration
+}
+
+// isStream returns whether a public URL is an audio file or stream that ffmpeg can play without youtube-dl.
+func isStream(u *url.URL) bool {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+	if isAudioFile(u.Path) {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodHead, u.String(), nil)
+	if err != nil {
+		return false
+	}
+	resp, err := publicClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+
+	t := strings.ToLower(resp.Header.Get("Content-Type"))
+	return strings.HasPrefix(t, "audio/") || strings.HasPrefix(t, "application/ogg") || resp.Header.Get("icy-name") != ""
+}
+
+// streamSong creates a song for an audio file or stream URL, its title is taken from the stream if possible.
+func streamSong(u *url.URL, name string) song {
+	title, duration := probe(u.String())
+	if title == "" {
+		title = name
+	}
+	if title == "" {
+		title = path.Base(u.Path)
+	}
+	if title == "" || title == "/" || title == "." {
+		title = u.Host
+	}
+	return song{
+		ID:       path.Base(u.Path),
+		Title:    title,
+		URL:      u.String(),
+		Duration: duration,
+		Source:   sourceURL,
+	}
+}
+
+// attachments returns the songs for the audio files attached to a message.
+func attachments(message rikka.Message) []song {
+	m, ok := message.(*rikka.DiscordMessage)
+	if !ok {
+		return nil
+	}
+
+	songs := []song{}
+	for _, a := range m.DiscordgoMessage.Attachments {
+		if !isAudioFile(a.Filename) {
+			continue
+		}
+		u, err := publicURL(a.URL)
+		if err != nil {
+			continue
+		}
+		s := streamSong(u, a.Filename)
+		s.ID = a.ID
+		songs = append(songs, s)
+	}
+	return songs
+}
+
+// libraryPath returns the path of a file or directory in the music library.
+// Paths are always inside the library, ".." can't be used to leave it.
+func (p *MusicPlugin) libraryPath(name string) (string, error) {
+	if p.config.Library == "" {
+		return "", ErrNoLibrary
+	}
+	return filepath.Join(p.config.Library, filepath.FromSlash(path.Clean("/"+name))), nil
+}
+
+// libraryName returns the name of a file in the music library, relative to the library.
+func (p *MusicPlugin) libraryName(file string) string {
+	rel, err := filepath.Rel(p.config.Library, file)
+	if err != nil {
+		return file
+	}
+	return filepath.ToSlash(rel)
+}
+
+// browseLibrary returns the directories and audio files in a directory of the library.
+func (p *MusicPlugin) browseLibrary(name string) (dirs, files []string, err error) {
+	dir, err := p.libraryPath(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, err := os.Open(dir)
+	if err != nil {
+		return nil, nil, ErrNotInLibrary
+	}
+	defer f.Close()
+
+	infos, err := f.Readdir(-1)
+	if err != nil {
+		return nil, nil, ErrNotInLibrary
+	}
+	for _, i := range infos {
+		if strings.HasPrefix(i.Name(), ".") {
+			continue
+		}
+		if i.IsDir() {
+			dirs = append(dirs, i.Name()+"/")
+		} else if isAudioFile(i.Name()) {
+			files = append(files, i.Name())
+		}
+	}
+	sort.Strings(dirs)
+	sort.Strings(files)
+	return dirs, files, nil
+}
+
+// searchLibrary returns the audio files in the library whose path contains all the words of a search.
+func (p *MusicPlugin) searchLibrary(search string) ([]string, error) {
+	if p.config.Library == "" {
+		return nil, ErrNoLibrary
+	}
+
+	words := strings.Fields(strings.ToLower(search))
+	results := []string{}
+	filepath.Walk(p.config.Library, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if strings.HasPrefix(info.Name(), ".") && file != p.config.Library {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() || !isAudioFile(info.Name()) {
+			return nil
+		}
+
+		name := strings.ToLower(p.libraryName(file))
+		for _, w := range words {
+			if !strings.Contains(name, w) {
+				return nil
+			}
+		}
+		results = append(results, p.libraryName(file))
+		return nil
+	})
+	return results, nil
+}
+
+// librarySongs returns the songs for a file in the library, or for all the audio files in a directory.
+func (p *MusicPlugin) librarySongs(name string) ([]song, error) {
+	file, err := p.libraryPath(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, ErrNotInLibrary
+	}
+
+	files := []string{file}
+	if info.IsDir() {
+		_, names, err := p.browseLibrary(name)
+		if err != nil {
+			return nil, err
+		}
+		files = files[:0]
+		for _, n := range names {
+			files = append(files, filepath.Join(file, n))
+		}
+	} else if !isAudioFile(file) {
+		return nil, ErrNotInLibrary
+	}
+
+	songs := []song{}
+	for _, f := range files {
+		title, duration := probe(f)
+		if title == "" {
+			title = strings.TrimSuffix(filepath.Base(f), filepath.Ext(f))
+		}
+		songs = append(songs, song{
+			ID:       filepath.Base(f),
+			Title:    title,
+			URL:      p.libraryName(f),
+			Duration: duration,
+			Source:   sourceFile,
+		})
+	}
+	return songs, nil
+}
+
+// library browses the music library, eg. "library", "library rock/", or "library search <words>".
+func (p *MusicPlugin) library(bot *rikka.Bot, service rikka.Service, message rikka.Message, parts []string) {
+	if p.config.Library == "" {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.library.none"))
+		return
+	}
+
+	if len(parts) > 0 && strings.ToLower(parts[0]) == "search" {
+		search := strings.Join(parts[1:], " ")
+		results, err := p.searchLibrary(search)
+		if err != nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+			return
+		}
+		if len(results) == 0 {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.search.none", search))
+			return
+		}
+		service.SendMessage(message.Channel(), p.libraryList(bot, service, message, bot.Translate(service, message, "music.library.results", search), results))
+		return
+	}
+
+	name := strings.Join(parts, " ")
+	dirs, files, err := p.browseLibrary(name)
+	if err != nil {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.library.notfound", name))
+		return
+	}
+	if len(dirs)+len(files) == 0 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.library.empty", path.Clean("/"+name)))
+		return
+	}
+	service.SendMessage(message.Channel(), p.libraryList(bot, service, message, bot.Translate(service, message, "music.library.browse", path.Clean("/"+name)), append(dirs, files...)))
+}
+
+func (p *MusicPlugin) libraryList(bot *rikka.Bot, service rikka.Service, message rikka.Message, title string, entries []string) string {
+	lines := []string{title, "```"}
+	for i, e := range entries {
+		if i == libraryListSize {
+			lines = append(lines, bot.Translate(service, message, "music.library.more", len(entries)-i))
+			break
+		}
+		lines = append(lines, e)
+	}
+	lines = append(lines, "```", bot.Translate(service, message, "music.library.help", service.CommandPrefix()))
+	return strings.Join(lines, "\n")
+}
+
+// local queues a file or directory of the music library, or searches it when there is no such file.
+func (p *MusicPlugin) local(bot *rikka.Bot, vc *voiceConnection, service rikka.Service, message rikka.Message, name string) {
+	if p.config.Library == "" {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.library.none"))
+		return
+	}
+
+	songs, err := fileResolver{p}.Resolve(name, false)
+	if err == ErrNotInLibrary {
+		results, err := p.searchLibrary(name)
+		if err != nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+			return
+		}
+		switch len(results) {
+		case 0:
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "music.search.none", name))
+			return
+		case 1:
+			songs, err = fileResolver{p}.Resolve(results[0], false)
+		default:
+			service.SendMessage(message.Channel(), p.libraryList(bot, service, message, bot.Translate(service, message, "music.library.results", name), results))
+			return
+		}
+	}
+	if err != nil {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+		return
+	}
+	if len(songs) == 0 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.library.empty", path.Clean("/"+name)))
+		return
+	}
+
+	// Directories are cut to the playlist limit of the guild.
+	if limit := p.settings(vc.GuildID).MaxPlaylist; len(songs) > limit {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.limit.playlist", limit, len(songs)))
+		songs = songs[:limit]
+	}
+	for _, s := range songs {
+		if !p.addSong(bot, vc, service, message, s) {
+			return
+		}
+	}
+}
+
+// stream queues an audio file or stream URL, it is played by ffmpeg without youtube-dl.
+func (p *MusicPlugin) stream(bot *rikka.Bot, vc *voiceConnection, service rikka.Service, message rikka.Message, rawURL string) {
+	service.Typing(message.Channel())
+	songs, err := httpResolver{}.Resolve(rawURL, false)
+	if err == errPrivateAddress {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.url.private"))
+		return
+	}
+	if err != nil {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "music.stream.invalid"))
+		return
+	}
+	p.addSong(bot, vc, service, message, songs[0])
+}
@@ -0,0 +1,19 @@
+package nametrackplugin
+
+import "github.com/ThyLeader/rikka"
+
+func init() {
+	rikka.RegisterMessages("en", map[string]string{
+		"names.help":     "See a user's past usernames",
+		"names.scanned":  "scanned guild %s",
+		"names.scanning": "scanning all..",
+		"names.notfound": "User `%s` not found\nPlease use the user's ID or mention them. Username searches coming soon:tm:",
+	})
+
+	rikka.RegisterMessages("es", map[string]string{
+		"names.help":     "Muestra los nombres de usuario anteriores de un usuario",
+		"names.scanned":  "servidor %s escaneado",
+		"names.scanning": "escaneando todo..",
+		"names.notfound": "Usuario `%s` no encontrado\nPor favor usa el ID del usuario o menciónalo. Búsqueda por nombre próximamente:tm:",
+	})
+}
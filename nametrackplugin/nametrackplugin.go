@@ -55,20 +55,20 @@ func (p *nameTrackPlugin) Message(bot *rikka.Bot, service rikka.Service, message
 	} else {
 		if parts[0] == "scan" {
 			if !service.IsBotOwner(message) {
-				service.SendMessage(message.Channel(), "Only the bot owner can use this feature")
+				service.SendMessage(message.Channel(), bot.Translate(service, message, "error.owner"))
 				return
 			}
 			p.testScan(message.GuildID(), service)
-			service.SendMessage(message.Channel(), "scanned guild "+message.GuildID())
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "names.scanned", message.GuildID()))
 			return
 		}
 		if parts[0] == "scanall" {
 			if !service.IsBotOwner(message) {
-				service.SendMessage(message.Channel(), "Only the bot owner can use this feature")
+				service.SendMessage(message.Channel(), bot.Translate(service, message, "error.owner"))
 				return
 			}
 			p.scanAll(service)
-			service.SendMessage(message.Channel(), "scanning all..")
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "names.scanning"))
 			return
 		}
 
@@ -85,7 +85,7 @@ func (p *nameTrackPlugin) Message(bot *rikka.Bot, service rikka.Service, message
 
 	u := client.SMembers("names:" + user).Val()
 	if len(u) < 1 {
-		service.SendMessage(message.Channel(), fmt.Sprintf("User `%s` not found\nPlease use the user's ID or mention them. Username searches coming soon:tm:", user))
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "names.notfound", user))
 		return
 	}
 	/*embed := &discordgo.MessageEmbed{
@@ -106,49 +106,54 @@ func (p *nameTrackPlugin) Message(bot *rikka.Bot, service rikka.Service, message
 }
 
 func (p *nameTrackPlugin) Run(bot *rikka.Bot, service rikka.Service) {
-	discord := service.(*rikka.Discord)
+	discord := rikka.DiscordService(service)
+
+	discord.ForEachSession(func(session *discordgo.Session) {
+		session.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
+			fmt.Println("ready")
+			for _, g := range r.Guilds {
+				for _, m := range g.Members {
+					p.update(m.User, m.Nick)
+				}
+			}
+
+			p.scanSession(s)
+		})
 
-	discord.Session.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
-		fmt.Println("ready")
-		for _, g := range r.Guilds {
+		session.AddHandler(func(s *discordgo.Session, g *discordgo.GuildCreate) {
+			if g.Unavailable {
+				return
+			}
 			for _, m := range g.Members {
 				p.update(m.User, m.Nick)
 			}
-		}
+		})
 
-		p.scanAll(service)
-	})
+		session.AddHandler(func(s *discordgo.Session, r *discordgo.GuildMemberUpdate) {
+			p.update(r.User, r.Nick)
+		})
 
-	discord.Session.AddHandler(func(s *discordgo.Session, g *discordgo.GuildCreate) {
-		if g.Unavailable {
-			return
-		}
-		for _, m := range g.Members {
-			p.update(m.User, m.Nick)
-		}
-	})
-
-	discord.Session.AddHandler(func(s *discordgo.Session, r *discordgo.GuildMemberUpdate) {
-		p.update(r.User, r.Nick)
-	})
+		session.AddHandler(func(s *discordgo.Session, r *discordgo.GuildMemberAdd) {
+			p.update(r.User, r.Nick)
+		})
 
-	discord.Session.AddHandler(func(s *discordgo.Session, r *discordgo.GuildMemberAdd) {
-		p.update(r.User, r.Nick)
-	})
-
-	discord.Session.AddHandler(func(s *discordgo.Session, r *discordgo.UserUpdate) {
-		p.update(r.User, "")
-	})
+		session.AddHandler(func(s *discordgo.Session, r *discordgo.UserUpdate) {
+			p.update(r.User, "")
+		})
 
-	discord.Session.AddHandler(func(s *discordgo.Session, r *discordgo.PresenceUpdate) {
-		p.update(r.User, r.Nick)
+		session.AddHandler(func(s *discordgo.Session, r *discordgo.PresenceUpdate) {
+			p.update(r.User, r.Nick)
+		})
 	})
 }
 
 func (p *nameTrackPlugin) testScan(gID string, service rikka.Service) {
-	discord := service.(*rikka.Discord)
+	discord := rikka.DiscordService(service)
 
-	g, _ := discord.Session.Guild(gID)
+	g, err := discord.Guild(gID)
+	if err != nil {
+		return
+	}
 
 	for _, m := range g.Members {
 		p.update(m.User, m.Nick)
@@ -156,10 +161,12 @@ func (p *nameTrackPlugin) testScan(gID string, service rikka.Service) {
 }
 
 func (p *nameTrackPlugin) scanAll(service rikka.Service) {
-	discord := service.(*rikka.Discord)
-
 	fmt.Println("scanning all")
-	for _, g := range discord.Session.State.Ready.Guilds {
+	rikka.DiscordService(service).ForEachSession(p.scanSession)
+}
+
+func (p *nameTrackPlugin) scanSession(s *discordgo.Session) {
+	for _, g := range s.State.Guilds {
 		if g.Unavailable {
 			continue
 		}
@@ -183,7 +190,7 @@ func (p *nameTrackPlugin) Help(bot *rikka.Bot, service rikka.Service, message ri
 	if detailed {
 		return nil
 	}
-	return rikka.CommandHelp(service, "names", "[@username]", "See a user's past usernames")
+	return rikka.CommandHelp(service, "names", "[@username]", bot.Translate(service, message, "names.help"))
 }
 
 func (p *nameTrackPlugin) Name() string {
@@ -0,0 +1,15 @@
+package neuralplugin
+
+import "github.com/ThyLeader/rikka"
+
+func init() {
+	rikka.RegisterMessages("en", map[string]string{
+		"neural.usage": "Please provide something to generate. eg. `%sgen shakespeare`",
+		"neural.title": "In an alternate universe...",
+	})
+
+	rikka.RegisterMessages("es", map[string]string{
+		"neural.usage": "Por favor indica algo que generar. ej. `%sgen shakespeare`",
+		"neural.title": "En un universo alternativo...",
+	})
+}
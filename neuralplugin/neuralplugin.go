@@ -30,17 +30,17 @@ func (p *neuralPlugin) Message(bot *rikka.Bot, service rikka.Service, message ri
 	service.Typing(message.Channel())
 	_, parts := rikka.ParseCommand(service, message)
 	if len(parts) < 1 {
-		service.SendMessage(message.Channel(), "Please provide something to generate. eg. `r.gen shakespeare`")
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "neural.usage", service.CommandPrefix()))
 		return
 	}
 
 	r, err := p.requestData(parts[0], "200")
 	if err != nil {
-		service.SendMessage(message.Channel(), fmt.Sprintf("There was an error! %s", err.Error()))
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
 		return
 	}
 	if r.Message != "" {
-		service.SendMessage(message.Channel(), fmt.Sprintf("There was an error! %s", r.Message))
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", r.Message))
 		return
 	}
 	service.SendMessageEmbed(message.Channel(), &discordgo.MessageEmbed{
@@ -52,7 +52,7 @@ func (p *neuralPlugin) Message(bot *rikka.Bot, service rikka.Service, message ri
 		Color: 0xff0000,
 		Fields: []*discordgo.MessageEmbedField{
 			&discordgo.MessageEmbedField{
-				Name:  "In an alternate universe...",
+				Name:  bot.Translate(service, message, "neural.title"),
 				Value: r.Data,
 			},
 		},
@@ -0,0 +1,25 @@
+package playedplugin
+
+import "github.com/ThyLeader/rikka"
+
+func init() {
+	rikka.RegisterMessages("en", map[string]string{
+		"played.help":        "Returns your most played games, or a users most played games if provided.",
+		"played.unseen":      "I haven't seen user %s.",
+		"played.empty":       "I do not have anything recorded for user %s.",
+		"played.description": "*First seen %s (%s), last update %s (%s)*",
+		"played.games":       "Games",
+		"played.footer":      "Data valid as of ",
+		"played.embed.error": "Unable to send embed %s",
+	})
+
+	rikka.RegisterMessages("es", map[string]string{
+		"played.help":        "Muestra tus juegos más jugados, o los de un usuario si se indica.",
+		"played.unseen":      "No he visto al usuario %s.",
+		"played.empty":       "No tengo nada registrado para el usuario %s.",
+		"played.description": "*Visto por primera vez %s (%s), última actualización %s (%s)*",
+		"played.games":       "Juegos",
+		"played.footer":      "Datos válidos a fecha de ",
+		"played.embed.error": "No se pudo enviar el embed %s",
+	})
+}
@@ -113,10 +113,26 @@ func (p *playedPlugin) Update(user string, entry string) {
 
 // Run is the background go routine that executes for the life of the plugin.
 func (p *playedPlugin) Run(bot *rikka.Bot, service rikka.Service) {
-	discord := service.(*rikka.Discord)
+	discord := rikka.DiscordService(service)
+
+	discord.ForEachSession(func(session *discordgo.Session) {
+		session.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
+			for _, g := range r.Guilds {
+				for _, pu := range g.Presences {
+					e := ""
+					if pu.Game != nil {
+						e = pu.Game.Name
+					}
+					p.Update(pu.User.ID, e)
+				}
+			}
+		})
+
+		session.AddHandler(func(s *discordgo.Session, g *discordgo.GuildCreate) {
+			if g.Unavailable {
+				return
+			}
 
-	discord.Session.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
-		for _, g := range r.Guilds {
 			for _, pu := range g.Presences {
 				e := ""
 				if pu.Game != nil {
@@ -124,89 +140,25 @@ func (p *playedPlugin) Run(bot *rikka.Bot, service rikka.Service) {
 				}
 				p.Update(pu.User.ID, e)
 			}
-		}
-	})
-
-	discord.Session.AddHandler(func(s *discordgo.Session, g *discordgo.GuildCreate) {
-		if g.Unavailable {
-			return
-		}
-
-		for _, pu := range g.Presences {
-			e := ""
-			if pu.Game != nil {
-				e = pu.Game.Name
-			}
-			p.Update(pu.User.ID, e)
-		}
-
-		// if s.Token == "Bot Mjc2MTIxNDk1MTIwNjQyMDQ4.C3cBnQ.rG3L8KQWVBRs0l3fYsDG_MbymOM" {
-		// 	return
-		// }
-
-		t, err := g.JoinedAt.Parse()
-		if err != nil {
-			fmt.Println("fuccccc")
-			return
-		}
-		if t.Before(time.Now().Add(-1 * time.Minute)) {
-			return
-		}
+		})
 
-		guildOwner, err := discord.Session.State.Member(g.ID, g.OwnerID)
-		if err != nil {
-			discord.Session.ChannelMessageSend("340326362432798720", "Unable to retrieve information on the guild owner")
-			return
-		}
-		gc, _ := discordgo.Timestamp(guildOwner.JoinedAt).Parse()
-
-		var userCount float32
-		var botCount float32
-		for _, e := range g.Members {
-			if e.User.Bot {
-				botCount++
-			} else {
-				userCount++
+		session.AddHandler(func(s *discordgo.Session, pr *discordgo.PresencesReplace) {
+			for _, pu := range *pr {
+				e := ""
+				if pu.Game != nil {
+					e = pu.Game.Name
+				}
+				p.Update(pu.User.ID, e)
 			}
-		}
-		percent := botCount / (userCount + botCount) * 100
-
-		discord.Session.ChannelMessageSendEmbed("340326362432798720", &discordgo.MessageEmbed{
-			Color: discord.UserColor(service.UserID(), "340326362432798720"),
-			Title: "Rikka joined a guild",
-			Fields: []*discordgo.MessageEmbedField{
-				&discordgo.MessageEmbedField{Name: "Name", Value: g.Name, Inline: true},
-				&discordgo.MessageEmbedField{Name: "ID", Value: g.ID, Inline: true},
-				&discordgo.MessageEmbedField{Name: "Owner name", Value: guildOwner.User.Username + "#" + guildOwner.User.Discriminator, Inline: true},
-				&discordgo.MessageEmbedField{Name: "Owner ID", Value: guildOwner.User.ID, Inline: true},
-				&discordgo.MessageEmbedField{Name: "Users", Value: fmt.Sprintf("%v", userCount), Inline: true},
-				&discordgo.MessageEmbedField{Name: "Bots", Value: fmt.Sprintf("%v", botCount), Inline: true},
-				&discordgo.MessageEmbedField{Name: "Percent", Value: fmt.Sprintf("%v", int(percent)) + "%", Inline: true},
-				&discordgo.MessageEmbedField{Name: "Created", Value: humanize.Time(gc), Inline: true},
-			},
-			Thumbnail: &discordgo.MessageEmbedThumbnail{
-				URL: discordgo.EndpointGuildIcon(g.ID, g.Icon), //"https://discordapp.com/api/guilds/" + parts[0] + "/icons/" + guild.Icon + ".jpg",
-			},
 		})
-		//service.SendMessage("286247106749136910", "Thyy has joined "+g.Name+" (")
-	})
 
-	discord.Session.AddHandler(func(s *discordgo.Session, pr *discordgo.PresencesReplace) {
-		for _, pu := range *pr {
+		session.AddHandler(func(s *discordgo.Session, pu *discordgo.PresenceUpdate) {
 			e := ""
 			if pu.Game != nil {
 				e = pu.Game.Name
 			}
 			p.Update(pu.User.ID, e)
-		}
-	})
-
-	discord.Session.AddHandler(func(s *discordgo.Session, pu *discordgo.PresenceUpdate) {
-		e := ""
-		if pu.Game != nil {
-			e = pu.Game.Name
-		}
-		p.Update(pu.User.ID, e)
+		})
 	})
 }
 
@@ -216,7 +168,7 @@ func (p *playedPlugin) Help(bot *rikka.Bot, service rikka.Service, message rikka
 		return nil
 	}
 
-	return rikka.CommandHelp(service, "played", "[@username]", "Returns your most played games, or a users most played games if provided.")
+	return rikka.CommandHelp(service, "played", "[@username]", bot.Translate(service, message, "played.help"))
 }
 
 func (p *playedPlugin) Message(bot *rikka.Bot, service rikka.Service, message rikka.Message) {
@@ -235,7 +187,7 @@ func (p *playedPlugin) Message(bot *rikka.Bot, service rikka.Service, message ri
 
 	mentions := message.Mentions()
 	if len(mentions) > 1 {
-		service.SendMessage(message.Channel(), "Please only mention one user")
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.mentions.one"))
 		return
 	}
 	var id string
@@ -252,7 +204,7 @@ func (p *playedPlugin) Message(bot *rikka.Bot, service rikka.Service, message ri
 			id = parts[0]
 			m, err := service.Member(message.GuildID(), id)
 			if err != nil {
-				service.SendMessage(message.Channel(), "There was an error! Please report this to the devs\n"+err.Error())
+				service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", "Please report this to the devs\n"+err.Error()))
 				log.Println(err.Error())
 				return
 			}
@@ -260,7 +212,7 @@ func (p *playedPlugin) Message(bot *rikka.Bot, service rikka.Service, message ri
 		case 0:
 			id = message.UserID()
 		default:
-			service.SendMessage(message.Channel(), "Please only search for one user at a time")
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "error.search.one"))
 			return
 		}
 	}
@@ -275,16 +227,16 @@ func (p *playedPlugin) Message(bot *rikka.Bot, service rikka.Service, message ri
 
 	u := p.Users[id]
 	if u == nil {
-		service.SendMessage(message.Channel(), fmt.Sprintf("I haven't seen user %s.", id))
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "played.unseen", id))
 		return
 	}
 
 	if len(u.Entries) == 0 {
-		service.SendMessage(message.Channel(), fmt.Sprintf("I do not have anything recorded for user %s.", id))
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "played.empty", id))
 		return
 	}
 
-	lc := humanize.Time(u.LastChanged)
+	lastChanged := u.LastChanged
 	u.Update(u.Current, time.Now())
 
 	pes := make(byDuration, len(u.Entries))
@@ -295,7 +247,6 @@ func (p *playedPlugin) Message(bot *rikka.Bot, service rikka.Service, message ri
 	}
 
 	sort.Sort(pes)
-	discord := service.(*rikka.Discord)
 	var statuses string
 
 	for i = 0; i < len(pes) && i < 5; i++ {
@@ -335,23 +286,23 @@ func (p *playedPlugin) Message(bot *rikka.Bot, service rikka.Service, message ri
 
 	embed := &discordgo.MessageEmbed{
 		Title:       title,
-		Description: fmt.Sprintf("*First seen %s, last update %s*", humanize.Time(u.FirstSeen), lc),
+		Description: bot.Translate(service, message, "played.description", humanize.Time(u.FirstSeen), bot.FormatTime(service, message, u.FirstSeen), humanize.Time(lastChanged), bot.FormatTime(service, message, lastChanged)),
 		Fields: []*discordgo.MessageEmbedField{
-			&discordgo.MessageEmbedField{Name: "Games", Value: statuses, Inline: false},
+			&discordgo.MessageEmbedField{Name: bot.Translate(service, message, "played.games"), Value: statuses, Inline: false},
 		},
 		Thumbnail: &discordgo.MessageEmbedThumbnail{
 			URL: url,
 		},
 		Color: 0x79c879,
 		Footer: &discordgo.MessageEmbedFooter{
-			Text: fmt.Sprintf("Data valid as of "),
+			Text: bot.Translate(service, message, "played.footer"),
 		},
 		Timestamp: fmt.Sprintf("%s", time.Now().Format(time.RFC3339)),
 	}
 
-	_, err := discord.Session.ChannelMessageSendEmbed(message.Channel(), embed)
+	_, err := service.SendMessageEmbed(message.Channel(), embed)
 	if err != nil {
-		service.SendMessage(message.Channel(), "Unable to send embed "+err.Error())
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "played.embed.error", err.Error()))
 	}
 	//service.SendMessage(message.Channel(), messageText)
 }
@@ -0,0 +1,13 @@
+package playingplugin
+
+import "github.com/ThyLeader/rikka"
+
+func init() {
+	rikka.RegisterMessages("en", map[string]string{
+		"playing.help": "Set which game %s is playing.",
+	})
+
+	rikka.RegisterMessages("es", map[string]string{
+		"playing.help": "Cambia el juego al que está jugando %s.",
+	})
+}
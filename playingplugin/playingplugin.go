@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/ThyLeader/rikka"
+	"github.com/bwmarrin/discordgo"
 )
 
 type playingPlugin struct {
@@ -29,42 +30,48 @@ func (p *playingPlugin) Load(bot *rikka.Bot, service rikka.Service, data []byte)
 		}
 	}
 
-	if p.Game != "" {
-		err := service.(*rikka.Discord).Session.UpdateStreamingStatus(0, p.Game, p.URL)
-		if err != nil {
-			fmt.Println(err.Error())
-		}
-	} else {
-		err := service.(*rikka.Discord).Session.UpdateStatus(0, p.Game)
-		if err != nil {
-			fmt.Println(err.Error())
+	p.updateStatus(service)
+
+	// The game is changed on every process, as each one runs its own shards.
+	rikka.DiscordService(service).OnBroadcast("playing", func(data string) {
+		if err := json.Unmarshal([]byte(data), p); err != nil {
+			log.Println("Error loading data", err)
+			return
 		}
+		p.updateStatus(service)
+	})
+
+	// The game is set again every hour, as Discord sometimes drops it after reconnecting.
+	scheduler := bot.Scheduler(service)
+	scheduler.Handle("playing", func(job *rikka.Job) {
+		p.updateStatus(service)
+	})
+	if err := scheduler.Schedule(&rikka.Job{
+		ID:       "playing",
+		Handler:  "playing",
+		Kind:     rikka.JobInterval,
+		Interval: time.Hour,
+		CatchUp:  rikka.CatchUpSkip,
+	}); err != nil {
+		log.Println("Error scheduling playing status", err)
 	}
 
-	go p.Persist(bot, service)
-
 	return nil
 }
 
-func (p *playingPlugin) Persist(bot *rikka.Bot, service rikka.Service) {
-	t := time.Tick(1 * time.Hour)
-
-	for {
-		select {
-		case <-t:
-			if p.Game != "" {
-				err := service.(*rikka.Discord).Session.UpdateStreamingStatus(0, p.Game, p.URL)
-				if err != nil {
-					fmt.Println(err.Error())
-				}
-			} else {
-				err := service.(*rikka.Discord).Session.UpdateStatus(0, p.Game)
-				if err != nil {
-					fmt.Println(err.Error())
-				}
-			}
+// updateStatus sets the game on every shard, presence is per session so each shard has to be updated.
+func (p *playingPlugin) updateStatus(service rikka.Service) {
+	rikka.DiscordService(service).ForEachSession(func(s *discordgo.Session) {
+		var err error
+		if p.URL != "" {
+			err = s.UpdateStreamingStatus(0, p.Game, p.URL)
+		} else {
+			err = s.UpdateStatus(0, p.Game)
 		}
-	}
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+	})
 }
 
 // Save will save plugin state to a byte array.
@@ -82,7 +89,7 @@ func (p *playingPlugin) helpFunc(bot *rikka.Bot, service rikka.Service, message
 		return nil
 	}
 
-	return rikka.CommandHelp(service, "playing", "<game>, <url>", fmt.Sprintf("Set which game %s is playing.", service.UserName()))
+	return rikka.CommandHelp(service, "playing", "<game>, <url>", bot.Translate(service, message, "playing.help", service.UserName()))
 }
 
 // Message handler.
@@ -104,18 +111,18 @@ func (p *playingPlugin) messageFunc(bot *rikka.Bot, service rikka.Service, messa
 
 	split := strings.Split(query, ",")
 
-	p.Game = strings.Trim(split[0], " ")
+	playing := playingPlugin{Game: strings.Trim(split[0], " ")}
 	if len(split) > 1 {
-		p.URL = strings.Trim(split[1], " ")
-		err := service.(*rikka.Discord).Session.UpdateStreamingStatus(0, p.Game, p.URL)
-		if err != nil {
-			fmt.Println(err.Error())
-		}
-	} else {
-		err := service.(*rikka.Discord).Session.UpdateStatus(0, p.Game)
-		if err != nil {
-			fmt.Println(err.Error())
-		}
+		playing.URL = strings.Trim(split[1], " ")
+	}
+
+	data, err := json.Marshal(playing)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+	if err := rikka.DiscordService(service).Broadcast("playing", string(data)); err != nil {
+		fmt.Println(err.Error())
 	}
 }
 
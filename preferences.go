@@ -0,0 +1,108 @@
+package rikka
+
+import (
+	"log"
+	"sync"
+
+	"github.com/go-redis/redis"
+)
+
+// userPreferences are the preferences users set for themselves, such as their language, by user ID.
+// They are stored in a Redis hash rather than with the plugin state, so every process of a cluster shares them,
+// and kept in memory, so reading them doesn't wait on Redis. A change is broadcast so every process reloads it.
+type userPreferences struct {
+	sync.RWMutex
+
+	key    string
+	values map[string]string
+}
+
+func newUserPreferences(key string) *userPreferences {
+	return &userPreferences{
+		key:    key,
+		values: map[string]string{},
+	}
+}
+
+// get returns the preference of a user, or false if they haven't set one.
+func (u *userPreferences) get(userID string) (string, bool) {
+	if userID == "" {
+		return "", false
+	}
+
+	u.RLock()
+	defer u.RUnlock()
+
+	v, ok := u.values[userID]
+	return v, ok
+}
+
+// set sets the preference of a user, an empty value removes it.
+func (u *userPreferences) set(service Service, userID, value string) error {
+	var err error
+	if value == "" {
+		err = client.HDel(u.key, userID).Err()
+	} else {
+		err = client.HSet(u.key, userID, value).Err()
+	}
+	if err != nil {
+		return err
+	}
+
+	u.store(userID, value)
+	if d := DiscordService(service); d != nil {
+		if err := d.Broadcast(u.key, userID); err != nil {
+			log.Println("Error broadcasting user preference", err)
+		}
+	}
+	return nil
+}
+
+func (u *userPreferences) store(userID, value string) {
+	u.Lock()
+	defer u.Unlock()
+
+	if value == "" {
+		delete(u.values, userID)
+		return
+	}
+	u.values[userID] = value
+}
+
+// load loads the preferences from Redis, and reloads the preference of a user when another process changes it.
+func (u *userPreferences) load(service Service) {
+	values, err := client.HGetAll(u.key).Result()
+	if err != nil {
+		log.Println("Error loading user preferences", err)
+	} else {
+		u.Lock()
+		for userID, v := range values {
+			u.values[userID] = v
+		}
+		u.Unlock()
+	}
+
+	if d := DiscordService(service); d != nil {
+		d.OnBroadcast(u.key, u.reload)
+	}
+}
+
+func (u *userPreferences) reload(userID string) {
+	v, err := client.HGet(u.key, userID).Result()
+	if err != nil && err != redis.Nil {
+		log.Println("Error reloading user preference", err)
+		return
+	}
+	u.store(userID, v)
+}
+
+// migrate moves preferences from older saves into Redis, preferences that were set since are kept.
+func (u *userPreferences) migrate(preferences map[string]string) {
+	for userID, v := range preferences {
+		if err := client.HSetNX(u.key, userID, v).Err(); err != nil {
+			log.Println("Error migrating user preference", err)
+			continue
+		}
+		delete(preferences, userID)
+	}
+}
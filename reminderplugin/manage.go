@@ -0,0 +1,242 @@
+package reminderplugin
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ThyLeader/rikka"
+	"github.com/dustin/go-humanize"
+)
+
+// How long after a reminder is sent it can be snoozed.
+const snoozeWindow = 30 * time.Minute
+
+// The time a reminder is snoozed for when no time is given.
+const defaultSnooze = 10 * time.Minute
+
+// The number of reminders shown by reminder list.
+const listSize = 20
+
+type delivery struct {
+	reminder *Reminder
+	sent     time.Time
+}
+
+func deliveryKey(channelID, userID string) string {
+	return channelID + ":" + userID
+}
+
+// deliver remembers a reminder that was sent to a channel so the users it was for can snooze it.
+func (p *ReminderPlugin) deliver(reminder *Reminder, channelID string, userIDs []string) {
+	p.Lock()
+	defer p.Unlock()
+
+	for k, d := range p.delivered {
+		if time.Since(d.sent) > snoozeWindow {
+			delete(p.delivered, k)
+		}
+	}
+	for _, userID := range userIDs {
+		p.delivered[deliveryKey(channelID, userID)] = &delivery{
+			reminder: reminder,
+			sent:     time.Now(),
+		}
+	}
+}
+
+// canManage returns whether the sender of a message can change a reminder.
+// Moderators can manage the reminders that are sent in their guild.
+func (p *ReminderPlugin) canManage(service rikka.Service, message rikka.Message, r *Reminder) bool {
+	if r.UserID == message.UserID() {
+		return true
+	}
+	return !r.IsPrivate && !service.IsPrivate(message) && r.GuildID == message.GuildID() && service.IsModerator(message)
+}
+
+// parseReminderID parses a reminder ID, with or without a leading #.
+func parseReminderID(s string) (int64, bool) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(s, "#"), 10, 64)
+	return id, err == nil && id > 0
+}
+
+func (p *ReminderPlugin) formatReminder(bot *rikka.Bot, service rikka.Service, message rikka.Message, r *Reminder) string {
+	repeats := ""
+	if r.Recurrence != nil {
+		repeats = bot.Translate(service, message, "reminder.list.repeats", r.Recurrence.Description)
+	}
+	return bot.Translate(service, message, "reminder.list.line", r.ID, bot.FormatTime(service, message, r.Time), humanize.Time(r.Time.Add(time.Second)), repeats, r.Message)
+}
+
+// list shows the reminders of the sender, or to moderators the reminders sent in their guild.
+func (p *ReminderPlugin) list(bot *rikka.Bot, service rikka.Service, message rikka.Message, parts []string) {
+	guild := len(parts) > 0 && (strings.ToLower(parts[0]) == "guild" || strings.ToLower(parts[0]) == "server" || strings.ToLower(parts[0]) == "all")
+	if guild {
+		if service.IsPrivate(message) {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "error.private"))
+			return
+		}
+		if !service.IsModerator(message) {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "error.moderator"))
+			return
+		}
+	}
+
+	reminders := []*Reminder{}
+	for _, r := range p.reminders() {
+		if (guild && !r.IsPrivate && r.GuildID == message.GuildID()) || (!guild && r.UserID == message.UserID()) {
+			reminders = append(reminders, r)
+		}
+	}
+
+	if len(reminders) == 0 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "reminder.list.none"))
+		return
+	}
+
+	lines := []string{}
+	for i, r := range reminders {
+		if i == listSize {
+			lines = append(lines, bot.Translate(service, message, "reminder.list.more", len(reminders)-i))
+			break
+		}
+		line := p.formatReminder(bot, service, message, r)
+		if guild {
+			line += " " + bot.Translate(service, message, "reminder.list.by", r.Requester, r.Target)
+		}
+		lines = append(lines, line)
+	}
+	service.SendMessage(message.Channel(), strings.Join(lines, "\n"))
+}
+
+func (p *ReminderPlugin) cancel(bot *rikka.Bot, service rikka.Service, message rikka.Message, parts []string) {
+	if len(parts) != 1 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "reminder.cancel.usage", service.CommandPrefix()))
+		return
+	}
+	id, ok := parseReminderID(parts[0])
+	if !ok {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "reminder.cancel.usage", service.CommandPrefix()))
+		return
+	}
+
+	r := p.reminder(id)
+	if r == nil || !p.canManage(service, message, r) || !p.scheduler.Cancel(reminderJobID(id)) {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "reminder.notfound", id))
+		return
+	}
+
+	service.SendMessage(message.Channel(), bot.Translate(service, message, "reminder.cancelled", id, r.Message))
+}
+
+// edit changes the time, the message, or both of a reminder.
+func (p *ReminderPlugin) edit(bot *rikka.Bot, service rikka.Service, message rikka.Message, parts []string) {
+	if len(parts) < 2 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "reminder.edit.usage", service.CommandPrefix()))
+		return
+	}
+	id, ok := parseReminderID(parts[0])
+	if !ok {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "reminder.edit.usage", service.CommandPrefix()))
+		return
+	}
+
+	now := time.Now().In(bot.MessageLocation(service, message))
+	recurrence, t, text, err := p.parse(parts[1:], now)
+	if err != nil {
+		// Anything that isn't a time is a new message.
+		recurrence, t, text = nil, time.Time{}, strings.Join(parts[1:], " ")
+	} else if !validTime(t, now) {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "reminder.invalid.time", strings.Join(randomTimes, ", ")))
+		return
+	}
+
+	// Moderators can cancel the reminders in their guild, but only the requester can change what a reminder says.
+	r := p.reminder(id)
+	if r == nil || r.UserID != message.UserID() {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "reminder.notfound", id))
+		return
+	}
+	removed := ""
+	if !t.IsZero() {
+		if r.Recurrence != nil && recurrence == nil {
+			removed = r.Recurrence.Description
+		}
+		r.Time = t
+		r.Recurrence = recurrence
+		r.Fired = 0
+	}
+	if text != "" {
+		r.Message = text
+	}
+	if err := p.schedule(r); err != nil {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+		return
+	}
+
+	msg := bot.Translate(service, message, "reminder.edited", p.formatReminder(bot, service, message, r))
+	if removed != "" {
+		msg += "\n" + bot.Translate(service, message, "reminder.edited.once", removed)
+	}
+	service.SendMessage(message.Channel(), msg)
+}
+
+// snooze sets a reminder that was just sent to be sent again.
+// Snoozing by reply is quiet when there is nothing to snooze, as "snooze" may just be part of a conversation.
+func (p *ReminderPlugin) snooze(bot *rikka.Bot, service rikka.Service, message rikka.Message, parts []string, reply bool) {
+	key := deliveryKey(message.Channel(), message.UserID())
+
+	p.RLock()
+	d := p.delivered[key]
+	var snoozed Reminder
+	if d != nil {
+		snoozed = *d.reminder
+	}
+	p.RUnlock()
+
+	if d == nil || time.Since(d.sent) > snoozeWindow {
+		if !reply {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "reminder.snooze.none"))
+		}
+		return
+	}
+
+	now := time.Now().In(bot.MessageLocation(service, message))
+	t := now.Add(defaultSnooze)
+	if len(parts) > 0 {
+		var err error
+		t, _, err = parseTime(parts, now)
+		if err != nil || !validTime(t, now) {
+			if !reply {
+				service.SendMessage(message.Channel(), bot.Translate(service, message, "reminder.invalid.time", strings.Join(randomTimes, ", ")))
+			}
+			return
+		}
+	}
+
+	// The snoozed reminder is only for the user that snoozed it.
+	if message.UserID() != snoozed.UserID {
+		snoozed.UserID = message.UserID()
+		snoozed.Requester = requester(service, message)
+	}
+	snoozed.Users = nil
+	snoozed.RoleID = ""
+	snoozed.Time = t
+	snoozed.Recurrence = nil
+	snoozed.Fired = 0
+	err := p.AddReminder(&snoozed)
+	if err == ErrTooManyReminders {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "reminder.toomany", maxReminders, service.CommandPrefix()))
+		return
+	}
+	if err != nil {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+		return
+	}
+
+	p.Lock()
+	delete(p.delivered, key)
+	p.Unlock()
+
+	service.SendMessage(message.Channel(), bot.Translate(service, message, "reminder.snoozed", snoozed.ID, t.Format(rikka.TimeFormat), humanize.Time(t.Add(time.Second))))
+}
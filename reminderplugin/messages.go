@@ -0,0 +1,99 @@
+package reminderplugin
+
+import "github.com/ThyLeader/rikka"
+
+func init() {
+	rikka.RegisterMessages("en", map[string]string{
+		"reminder.help":              "Sets a reminder that is sent at the provided time, eg. in 2h30m, at 17:30 or friday at noon.",
+		"reminder.help.examples":     "Examples: ",
+		"reminder.invalid":           "Invalid reminder, no time or message. eg: %s",
+		"reminder.invalid.time":      "Invalid time. eg: %s",
+		"reminder.invalid.message":   "Invalid reminder, no message. eg: %s",
+		"reminder.toomany":           "You already have %d reminders. See them with `%sreminder list` and cancel one with `reminder cancel <id>`.",
+		"reminder.set":               "Reminder `#%d` set for %s (%s).",
+		"reminder.private":           "%s you set a reminder: %s",
+		"reminder.public":            "%s %s set a reminder: %s",
+		"reminder.set.recurring":     "Reminder `#%d` set to repeat %s, first at %s (%s).",
+		"reminder.recurring.private": "Your recurring reminder (%s): %s",
+		"reminder.recurring.public":  "%s your recurring reminder (%s): %s",
+		"reminder.help.recurring":    "Sets a reminder that repeats, eg. every day at 9am, every monday and friday at 17:30 or every 2 weeks until june 1st.",
+		"reminder.help.cron":         "Sets a reminder that repeats on a cron schedule, eg. cron 0 9 * * 1-5 is every weekday at 9:00.",
+		"reminder.help.list":         "Lists your reminders, or to moderators the reminders in this server.",
+		"reminder.help.cancel":       "Cancels a reminder.",
+		"reminder.help.edit":         "Changes the time or the message of a reminder.",
+		"reminder.help.snooze":       "Sends the reminder you just got again later, 10 minutes by default. You can also just reply `snooze 10m`.",
+		"reminder.snooze.hint":       "*Reply `snooze 10m` to be reminded again.*",
+		"reminder.snooze.none":       "There is no recent reminder to snooze.",
+		"reminder.snoozed":           "Reminder `#%d` snoozed until %s (%s).",
+		"reminder.list.line":         "`#%d` %s (%s)%s: %s",
+		"reminder.list.repeats":      ", repeats %s",
+		"reminder.list.by":           "by %s in <#%s>",
+		"reminder.list.none":         "There are no reminders.",
+		"reminder.list.more":         "...and %d more.",
+		"reminder.notfound":          "Reminder `#%d` not found.",
+		"reminder.cancelled":         "Reminder `#%d` cancelled: %s",
+		"reminder.cancel.usage":      "Usage: `%sreminder cancel <id>`",
+		"reminder.edit.usage":        "Usage: `%sreminder edit <id> <time|reminder>`",
+		"reminder.edited":            "Reminder updated: %s",
+		"reminder.edited.once":       "It no longer repeats %s, use `every` in the new time to keep it recurring.",
+		"reminder.help.target":       "Sends the reminder by private message, to other users or a role, or to another channel.",
+		"reminder.jump":              "Set here: %s",
+		"reminder.fallback":          "*I couldn't send this reminder to <#%s>, so here it is.*",
+		"reminder.target.nodm":       "I can't send private messages here.",
+		"reminder.target.private":    "Other users, roles and channels can only be reminded in a server.",
+		"reminder.target.toomany":    "A reminder can only be for %d users.",
+		"reminder.target.channel":    "You can't send reminders to <#%s>.",
+		"reminder.target.user":       "<@%s> isn't in this server.",
+		"reminder.target.dmusers":    "Only moderators can remind other users by private message.",
+		"reminder.target.dmchannel":  "A reminder can be sent by private message or to a channel, not both.",
+		"reminder.target.dmrole":     "Roles can't be reminded by private message.",
+		"reminder.target.role":       "You can't mention that role.",
+	})
+
+	rikka.RegisterMessages("es", map[string]string{
+		"reminder.help":              "Crea un recordatorio que se envía en el momento indicado, p. ej. in 2h30m, at 17:30 o friday at noon.",
+		"reminder.help.examples":     "Ejemplos: ",
+		"reminder.invalid":           "Recordatorio inválido, falta el tiempo o el mensaje. ej: %s",
+		"reminder.invalid.time":      "Tiempo inválido. ej: %s",
+		"reminder.invalid.message":   "Recordatorio inválido, falta el mensaje. ej: %s",
+		"reminder.toomany":           "Ya tienes %d recordatorios. Puedes verlos con `%sreminder list` y cancelar uno con `reminder cancel <id>`.",
+		"reminder.set":               "Recordatorio `#%d` creado para %s (%s).",
+		"reminder.private":           "%s creaste un recordatorio: %s",
+		"reminder.public":            "%s %s creó un recordatorio: %s",
+		"reminder.set.recurring":     "Recordatorio `#%d` creado para repetirse %s, primero el %s (%s).",
+		"reminder.recurring.private": "Tu recordatorio periódico (%s): %s",
+		"reminder.recurring.public":  "%s tu recordatorio periódico (%s): %s",
+		"reminder.help.recurring":    "Crea un recordatorio que se repite, p. ej. every day at 9am, every monday and friday at 17:30 o every 2 weeks until june 1st.",
+		"reminder.help.cron":         "Crea un recordatorio que se repite según un horario cron, p. ej. cron 0 9 * * 1-5 es cada día laborable a las 9:00.",
+		"reminder.help.list":         "Muestra tus recordatorios, o a los moderadores los recordatorios de este servidor.",
+		"reminder.help.cancel":       "Cancela un recordatorio.",
+		"reminder.help.edit":         "Cambia el momento o el mensaje de un recordatorio.",
+		"reminder.help.snooze":       "Vuelve a enviar más tarde el recordatorio que acabas de recibir, 10 minutos por defecto. También puedes responder `snooze 10m`.",
+		"reminder.snooze.hint":       "*Responde `snooze 10m` para que te lo recuerde de nuevo.*",
+		"reminder.snooze.none":       "No hay ningún recordatorio reciente que posponer.",
+		"reminder.snoozed":           "Recordatorio `#%d` pospuesto hasta %s (%s).",
+		"reminder.list.line":         "`#%d` %s (%s)%s: %s",
+		"reminder.list.repeats":      ", se repite %s",
+		"reminder.list.by":           "de %s en <#%s>",
+		"reminder.list.none":         "No hay recordatorios.",
+		"reminder.list.more":         "...y %d más.",
+		"reminder.notfound":          "No se encontró el recordatorio `#%d`.",
+		"reminder.cancelled":         "Recordatorio `#%d` cancelado: %s",
+		"reminder.cancel.usage":      "Uso: `%sreminder cancel <id>`",
+		"reminder.edit.usage":        "Uso: `%sreminder edit <id> <momento|recordatorio>`",
+		"reminder.edited":            "Recordatorio actualizado: %s",
+		"reminder.edited.once":       "Ya no se repite %s, usa `every` en el nuevo momento para que siga repitiéndose.",
+		"reminder.help.target":       "Envía el recordatorio por mensaje privado, a otros usuarios o a un rol, o a otro canal.",
+		"reminder.jump":              "Creado aquí: %s",
+		"reminder.fallback":          "*No pude enviar este recordatorio a <#%s>, así que aquí lo tienes.*",
+		"reminder.target.nodm":       "Aquí no puedo enviar mensajes privados.",
+		"reminder.target.private":    "Solo se puede recordar a otros usuarios, roles y canales en un servidor.",
+		"reminder.target.toomany":    "Un recordatorio solo puede ser para %d usuarios.",
+		"reminder.target.channel":    "No puedes enviar recordatorios a <#%s>.",
+		"reminder.target.user":       "<@%s> no está en este servidor.",
+		"reminder.target.dmusers":    "Solo los moderadores pueden recordar algo a otros usuarios por mensaje privado.",
+		"reminder.target.dmchannel":  "Un recordatorio se puede enviar por mensaje privado o a un canal, no a ambos.",
+		"reminder.target.dmrole":     "No se puede recordar algo a un rol por mensaje privado.",
+		"reminder.target.role":       "No puedes mencionar ese rol.",
+	})
+}
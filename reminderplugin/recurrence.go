@@ -0,0 +1,370 @@
+package reminderplugin
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ThyLeader/rikka"
+)
+
+// ErrInvalidRecurrence is returned when a recurring reminder can't be understood.
+var ErrInvalidRecurrence = errors.New("invalid recurrence")
+
+// The shortest time between two firings of a recurring reminder.
+const minRecurrence = 5 * time.Minute
+
+var unitNames = map[timeUnit]string{
+	unitMinute: "minute",
+	unitHour:   "hour",
+	unitDay:    "day",
+	unitWeek:   "week",
+	unitMonth:  "month",
+	unitYear:   "year",
+}
+
+// A Recurrence describes when a recurring reminder fires again.
+// It repeats either every Every units, on a set of weekdays, or on a cron expression.
+type Recurrence struct {
+	// Description is the recurrence as it was written by the requester.
+	Description string
+
+	Every    int
+	Unit     string
+	Weekdays []time.Weekday
+	Cron     string
+
+	HasClock bool
+	Hour     int
+	Minute   int
+	// Day is the day of the month of the first firing, so monthly reminders stay on it in shorter months.
+	Day int
+
+	// Until and Times end the recurrence, either may be zero for no end.
+	Until time.Time
+	Times int
+}
+
+func (r *Recurrence) unit() timeUnit {
+	for u, name := range unitNames {
+		if name == r.Unit {
+			return u
+		}
+	}
+	return unitDay
+}
+
+func (r *Recurrence) hasWeekday(d time.Weekday) bool {
+	for _, w := range r.Weekdays {
+		if w == d {
+			return true
+		}
+	}
+	return false
+}
+
+// daysIn returns the number of days in a month.
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// calendar returns the date a number of months and days after a date, at a time of day.
+// Dates are built from their parts so they stay at the same time of day across daylight saving changes.
+func (r *Recurrence) calendar(t time.Time, months, days, hour, minute, second int) time.Time {
+	year, month, day := t.Date()
+	if months != 0 {
+		month += time.Month(months)
+		for month > time.December {
+			month -= 12
+			year++
+		}
+		day = r.Day
+		if d := daysIn(year, month); day > d {
+			day = d
+		}
+	}
+	return time.Date(year, month, day+days, hour, minute, second, 0, t.Location())
+}
+
+// step returns the months and days between firings of a calendar recurrence.
+func (r *Recurrence) step() (int, int) {
+	switch r.unit() {
+	case unitWeek:
+		return 0, 7 * r.Every
+	case unitMonth:
+		return r.Every, 0
+	case unitYear:
+		return 12 * r.Every, 0
+	}
+	return 0, r.Every
+}
+
+// nextWeekday returns the first time on one of the weekdays, at the time of day, after a time.
+func (r *Recurrence) nextWeekday(after time.Time, hour, minute, second int) time.Time {
+	for i := 0; i <= 7; i++ {
+		t := r.calendar(after, 0, i, hour, minute, second)
+		if t.After(after) && r.hasWeekday(t.Weekday()) {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// First returns the first firing of the recurrence after now, in the location of now.
+func (r *Recurrence) First(now time.Time) time.Time {
+	if r.Cron != "" {
+		return r.next(now)
+	}
+
+	hour, minute, second := now.Clock()
+	if r.HasClock {
+		hour, minute, second = r.Hour, r.Minute, 0
+	}
+	if r.Day == 0 {
+		r.Day = now.Day()
+	}
+
+	if len(r.Weekdays) > 0 {
+		return r.nextWeekday(now, hour, minute, second)
+	}
+
+	switch r.unit() {
+	case unitMinute:
+		return now.Add(time.Duration(r.Every) * time.Minute)
+	case unitHour:
+		return now.Add(time.Duration(r.Every) * time.Hour)
+	}
+
+	if r.HasClock {
+		// A time of day fires at its next occurrence, and then every step after it.
+		t := r.calendar(now, 0, 0, hour, minute, second)
+		if !t.After(now) {
+			t = r.calendar(now, 0, 1, hour, minute, second)
+		}
+		r.Day = t.Day()
+		return t
+	}
+	months, days := r.step()
+	return r.calendar(now, months, days, hour, minute, second)
+}
+
+// next returns the firing of the recurrence after the one at prev, in the location of prev.
+func (r *Recurrence) next(prev time.Time) time.Time {
+	if r.Cron != "" {
+		c, err := rikka.ParseCron(r.Cron)
+		if err != nil {
+			return time.Time{}
+		}
+		return c.Next(prev)
+	}
+
+	hour, minute, second := prev.Clock()
+	if r.HasClock {
+		hour, minute, second = r.Hour, r.Minute, 0
+	}
+
+	if len(r.Weekdays) > 0 {
+		return r.nextWeekday(prev, hour, minute, second)
+	}
+
+	switch r.unit() {
+	case unitMinute:
+		return prev.Add(time.Duration(r.Every) * time.Minute)
+	case unitHour:
+		return prev.Add(time.Duration(r.Every) * time.Hour)
+	}
+	months, days := r.step()
+	return r.calendar(prev, months, days, hour, minute, second)
+}
+
+// Next returns the firing after prev, or the zero time if the recurrence has ended.
+// fired is the number of times the reminder has fired so far.
+func (r *Recurrence) Next(prev time.Time, fired int) time.Time {
+	if r.Times > 0 && fired >= r.Times {
+		return time.Time{}
+	}
+	t := r.next(prev)
+	if t.IsZero() || (!r.Until.IsZero() && t.After(r.Until)) {
+		return time.Time{}
+	}
+	return t
+}
+
+// parseRecurrenceWeekdays parses lists of weekdays like "monday and friday", "mon,wed" or "weekdays".
+func parseRecurrenceWeekdays(parts []string) ([]time.Weekday, int) {
+	weekdays := []time.Weekday{}
+	n := 0
+	for n < len(parts) {
+		p := parts[n]
+		if p == "and" && len(weekdays) > 0 {
+			n++
+			continue
+		}
+		switch p {
+		case "weekday", "weekdays":
+			weekdays = append(weekdays, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+			n++
+			continue
+		case "weekend", "weekends":
+			weekdays = append(weekdays, time.Saturday, time.Sunday)
+			n++
+			continue
+		}
+
+		found := false
+		for _, name := range strings.Split(strings.Trim(p, ","), ",") {
+			name = strings.TrimSuffix(name, "s")
+			d, ok := parseWeekday(name)
+			if !ok {
+				found = false
+				break
+			}
+			weekdays = append(weekdays, d)
+			found = true
+		}
+		if !found {
+			break
+		}
+		n++
+	}
+
+	// A trailing "and" belongs to the message.
+	if n > 0 && parts[n-1] == "and" {
+		n--
+	}
+	return weekdays, n
+}
+
+// parseRecurrence parses a recurrence at the start of a reminder, like "every monday at 9am 3 times" or "cron 0 9 * * 1-5".
+// now is used to resolve end dates, in its location. It returns the recurrence and the number of parts that were used.
+func parseRecurrence(parts []string, now time.Time) (*Recurrence, int, error) {
+	lower := make([]string, len(parts))
+	for i, p := range parts {
+		lower[i] = strings.ToLower(p)
+	}
+	if len(lower) < 2 {
+		return nil, 0, ErrInvalidRecurrence
+	}
+
+	r := &Recurrence{Every: 1}
+	i := 1
+
+	switch lower[0] {
+	case "cron":
+		if len(lower) < 6 {
+			return nil, 0, ErrInvalidRecurrence
+		}
+		r.Cron = strings.Join(lower[1:6], " ")
+		if _, err := rikka.ParseCron(r.Cron); err != nil {
+			return nil, 0, err
+		}
+		i = 6
+	case "every":
+		if lower[i] == "other" {
+			r.Every = 2
+			i++
+		} else if n, err := strconv.Atoi(lower[i]); err == nil {
+			if n < 1 {
+				return nil, 0, ErrInvalidRecurrence
+			}
+			r.Every = n
+			i++
+		}
+		if i >= len(lower) {
+			return nil, 0, ErrInvalidRecurrence
+		}
+
+		if t, ok := partsOfDay[lower[i]]; ok && r.Every == 1 {
+			// "every morning" is every day in the morning.
+			r.Unit = unitNames[unitDay]
+			r.HasClock, r.Hour, r.Minute = true, t[0], t[1]
+			i++
+		} else if unit, ok := parseUnit(lower[i]); ok {
+			if unit == unitSecond {
+				return nil, 0, ErrInvalidRecurrence
+			}
+			r.Unit = unitNames[unit]
+			i++
+		} else if weekdays, n := parseRecurrenceWeekdays(lower[i:]); n > 0 {
+			if r.Every != 1 {
+				return nil, 0, ErrInvalidRecurrence
+			}
+			r.Weekdays = weekdays
+			i += n
+		} else {
+			return nil, 0, ErrInvalidRecurrence
+		}
+	default:
+		return nil, 0, ErrInvalidRecurrence
+	}
+
+	// The time of day, and the end of the recurrence.
+	for i < len(lower) {
+		p := lower[i]
+		next := ""
+		if i+1 < len(lower) {
+			next = lower[i+1]
+		}
+
+		if r.Cron == "" && !r.HasClock {
+			start, bare := i, false
+			if p == "at" && next != "" {
+				start, bare = i+1, true
+			}
+			if hour, minute, n, ok := parseClock(lower[start:], bare); ok {
+				r.HasClock, r.Hour, r.Minute = true, hour, minute
+				i = start + n
+				continue
+			}
+			switch lower[start] {
+			case "noon", "midday":
+				r.HasClock, r.Hour, r.Minute = true, 12, 0
+				i = start + 1
+				continue
+			case "midnight":
+				r.HasClock, r.Hour, r.Minute = true, 0, 0
+				i = start + 1
+				continue
+			}
+			if t, ok := partsOfDay[p]; ok {
+				r.HasClock, r.Hour, r.Minute = true, t[0], t[1]
+				i++
+				continue
+			}
+		}
+
+		if p == "until" && r.Until.IsZero() && next != "" {
+			t, n, err := parseTime(parts[i+1:], now)
+			if err != nil {
+				return nil, 0, err
+			}
+			r.Until = t
+			i += n + 1
+			continue
+		}
+
+		if r.Times == 0 {
+			start := i
+			if p == "for" {
+				start = i + 1
+			}
+			if start+1 < len(lower) && lower[start+1] == "times" {
+				if n, err := strconv.Atoi(lower[start]); err == nil && n > 0 {
+					r.Times = n
+					i = start + 2
+					continue
+				}
+			}
+		}
+
+		break
+	}
+
+	if r.HasClock && (r.unit() == unitMinute || r.unit() == unitHour) && len(r.Weekdays) == 0 && r.Cron == "" {
+		return nil, 0, ErrInvalidRecurrence
+	}
+
+	r.Description = strings.Join(parts[:i], " ")
+	return r, i, nil
+}
@@ -17,29 +17,112 @@ import (
 
 // A Reminder holds data about a specific reminder.
 type Reminder struct {
+	ID        int64
 	StartTime time.Time
 	Time      time.Time
 	Requester string
 	Target    string
 	Message   string
 	IsPrivate bool
+	GuildID   string
+	UserID    string
+	// Location is the time zone of the requester, recurring reminders are calculated in it.
+	Location string
+	// Recurrence is nil for reminders that only fire once.
+	Recurrence *Recurrence
+	Fired      int
+	// DM sends the reminder by private message instead of to Target.
+	DM bool
+	// Users and RoleID are who the reminder is for when it isn't only for the requester.
+	Users  []string
+	RoleID string
+	// ChannelID and MessageID are the message that set the reminder, the reminder links back to it.
+	ChannelID string
+	MessageID string
 }
 
+func (r *Reminder) location() *time.Location {
+	if l, err := rikka.LoadLocation(r.Location); err == nil {
+		return l
+	}
+	return time.UTC
+}
+
+// The number of reminders a user can have.
+const maxReminders = 10
+
+// The name of the scheduler handler that sends reminders.
+const reminderHandler = "reminder"
+
 // ReminderPlugin is a plugin that reminds users.
 type ReminderPlugin struct {
 	sync.RWMutex
-	bot            *rikka.Bot
-	Reminders      []*Reminder
+	bot       *rikka.Bot
+	scheduler *rikka.Scheduler
+	// Reminders is only read to move reminders from older saves onto the scheduler, where they are stored.
+	Reminders      []*Reminder `json:",omitempty"`
 	TotalReminders int
+	NextID         int64
+	// delivered holds recently sent reminders so they can be snoozed, by channel and user.
+	delivered map[string]*delivery
+}
+
+func reminderJobID(id int64) string {
+	return reminderHandler + ":" + strconv.FormatInt(id, 10)
+}
+
+// schedule schedules a reminder to be sent at its time, replacing it if it was already scheduled.
+func (p *ReminderPlugin) schedule(reminder *Reminder) error {
+	job := &rikka.Job{
+		ID:      reminderJobID(reminder.ID),
+		Handler: reminderHandler,
+		Kind:    rikka.JobOnce,
+		Time:    reminder.Time,
+		CatchUp: rikka.CatchUpOnce,
+	}
+	if err := job.SetPayload(reminder); err != nil {
+		return err
+	}
+	return p.scheduler.Schedule(job)
+}
+
+// reminder returns a pending reminder, or nil if it doesn't exist.
+func (p *ReminderPlugin) reminder(id int64) *Reminder {
+	job := p.scheduler.Job(reminderJobID(id))
+	if job == nil {
+		return nil
+	}
+	r := &Reminder{}
+	if err := job.Decode(r); err != nil {
+		return nil
+	}
+	return r
+}
+
+// reminders returns the pending reminders, ordered by time.
+func (p *ReminderPlugin) reminders() []*Reminder {
+	reminders := []*Reminder{}
+	for _, job := range p.scheduler.Jobs(reminderHandler) {
+		r := &Reminder{}
+		if err := job.Decode(r); err != nil {
+			log.Println("Error decoding reminder", err)
+			continue
+		}
+		reminders = append(reminders, r)
+	}
+	return reminders
 }
 
 var randomTimes = []string{
-	"1 minute",
-	"10 minutes",
-	"1 hour",
-	"4 hours",
-	"tomorrow",
+	"in 10 minutes",
+	"in 2h30m",
+	"at 17:30",
+	"tomorrow morning",
+	"friday at noon",
+	"on december 1st at 9am",
 	"next week",
+	"every day at 9am",
+	"every monday and friday at 17:30",
 }
 
 var randomMessages = []string{
@@ -66,11 +149,18 @@ func (p *ReminderPlugin) randomReminder(service rikka.Service) string {
 // Help returns a list of help strings that are printed when the user requests them.
 func (p *ReminderPlugin) Help(bot *rikka.Bot, service rikka.Service, message rikka.Message, detailed bool) []string {
 	help := []string{
-		rikka.CommandHelp(service, "reminder", "<time> <reminder>", "Sets a reminder that is sent after the provided time.")[0],
+		rikka.CommandHelp(service, "reminder", "<time> <reminder>", bot.Translate(service, message, "reminder.help"))[0],
 	}
 	if detailed {
 		help = append(help, []string{
-			"Examples: ",
+			rikka.CommandHelp(service, "reminder", "every <interval|weekdays> [at <time>] [until <date>|<n> times] <reminder>", bot.Translate(service, message, "reminder.help.recurring"))[0],
+			rikka.CommandHelp(service, "reminder", "cron <minute> <hour> <day> <month> <weekday> <reminder>", bot.Translate(service, message, "reminder.help.cron"))[0],
+			rikka.CommandHelp(service, "remind", "[dm] [@user|@role] [#channel] <time> <reminder>", bot.Translate(service, message, "reminder.help.target"))[0],
+			rikka.CommandHelp(service, "reminder", "list [guild]", bot.Translate(service, message, "reminder.help.list"))[0],
+			rikka.CommandHelp(service, "reminder", "cancel <id>", bot.Translate(service, message, "reminder.help.cancel"))[0],
+			rikka.CommandHelp(service, "reminder", "edit <id> <time|reminder>", bot.Translate(service, message, "reminder.help.edit"))[0],
+			rikka.CommandHelp(service, "reminder", "snooze [time]", bot.Translate(service, message, "reminder.help.snooze"))[0],
+			bot.Translate(service, message, "reminder.help.examples"),
 			p.randomReminder(service),
 			p.randomReminder(service),
 		}...)
@@ -78,75 +168,78 @@ func (p *ReminderPlugin) Help(bot *rikka.Bot, service rikka.Service, message rik
 	return help
 }
 
-func (p *ReminderPlugin) parseReminder(parts []string) (time.Duration, string, error) {
-	if parts[0] == "tomorrow" {
-		return time.Duration(1 * time.Hour * 24), strings.Join(parts[1:], " "), nil
-	}
-
-	if parts[0] == "next" {
-		switch parts[1] {
-		case "week":
-			return time.Duration(1 * time.Hour * 24 * 7), strings.Join(parts[2:], " "), nil
-		case "month":
-			return time.Duration(1 * time.Hour * 24 * 7 * 4), strings.Join(parts[2:], " "), nil
-		case "year":
-			return time.Duration(1 * time.Hour * 24 * 365), strings.Join(parts[2:], " "), nil
-		default:
-			return time.Duration(0), "", errors.New("Invalid next.")
-		}
+// requester returns how the sender of a message is named in the reminders they set.
+func requester(service rikka.Service, message rikka.Message) string {
+	if service.Name() == rikka.DiscordServiceName {
+		return fmt.Sprintf("<@%s>", message.UserID())
 	}
+	return message.UserName()
+}
 
-	i, err := strconv.Atoi(parts[0])
+// parseReminder parses the time and message of a reminder.
+func (p *ReminderPlugin) parseReminder(parts []string, now time.Time) (time.Time, string, error) {
+	t, n, err := parseTime(parts, now)
 	if err != nil {
-		return time.Duration(0), "", err
+		return time.Time{}, "", err
 	}
+	return t, strings.Join(parts[n:], " "), nil
+}
 
-	switch {
-	case strings.HasPrefix(parts[1], "sec"):
-		return time.Duration(time.Duration(i) * time.Second), strings.Join(parts[2:], " "), nil
-	case strings.HasPrefix(parts[1], "min"):
-		return time.Duration(time.Duration(i) * time.Minute), strings.Join(parts[2:], " "), nil
-	case strings.HasPrefix(parts[1], "hour"):
-		return time.Duration(time.Duration(i) * time.Hour), strings.Join(parts[2:], " "), nil
-	case strings.HasPrefix(parts[1], "day"):
-		return time.Duration(time.Duration(i) * time.Hour * 24), strings.Join(parts[2:], " "), nil
-	case strings.HasPrefix(parts[1], "week"):
-		return time.Duration(time.Duration(i) * time.Hour * 24 * 7), strings.Join(parts[2:], " "), nil
-	case strings.HasPrefix(parts[1], "month"):
-		return time.Duration(time.Duration(i) * time.Hour * 24 * 7 * 4), strings.Join(parts[2:], " "), nil
-	case strings.HasPrefix(parts[1], "year"):
-		return time.Duration(time.Duration(i) * time.Hour * 24 * 365), strings.Join(parts[2:], " "), nil
-	}
-
-	return time.Duration(0), "", errors.New("Invalid string.")
+// parseRecurringReminder parses the recurrence and message of a recurring reminder, and returns its first firing.
+func (p *ReminderPlugin) parseRecurringReminder(parts []string, now time.Time) (*Recurrence, time.Time, string, error) {
+	recurrence, n, err := parseRecurrence(parts, now)
+	if err != nil {
+		return nil, time.Time{}, "", err
+	}
+
+	t := recurrence.First(now)
+	if t.IsZero() || (!recurrence.Until.IsZero() && t.After(recurrence.Until)) {
+		return nil, time.Time{}, "", ErrInvalidRecurrence
+	}
+	// Reminders that would fire too often are refused, as they would flood the channel.
+	if next := recurrence.next(t); !next.IsZero() && next.Sub(t) < minRecurrence {
+		return nil, time.Time{}, "", ErrInvalidRecurrence
+	}
+	return recurrence, t, strings.Join(parts[n:], " "), nil
+}
+
+// parse parses the time and message of a one-shot or recurring reminder.
+func (p *ReminderPlugin) parse(parts []string, now time.Time) (*Recurrence, time.Time, string, error) {
+	if first := strings.ToLower(parts[0]); first == "every" || first == "cron" {
+		return p.parseRecurringReminder(parts, now)
+	}
+	t, r, err := p.parseReminder(parts, now)
+	return nil, t, r, err
+}
+
+// validTime returns whether a reminder can be set for a time.
+func validTime(t, now time.Time) bool {
+	return !t.Before(now) && !t.After(now.Add(time.Hour*24*365*5+time.Hour))
 }
 
+// ErrTooManyReminders is returned by AddReminder if the requester has reached the reminder limit.
+var ErrTooManyReminders = errors.New("too many reminders")
+
 // AddReminder adds a reminder.
 func (p *ReminderPlugin) AddReminder(reminder *Reminder) error {
 	p.Lock()
 	defer p.Unlock()
 
 	i := 0
-	for _, r := range p.Reminders {
-		if r.Requester == reminder.Requester {
+	for _, r := range p.reminders() {
+		if r.UserID == reminder.UserID {
 			i++
-			if i > 10 {
-				return errors.New("You have too many reminders already.")
+			if i >= maxReminders {
+				return ErrTooManyReminders
 			}
 		}
 	}
 
-	i = 0
-	for _, r := range p.Reminders {
-		if r.Time.After(reminder.Time) {
-			break
-		}
-		i++
+	p.NextID++
+	reminder.ID = p.NextID
+	if err := p.schedule(reminder); err != nil {
+		return err
 	}
-
-	p.Reminders = append(p.Reminders, reminder)
-	copy(p.Reminders[i+1:], p.Reminders[i:])
-	p.Reminders[i] = reminder
 	p.TotalReminders++
 
 	return nil
@@ -160,89 +253,205 @@ func (p *ReminderPlugin) Message(bot *rikka.Bot, service rikka.Service, message
 	}
 
 	if !rikka.MatchesCommand(service, "remind", message) && !rikka.MatchesCommand(service, "reminder", message) {
+		// Delivered reminders can be snoozed by replying "snooze 10m".
+		if fields := strings.Fields(message.Message()); len(fields) > 0 && strings.ToLower(fields[0]) == "snooze" {
+			p.snooze(bot, service, message, fields[1:], true)
+		}
 		return
 	}
 
 	_, parts := rikka.ParseCommand(service, message)
 
-	if len(parts) < 2 {
-		service.SendMessage(message.Channel(), fmt.Sprintf("Invalid reminder, no time or message. eg: %s", p.randomReminder(service)))
+	if len(parts) > 0 {
+		switch strings.ToLower(parts[0]) {
+		case "list":
+			p.list(bot, service, message, parts[1:])
+			return
+		case "cancel", "delete", "remove":
+			p.cancel(bot, service, message, parts[1:])
+			return
+		case "edit":
+			p.edit(bot, service, message, parts[1:])
+			return
+		case "snooze":
+			p.snooze(bot, service, message, parts[1:], false)
+			return
+		}
+	}
+
+	// Mentions are read from the raw message, the message of the reminder has them replaced like any other command.
+	discord := rikka.DiscordService(service)
+	if discord != nil {
+		_, parts = rikka.ParseCommandString(service, message.RawMessage())
+	}
+
+	target, n, denied := parseTarget(bot, service, message, parts)
+	if denied != "" {
+		service.SendMessage(message.Channel(), denied)
 		return
 	}
+	parts = parts[n:]
 
-	d, r, err := p.parseReminder(parts)
+	if len(parts) < 2 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "reminder.invalid", p.randomReminder(service)))
+		return
+	}
 
-	now := time.Now()
-	hum := humanize.Time(time.Now().Add(d + time.Duration(1*time.Second)))
-	t := time.Now().Add(d)
+	location := bot.MessageLocation(service, message)
+	now := time.Now().In(location)
 
-	if err != nil || t.Before(now) || t.After(now.Add(time.Hour*24*365*5+time.Hour)) {
-		service.SendMessage(message.Channel(), fmt.Sprintf("Invalid time. eg: %s", strings.Join(randomTimes, ", ")))
+	recurrence, t, r, err := p.parse(parts, now)
+	if err != nil || !validTime(t, now) {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "reminder.invalid.time", strings.Join(randomTimes, ", ")))
 		return
 	}
 
+	if discord != nil {
+		r = discord.ReplaceMentions(message, r)
+	}
 	if r == "" {
-		service.SendMessage(message.Channel(), fmt.Sprintf("Invalid reminder, no message. eg: %s", p.randomReminder(service)))
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "reminder.invalid.message", p.randomReminder(service)))
 		return
 	}
 
-	requester := message.UserName()
-	if service.Name() == rikka.DiscordServiceName {
-		requester = fmt.Sprintf("<@%s>", message.UserID())
+	guildID := ""
+	if !service.IsPrivate(message) {
+		guildID = message.GuildID()
 	}
 
-	err = p.AddReminder(&Reminder{
-		StartTime: now,
-		Time:      t,
-		Requester: requester,
-		Target:    message.Channel(),
-		Message:   r,
-		IsPrivate: service.IsPrivate(message),
-	})
+	reminder := &Reminder{
+		StartTime:  now,
+		Time:       t,
+		Requester:  requester(service, message),
+		Target:     message.Channel(),
+		Message:    r,
+		IsPrivate:  service.IsPrivate(message),
+		GuildID:    guildID,
+		UserID:     message.UserID(),
+		Location:   location.String(),
+		Recurrence: recurrence,
+		DM:         target.dm,
+		Users:      target.users,
+		RoleID:     target.roleID,
+		ChannelID:  message.Channel(),
+		MessageID:  message.MessageID(),
+	}
+	if target.channelID != "" {
+		reminder.Target = target.channelID
+	}
+	err = p.AddReminder(reminder)
+	if err == ErrTooManyReminders {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "reminder.toomany", maxReminders, service.CommandPrefix()))
+		return
+	}
 	if err != nil {
 		service.SendMessage(message.Channel(), err.Error())
 		return
 	}
 
-	service.SendMessage(message.Channel(), fmt.Sprintf("Reminder set for %s.", hum))
+	hum := humanize.Time(t.Add(time.Second))
+	if recurrence != nil {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "reminder.set.recurring", reminder.ID, recurrence.Description, t.Format(rikka.TimeFormat), hum))
+		return
+	}
+	service.SendMessage(message.Channel(), bot.Translate(service, message, "reminder.set", reminder.ID, t.Format(rikka.TimeFormat), hum))
 }
 
-// SendReminder sends a reminder.
-func (p *ReminderPlugin) SendReminder(service rikka.Service, reminder *Reminder) {
-	if reminder.IsPrivate {
-		service.SendMessage(reminder.Target, fmt.Sprintf("%s you set a reminder: %s", humanize.Time(reminder.StartTime), reminder.Message))
-	} else {
-		service.SendMessage(reminder.Target, fmt.Sprintf("%s %s set a reminder: %s", humanize.Time(reminder.StartTime), reminder.Requester, reminder.Message))
+// text returns the text of a reminder, private is whether it is sent by private message to the requester.
+func (p *ReminderPlugin) text(service rikka.Service, reminder *Reminder, private bool) string {
+	var text string
+	switch {
+	case reminder.Recurrence != nil && private:
+		text = p.bot.TranslateGuild(service, reminder.GuildID, reminder.UserID, "reminder.recurring.private", reminder.Recurrence.Description, reminder.Message)
+	case reminder.Recurrence != nil:
+		text = p.bot.TranslateGuild(service, reminder.GuildID, reminder.UserID, "reminder.recurring.public", reminder.Requester, reminder.Recurrence.Description, reminder.Message)
+	case private:
+		text = p.bot.TranslateGuild(service, reminder.GuildID, reminder.UserID, "reminder.private", humanize.Time(reminder.StartTime), reminder.Message)
+	default:
+		text = p.bot.TranslateGuild(service, reminder.GuildID, reminder.UserID, "reminder.public", humanize.Time(reminder.StartTime), reminder.Requester, reminder.Message)
 	}
+	if service.Name() == rikka.DiscordServiceName && reminder.MessageID != "" {
+		text += "\n" + p.bot.TranslateGuild(service, reminder.GuildID, reminder.UserID, "reminder.jump", rikka.MessageLink(reminder.GuildID, reminder.ChannelID, reminder.MessageID))
+	}
+	return text + "\n" + p.bot.TranslateGuild(service, reminder.GuildID, reminder.UserID, "reminder.snooze.hint")
+}
+
+// sendPrivate sends a reminder to a user by private message.
+func (p *ReminderPlugin) sendPrivate(service rikka.Service, reminder *Reminder, userID, note string) {
+	text := p.text(service, reminder, userID == reminder.UserID)
+	if note != "" {
+		text = note + "\n" + text
+	}
+	m, err := service.PrivateMessage(userID, text)
+	if err != nil {
+		log.Println("Error sending reminder", err)
+		return
+	}
+	p.deliver(reminder, m.ChannelID, []string{userID})
 }
 
-// Run will block until a reminder needs to be fired and then fire it.
-func (p *ReminderPlugin) Run(bot *rikka.Bot, service rikka.Service) {
-	for {
-		p.RLock()
+// SendReminder sends a reminder.
+// Reminders that can't be sent to their channel are sent by private message to the users they are for.
+func (p *ReminderPlugin) SendReminder(service rikka.Service, reminder *Reminder) {
+	recipients := reminder.recipients()
+
+	if reminder.DM && !reminder.IsPrivate {
+		for _, userID := range recipients {
+			p.sendPrivate(service, reminder, userID, "")
+		}
+		return
+	}
 
-		if len(p.Reminders) > 0 {
-			reminder := p.Reminders[0]
-			if time.Now().After(reminder.Time) {
-				p.RUnlock()
+	text := p.text(service, reminder, reminder.IsPrivate)
+	if mentions := reminder.mentions(); mentions != "" {
+		text = mentions + " " + text
+	}
+	if _, err := service.SendMessage(reminder.Target, text); err == nil {
+		p.deliver(reminder, reminder.Target, append([]string{reminder.UserID}, recipients...))
+		return
+	}
 
-				p.SendReminder(service, reminder)
+	if reminder.IsPrivate || !service.SupportsPrivateMessages() {
+		return
+	}
+	note := p.bot.TranslateGuild(service, reminder.GuildID, reminder.UserID, "reminder.fallback", reminder.Target)
+	for _, userID := range recipients {
+		p.sendPrivate(service, reminder, userID, note)
+	}
+}
 
-				p.Lock()
-				p.Reminders = p.Reminders[1:]
-				p.Unlock()
+// fire sends a reminder when its job runs, and schedules the next firing of recurring reminders.
+func (p *ReminderPlugin) fire(service rikka.Service, job *rikka.Job) {
+	reminder := &Reminder{}
+	if err := job.Decode(reminder); err != nil {
+		log.Println("Error decoding reminder", err)
+		return
+	}
 
-				continue
-			}
-		}
+	p.SendReminder(service, reminder)
 
-		p.RUnlock()
-		time.Sleep(500 * time.Millisecond)
+	if reminder.Recurrence == nil {
+		return
+	}
+	reminder.Fired++
+	next := reminder.Recurrence.Next(reminder.Time.In(reminder.location()), reminder.Fired)
+	// Firings that were missed while the bot wasn't running are skipped, so they aren't all sent at once.
+	for !next.IsZero() && !next.After(time.Now()) {
+		next = reminder.Recurrence.Next(next, reminder.Fired)
+	}
+	if next.IsZero() {
+		return
+	}
+	reminder.Time = next
+	if err := p.schedule(reminder); err != nil {
+		log.Println("Error scheduling reminder", err)
 	}
 }
 
 // Load will load plugin state from a byte array.
 func (p *ReminderPlugin) Load(bot *rikka.Bot, service rikka.Service, data []byte) error {
+	p.bot = bot
+
 	if data != nil {
 		if err := json.Unmarshal(data, p); err != nil {
 			log.Println("Error loading data", err)
@@ -251,8 +460,23 @@ func (p *ReminderPlugin) Load(bot *rikka.Bot, service rikka.Service, data []byte
 	if len(p.Reminders) > p.TotalReminders {
 		p.TotalReminders = len(p.Reminders)
 	}
+	p.scheduler = bot.Scheduler(service)
+	p.scheduler.Handle(reminderHandler, func(job *rikka.Job) {
+		p.fire(service, job)
+	})
+
+	for _, r := range p.Reminders {
+		// Reminders from before IDs were added are numbered.
+		if r.ID == 0 {
+			p.NextID++
+			r.ID = p.NextID
+		}
+		if err := p.schedule(r); err != nil {
+			log.Println("Error scheduling reminder", err)
+		}
+	}
+	p.Reminders = nil
 
-	go p.Run(bot, service)
 	return nil
 }
 
@@ -275,5 +499,6 @@ func (p *ReminderPlugin) Name() string {
 func New() rikka.Plugin {
 	return &ReminderPlugin{
 		Reminders: []*Reminder{},
+		delivered: map[string]*delivery{},
 	}
 }
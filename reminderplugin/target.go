@@ -0,0 +1,176 @@
+package reminderplugin
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ThyLeader/rikka"
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	userMentionRegexp    = regexp.MustCompile(`^<@!?([0-9]+)>$`)
+	roleMentionRegexp    = regexp.MustCompile(`^<@&([0-9]+)>$`)
+	channelMentionRegexp = regexp.MustCompile(`^<#([0-9]+)>$`)
+)
+
+// The most users a reminder can mention.
+const maxReminderUsers = 10
+
+// A target is where a reminder is sent and who it reminds, as given before its time.
+type target struct {
+	dm        bool
+	channelID string
+	users     []string
+	roleID    string
+}
+
+// parseTarget parses who a reminder is for and where it is sent, eg. "me", "dm", "<@user>", "<@&role>" or "<#channel>".
+// Mentions are only understood on Discord, so parts should be the raw parts of the message.
+// It returns the target and the number of parts that were used, or a message for the requester if the target isn't allowed.
+func parseTarget(bot *rikka.Bot, service rikka.Service, message rikka.Message, parts []string) (*target, int, string) {
+	t := &target{}
+	self := false
+	n := 0
+	for ; n < len(parts); n++ {
+		p := strings.Trim(parts[n], ",")
+		lower := strings.ToLower(p)
+
+		switch {
+		case lower == "me":
+			self = true
+		case lower == "dm" || lower == "privately":
+			t.dm = true
+		case lower == "and" && n > 0 && n+1 < len(parts) && isMention(strings.Trim(parts[n+1], ",")):
+		case userMentionRegexp.MatchString(p):
+			t.users = append(t.users, userMentionRegexp.FindStringSubmatch(p)[1])
+		case roleMentionRegexp.MatchString(p) && t.roleID == "":
+			t.roleID = roleMentionRegexp.FindStringSubmatch(p)[1]
+		case channelMentionRegexp.MatchString(p) && t.channelID == "":
+			t.channelID = channelMentionRegexp.FindStringSubmatch(p)[1]
+		default:
+			return t, n, t.check(bot, service, message, self)
+		}
+	}
+	return t, n, t.check(bot, service, message, self)
+}
+
+// check returns a message for the requester if they can't send a reminder to the target.
+func (t *target) check(bot *rikka.Bot, service rikka.Service, message rikka.Message, self bool) string {
+	if len(t.users) > 0 && self {
+		t.users = append(t.users, message.UserID())
+	}
+	t.users = dedupe(t.users)
+	if len(t.users) == 1 && t.users[0] == message.UserID() {
+		t.users = nil
+	}
+
+	if t.dm && !service.SupportsPrivateMessages() {
+		return bot.Translate(service, message, "reminder.target.nodm")
+	}
+	if len(t.users) == 0 && t.roleID == "" && t.channelID == "" {
+		return ""
+	}
+	if service.IsPrivate(message) {
+		return bot.Translate(service, message, "reminder.target.private")
+	}
+	if len(t.users) > maxReminderUsers {
+		return bot.Translate(service, message, "reminder.target.toomany", maxReminderUsers)
+	}
+
+	discord := rikka.DiscordService(service)
+	if discord == nil {
+		return bot.Translate(service, message, "reminder.target.private")
+	}
+
+	channelID := message.Channel()
+	if t.channelID != "" {
+		if t.dm {
+			return bot.Translate(service, message, "reminder.target.dmchannel")
+		}
+		c, err := service.Channel(t.channelID)
+		if err != nil || c.GuildID != message.GuildID() {
+			return bot.Translate(service, message, "reminder.target.channel", t.channelID)
+		}
+		if p, err := discord.UserChannelPermissions(message.UserID(), t.channelID); err != nil || p&discordgo.PermissionSendMessages != discordgo.PermissionSendMessages {
+			return bot.Translate(service, message, "reminder.target.channel", t.channelID)
+		}
+		channelID = t.channelID
+	}
+
+	for _, userID := range t.users {
+		if _, err := service.Member(message.GuildID(), userID); err != nil {
+			return bot.Translate(service, message, "reminder.target.user", userID)
+		}
+	}
+	// Anyone can mention others in a channel, but only moderators can send them private messages.
+	if t.dm && len(t.users) > 0 && !service.IsModerator(message) {
+		return bot.Translate(service, message, "reminder.target.dmusers")
+	}
+
+	if t.roleID != "" {
+		if t.dm {
+			return bot.Translate(service, message, "reminder.target.dmrole")
+		}
+		g, err := discord.Guild(message.GuildID())
+		if err != nil {
+			return bot.Translate(service, message, "reminder.target.role")
+		}
+		var role *discordgo.Role
+		for _, r := range g.Roles {
+			if r.ID == t.roleID {
+				role = r
+				break
+			}
+		}
+		if role == nil {
+			return bot.Translate(service, message, "reminder.target.role")
+		}
+		// Roles that can't be mentioned by everyone need the same permission as @everyone.
+		if !role.Mentionable {
+			if p, err := discord.UserChannelPermissions(message.UserID(), channelID); err != nil || p&discordgo.PermissionMentionEveryone != discordgo.PermissionMentionEveryone {
+				return bot.Translate(service, message, "reminder.target.role")
+			}
+		}
+	}
+
+	return ""
+}
+
+func isMention(p string) bool {
+	return strings.ToLower(p) == "me" || userMentionRegexp.MatchString(p) || roleMentionRegexp.MatchString(p) || channelMentionRegexp.MatchString(p)
+}
+
+func dedupe(ids []string) []string {
+	seen := map[string]bool{}
+	out := []string{}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// recipients returns the users a reminder is for.
+func (r *Reminder) recipients() []string {
+	if len(r.Users) > 0 {
+		return r.Users
+	}
+	return []string{r.UserID}
+}
+
+// mentions returns the mentions of the users and role a reminder is for, besides the requester.
+func (r *Reminder) mentions() string {
+	mentions := []string{}
+	for _, userID := range r.Users {
+		if userID != r.UserID {
+			mentions = append(mentions, "<@"+userID+">")
+		}
+	}
+	if r.RoleID != "" {
+		mentions = append(mentions, "<@&"+r.RoleID+">")
+	}
+	return strings.Join(mentions, " ")
+}
@@ -0,0 +1,491 @@
+package reminderplugin
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidTime is returned when a reminder time can't be understood.
+var ErrInvalidTime = errors.New("invalid time")
+
+type timeUnit int
+
+const (
+	unitSecond timeUnit = iota
+	unitMinute
+	unitHour
+	unitDay
+	unitWeek
+	unitMonth
+	unitYear
+)
+
+// parseUnit parses a unit of time, either written out or abbreviated like in 2h30m.
+func parseUnit(s string) (timeUnit, bool) {
+	switch s {
+	case "s", "sec", "secs", "second", "seconds":
+		return unitSecond, true
+	case "m", "min", "mins", "minute", "minutes":
+		return unitMinute, true
+	case "h", "hr", "hrs", "hour", "hours":
+		return unitHour, true
+	case "d", "day", "days":
+		return unitDay, true
+	case "w", "wk", "wks", "week", "weeks":
+		return unitWeek, true
+	case "mo", "month", "months":
+		return unitMonth, true
+	case "y", "yr", "yrs", "year", "years":
+		return unitYear, true
+	}
+	return 0, false
+}
+
+// parseWeekday parses a weekday, or an abbreviation of at least three letters.
+func parseWeekday(s string) (time.Weekday, bool) {
+	if len(s) < 3 {
+		return 0, false
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.HasPrefix(strings.ToLower(d.String()), s) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// parseMonth parses a month, or an abbreviation of at least three letters.
+func parseMonth(s string) (time.Month, bool) {
+	s = strings.TrimSuffix(s, ".")
+	if len(s) < 3 {
+		return 0, false
+	}
+	for m := time.January; m <= time.December; m++ {
+		if strings.HasPrefix(strings.ToLower(m.String()), s) {
+			return m, true
+		}
+	}
+	return 0, false
+}
+
+// parseDay parses a day of the month like 1 or 1st.
+func parseDay(s string) (int, bool) {
+	s = strings.TrimSuffix(s, ",")
+	for _, suffix := range []string{"st", "nd", "rd", "th"} {
+		s = strings.TrimSuffix(s, suffix)
+	}
+	d, err := strconv.Atoi(s)
+	return d, err == nil && d >= 1 && d <= 31
+}
+
+// parseYear parses a four digit year.
+func parseYear(s string) (int, bool) {
+	if len(s) != 4 {
+		return 0, false
+	}
+	y, err := strconv.Atoi(s)
+	return y, err == nil
+}
+
+var clockRegexp = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+
+// parseClock parses a time of day like 17:30, 9am or 9:15 pm.
+// A bare hour is only accepted when bare is set, so that "at 5" works but "5 apples" doesn't.
+// It returns the number of parts that were used.
+func parseClock(parts []string, bare bool) (int, int, int, bool) {
+	m := clockRegexp.FindStringSubmatch(parts[0])
+	if m == nil {
+		return 0, 0, 0, false
+	}
+	n := 1
+	meridiem := m[3]
+	if meridiem == "" && len(parts) > 1 && (parts[1] == "am" || parts[1] == "pm") {
+		meridiem = parts[1]
+		n = 2
+	}
+	if meridiem == "" && m[2] == "" && !bare {
+		return 0, 0, 0, false
+	}
+
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	if minute > 59 {
+		return 0, 0, 0, false
+	}
+
+	switch meridiem {
+	case "":
+		if hour > 23 {
+			return 0, 0, 0, false
+		}
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, 0, false
+		}
+		hour %= 12
+		if meridiem == "pm" {
+			hour += 12
+		}
+	}
+	return hour, minute, n, true
+}
+
+var durationRegexp = regexp.MustCompile(`(\d+)([a-z]+)`)
+
+// timeSpec collects the parts of a reminder time as they are parsed.
+type timeSpec struct {
+	// dated is set when anything fixes the day, so a time of day that has passed isn't moved to tomorrow.
+	dated bool
+
+	hasDate  bool
+	hasYear  bool
+	year     int
+	month    time.Month
+	day      int
+	hasClock bool
+	hour     int
+	minute   int
+	// A part of the day like "morning" is only used when no time of day is given.
+	hasPeriod bool
+	period    [2]int
+	weekday   time.Weekday
+	hasWeek   bool
+	nextWeek  bool
+	years     int
+	months    int
+	days      int
+	offset    time.Duration
+	hasOffset bool
+}
+
+func (s *timeSpec) add(n int, unit timeUnit) {
+	switch unit {
+	case unitSecond:
+		s.offset += time.Duration(n) * time.Second
+		s.hasOffset = true
+	case unitMinute:
+		s.offset += time.Duration(n) * time.Minute
+		s.hasOffset = true
+	case unitHour:
+		s.offset += time.Duration(n) * time.Hour
+		s.hasOffset = true
+	case unitDay:
+		s.days += n
+		s.dated = true
+	case unitWeek:
+		s.days += 7 * n
+		s.dated = true
+	case unitMonth:
+		s.months += n
+		s.dated = true
+	case unitYear:
+		s.years += n
+		s.dated = true
+	}
+}
+
+func (s *timeSpec) setClock(hour, minute int) bool {
+	if s.hasClock {
+		return false
+	}
+	s.hasClock = true
+	s.hour = hour
+	s.minute = minute
+	return true
+}
+
+func (s *timeSpec) setDate(year int, month time.Month, day int, hasYear bool) bool {
+	if s.hasDate || s.hasWeek {
+		return false
+	}
+	s.hasDate = true
+	s.hasYear = hasYear
+	s.year = year
+	s.month = month
+	s.day = day
+	s.dated = true
+	return true
+}
+
+func (s *timeSpec) setWeekday(d time.Weekday, next bool) bool {
+	if s.hasDate || s.hasWeek {
+		return false
+	}
+	s.hasWeek = true
+	s.weekday = d
+	s.nextWeek = next
+	s.dated = true
+	return true
+}
+
+// partsOfDay are the times used for words like "morning".
+var partsOfDay = map[string][2]int{
+	"morning":   {9, 0},
+	"afternoon": {15, 0},
+	"evening":   {18, 0},
+	"tonight":   {21, 0},
+	"night":     {21, 0},
+}
+
+// clause parses one clause of a reminder time and returns the number of parts it used, or 0 if it isn't part of the time.
+func (s *timeSpec) clause(parts []string) int {
+	p := parts[0]
+	next := ""
+	if len(parts) > 1 {
+		next = parts[1]
+	}
+
+	switch p {
+	case "at":
+		if next == "" {
+			return 0
+		}
+		if hour, minute, n, ok := parseClock(parts[1:], true); ok {
+			if !s.setClock(hour, minute) {
+				return 0
+			}
+			return n + 1
+		}
+		fallthrough
+	case "on", "in", "this", "the":
+		if next == "" {
+			return 0
+		}
+		if n := s.clause(parts[1:]); n > 0 {
+			return n + 1
+		}
+		return 0
+	case "today":
+		s.dated = true
+		return 1
+	case "tomorrow":
+		s.add(1, unitDay)
+		return 1
+	case "next":
+		if unit, ok := parseUnit(next); ok && unit > unitHour {
+			s.add(1, unit)
+			return 2
+		}
+		if d, ok := parseWeekday(next); ok && s.setWeekday(d, true) {
+			return 2
+		}
+		return 0
+	case "a", "an":
+		if unit, ok := parseUnit(next); ok {
+			s.add(1, unit)
+			return 2
+		}
+		return 0
+	case "noon", "midday":
+		if !s.setClock(12, 0) {
+			return 0
+		}
+		return 1
+	case "midnight":
+		if !s.setClock(0, 0) {
+			return 0
+		}
+		return 1
+	}
+
+	if t, ok := partsOfDay[p]; ok {
+		if s.hasPeriod {
+			return 0
+		}
+		s.hasPeriod = true
+		s.period = t
+		if p == "tonight" {
+			s.dated = true
+		}
+		return 1
+	}
+
+	if d, ok := parseWeekday(p); ok {
+		if !s.setWeekday(d, false) {
+			return 0
+		}
+		return 1
+	}
+
+	if hour, minute, n, ok := parseClock(parts, false); ok {
+		if !s.setClock(hour, minute) {
+			return 0
+		}
+		return n
+	}
+
+	if i, err := strconv.Atoi(p); err == nil {
+		if unit, ok := parseUnit(next); ok {
+			s.add(i, unit)
+			return 2
+		}
+	}
+
+	if matches := durationRegexp.FindAllStringSubmatch(p, -1); matches != nil {
+		units := []timeUnit{}
+		values := []int{}
+		length := 0
+		for _, m := range matches {
+			unit, ok := parseUnit(m[2])
+			if !ok {
+				break
+			}
+			i, _ := strconv.Atoi(m[1])
+			units = append(units, unit)
+			values = append(values, i)
+			length += len(m[0])
+		}
+		if length == len(p) {
+			for i := range units {
+				s.add(values[i], units[i])
+			}
+			return 1
+		}
+	}
+
+	for _, layout := range []string{"2006-01-02", "2006/01/02"} {
+		if t, err := time.Parse(layout, p); err == nil {
+			if !s.setDate(t.Year(), t.Month(), t.Day(), true) {
+				return 0
+			}
+			return 1
+		}
+	}
+
+	// Dates like "december 1st 2026" or "1 dec".
+	if month, ok := parseMonth(p); ok {
+		if day, ok := parseDay(next); ok {
+			n := 2
+			year, hasYear := 0, false
+			if len(parts) > 2 {
+				year, hasYear = parseYear(parts[2])
+			}
+			if hasYear {
+				n = 3
+			}
+			if !s.setDate(year, month, day, hasYear) {
+				return 0
+			}
+			return n
+		}
+	}
+	if day, ok := parseDay(p); ok {
+		if month, ok := parseMonth(next); ok {
+			n := 2
+			year, hasYear := 0, false
+			if len(parts) > 2 {
+				year, hasYear = parseYear(parts[2])
+			}
+			if hasYear {
+				n = 3
+			}
+			if !s.setDate(year, month, day, hasYear) {
+				return 0
+			}
+			return n
+		}
+	}
+
+	return 0
+}
+
+// resolve returns the time the spec refers to, relative to now and in its location.
+func (s *timeSpec) resolve(now time.Time) (time.Time, error) {
+	if !s.hasClock && s.hasPeriod {
+		s.setClock(s.period[0], s.period[1])
+	}
+	if s.hasClock && s.hasOffset {
+		return time.Time{}, ErrInvalidTime
+	}
+
+	if !s.hasClock && !s.dated {
+		return now.Add(s.offset), nil
+	}
+
+	if s.hasDate {
+		// Dates without a year are checked against a leap year, so February 29th is allowed.
+		year := 2000
+		if s.hasYear {
+			year = s.year
+		}
+		if time.Date(year, s.month, s.day, 0, 0, 0, 0, time.UTC).Day() != s.day {
+			return time.Time{}, ErrInvalidTime
+		}
+	}
+
+	year, month, day := now.Date()
+	hour, minute, second := now.Clock()
+	if s.hasClock {
+		hour, minute, second = s.hour, s.minute, 0
+	}
+
+	weekdayDelta := 0
+	if s.hasDate {
+		month, day = s.month, s.day
+		if s.hasYear {
+			year = s.year
+		}
+	} else if s.hasWeek {
+		weekdayDelta = (int(s.weekday) - int(now.Weekday()) + 7) % 7
+		if s.nextWeek && weekdayDelta == 0 {
+			weekdayDelta = 7
+		}
+		day += weekdayDelta
+	}
+
+	// The date is built from its parts rather than by adding durations, so that it is correct across daylight saving changes.
+	date := func() time.Time {
+		return time.Date(year+s.years, month+time.Month(s.months), day+s.days, hour, minute, second, 0, now.Location())
+	}
+	t := date()
+
+	if t.After(now) {
+		return t.Add(s.offset), nil
+	}
+
+	switch {
+	case !s.dated:
+		day++
+	case s.hasDate && !s.hasYear:
+		year++
+	case s.hasWeek && weekdayDelta == 0:
+		day += 7
+	default:
+		return t.Add(s.offset), nil
+	}
+	return date().Add(s.offset), nil
+}
+
+// parseTime parses the time at the start of a reminder, relative to now and in its location.
+// It returns the time and the number of parts that were used.
+func parseTime(parts []string, now time.Time) (time.Time, int, error) {
+	lower := make([]string, len(parts))
+	for i, p := range parts {
+		lower[i] = strings.ToLower(p)
+	}
+
+	s := &timeSpec{}
+	i := 0
+	for i < len(lower) {
+		n := s.clause(lower[i:])
+		if n == 0 {
+			break
+		}
+		i += n
+	}
+	if i == 0 {
+		return time.Time{}, 0, ErrInvalidTime
+	}
+
+	t, err := s.resolve(now)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	return t, i, nil
+}
@@ -0,0 +1,82 @@
+package reminderplugin
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	// A Wednesday.
+	now := time.Date(2024, time.January, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		s    string
+		want time.Time
+		// n is the number of parts that are part of the time.
+		n int
+	}{
+		{"in 10 minutes walk the dog", now.Add(10 * time.Minute), 3},
+		{"in 30 secs", now.Add(30 * time.Second), 3},
+		{"in 2h30m check the oven", now.Add(2*time.Hour + 30*time.Minute), 2},
+		{"10 min tea", now.Add(10 * time.Minute), 2},
+		{"in a minute", now.Add(time.Minute), 3},
+		{"at 17:30", time.Date(2024, time.January, 10, 17, 30, 0, 0, time.UTC), 2},
+		{"at 9am", time.Date(2024, time.January, 11, 9, 0, 0, 0, time.UTC), 2},
+		{"tomorrow at noon", time.Date(2024, time.January, 11, 12, 0, 0, 0, time.UTC), 3},
+		{"friday at noon", time.Date(2024, time.January, 12, 12, 0, 0, 0, time.UTC), 3},
+		{"on december 1st at 9am", time.Date(2024, time.December, 1, 9, 0, 0, 0, time.UTC), 5},
+		{"in 2 weeks", now.AddDate(0, 0, 14), 3},
+	}
+
+	for _, test := range tests {
+		got, n, err := parseTime(strings.Fields(test.s), now)
+		if err != nil {
+			t.Errorf("parseTime(%q) returned %v", test.s, err)
+			continue
+		}
+		if !got.Equal(test.want) || n != test.n {
+			t.Errorf("parseTime(%q) = %v, %d, want %v, %d", test.s, got, n, test.want, test.n)
+		}
+	}
+}
+
+func TestParseTimeInvalid(t *testing.T) {
+	now := time.Date(2024, time.January, 10, 12, 0, 0, 0, time.UTC)
+
+	for _, s := range []string{"walk the dog", "in 5 minions", "in 5 secretaries", "", "at"} {
+		if _, _, err := parseTime(strings.Fields(s), now); err == nil {
+			t.Errorf("parseTime(%q) didn't return an error", s)
+		}
+	}
+}
+
+func TestParseUnit(t *testing.T) {
+	tests := []struct {
+		s    string
+		unit timeUnit
+		ok   bool
+	}{
+		{"s", unitSecond, true},
+		{"seconds", unitSecond, true},
+		{"m", unitMinute, true},
+		{"mins", unitMinute, true},
+		{"minute", unitMinute, true},
+		{"hrs", unitHour, true},
+		{"days", unitDay, true},
+		{"wk", unitWeek, true},
+		{"mo", unitMonth, true},
+		{"years", unitYear, true},
+		{"minions", 0, false},
+		{"secretary", 0, false},
+		{"hourglass", 0, false},
+		{"monthly", 0, false},
+	}
+
+	for _, test := range tests {
+		unit, ok := parseUnit(test.s)
+		if unit != test.unit || ok != test.ok {
+			t.Errorf("parseUnit(%q) = %v, %v, want %v, %v", test.s, unit, ok, test.unit, test.ok)
+		}
+	}
+}
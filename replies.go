@@ -0,0 +1,256 @@
+package rikka
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// DefaultReplyWindow is how long the replies to a message are remembered for edits and deletes.
+const DefaultReplyWindow = 10 * time.Minute
+
+type reply struct {
+	id    string
+	embed bool
+}
+
+type replyEntry struct {
+	content string
+	replies []reply
+	expires time.Time
+}
+
+// replyTracker remembers the replies sent in response to messages, keyed by the ID of the message that triggered them.
+type replyTracker struct {
+	sync.Mutex
+	entries map[string]*replyEntry
+}
+
+func newReplyTracker() *replyTracker {
+	return &replyTracker{
+		entries: make(map[string]*replyEntry),
+	}
+}
+
+// get returns the entry for a message, or nil if the message has no replies or they have expired.
+func (t *replyTracker) get(messageID string) *replyEntry {
+	t.Lock()
+	defer t.Unlock()
+
+	e := t.entries[messageID]
+	if e == nil {
+		return nil
+	}
+	if time.Now().After(e.expires) {
+		delete(t.entries, messageID)
+		return nil
+	}
+	return e
+}
+
+func (t *replyTracker) set(messageID string, entry *replyEntry) {
+	t.Lock()
+	defer t.Unlock()
+
+	now := time.Now()
+	for id, e := range t.entries {
+		if now.After(e.expires) {
+			delete(t.entries, id)
+		}
+	}
+
+	if len(entry.replies) == 0 {
+		delete(t.entries, messageID)
+		return
+	}
+	t.entries[messageID] = entry
+}
+
+func (t *replyTracker) remove(messageID string) *replyEntry {
+	t.Lock()
+	defer t.Unlock()
+
+	e := t.entries[messageID]
+	delete(t.entries, messageID)
+	return e
+}
+
+// deleteReplies deletes the replies to a message that has been deleted.
+func (t *replyTracker) deleteReplies(service Service, message Message) {
+	e := t.remove(message.MessageID())
+	if e == nil {
+		return
+	}
+	for _, r := range e.replies {
+		if err := service.DeleteMessage(message.Channel(), r.id); err != nil {
+			log.Println("Error deleting reply", err)
+		}
+	}
+}
+
+// replyService wraps a service while a single message is handled.
+// It records the replies sent to the channel of the message, and when the message is an edit of a message
+// that was already replied to, it edits the previous replies in order instead of sending new ones.
+type replyService struct {
+	Service
+
+	sync.Mutex
+	tracker  *replyTracker
+	message  Message
+	window   time.Duration
+	previous []reply
+	replies  []reply
+	done     bool
+}
+
+func newReplyService(service Service, tracker *replyTracker, message Message, window time.Duration) *replyService {
+	s := &replyService{
+		Service: service,
+		tracker: tracker,
+		message: message,
+		window:  window,
+	}
+	if message.Type() == MessageTypeUpdate {
+		if e := tracker.get(message.MessageID()); e != nil {
+			s.previous = e.replies
+		}
+	}
+	return s
+}
+
+// next returns the previous reply that the next reply should replace.
+func (s *replyService) next(channel string) (reply, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.done || channel != s.message.Channel() || len(s.previous) == 0 {
+		return reply{}, false
+	}
+	r := s.previous[0]
+	s.previous = s.previous[1:]
+	return r, true
+}
+
+func (s *replyService) record(channel string, m *discordgo.Message, embed bool) {
+	if m == nil || channel != s.message.Channel() {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.done {
+		return
+	}
+	// Replies sent in a burst by different plugins may have been coalesced into a single message.
+	for _, r := range s.replies {
+		if r.id == m.ID {
+			return
+		}
+	}
+	s.replies = append(s.replies, reply{id: m.ID, embed: embed})
+}
+
+// replySender is implemented by services that can tell the send queue which message a reply is for.
+type replySender interface {
+	sendReply(channel, origin, message string) (*discordgo.Message, error)
+}
+
+// SendMessage sends a message, or edits the previous replies if the message being handled was edited.
+// Long messages are split here rather than by the service, so that every part is recorded and can be edited or deleted.
+func (s *replyService) SendMessage(channel, message string) (m *discordgo.Message, err error) {
+	for _, part := range splitMessage(message, MaxMessageLength) {
+		if m, err = s.sendPart(channel, part); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (s *replyService) sendPart(channel, message string) (*discordgo.Message, error) {
+	if r, ok := s.next(channel); ok {
+		if !r.embed {
+			if m, err := s.Service.EditMessage(channel, r.id, message); err == nil {
+				s.record(channel, m, false)
+				return m, nil
+			}
+		} else {
+			s.Service.DeleteMessage(channel, r.id)
+		}
+	}
+
+	var m *discordgo.Message
+	var err error
+	if rs, ok := s.Service.(replySender); ok {
+		m, err = rs.sendReply(channel, s.message.MessageID(), message)
+	} else {
+		m, err = s.Service.SendMessage(channel, message)
+	}
+	s.record(channel, m, false)
+	return m, err
+}
+
+// SendMessageEmbed sends an embed, or edits the previous reply if the message being handled was edited.
+func (s *replyService) SendMessageEmbed(channel string, embed *discordgo.MessageEmbed) (*discordgo.Message, error) {
+	if r, ok := s.next(channel); ok {
+		if r.embed {
+			if m, err := s.Service.EditMessageEmbed(channel, r.id, embed); err == nil {
+				s.record(channel, m, true)
+				return m, nil
+			}
+		} else {
+			s.Service.DeleteMessage(channel, r.id)
+		}
+	}
+
+	m, err := s.Service.SendMessageEmbed(channel, embed)
+	s.record(channel, m, true)
+	return m, err
+}
+
+// SendAction sends an action, or edits the previous reply if the message being handled was edited.
+func (s *replyService) SendAction(channel, message string) (*discordgo.Message, error) {
+	if r, ok := s.next(channel); ok {
+		s.Service.DeleteMessage(channel, r.id)
+	}
+
+	m, err := s.Service.SendAction(channel, message)
+	s.record(channel, m, m != nil && len(m.Embeds) > 0)
+	return m, err
+}
+
+// DeleteMessage deletes a message, and forgets it if it was a reply.
+func (s *replyService) DeleteMessage(channel, messageID string) error {
+	s.Lock()
+	for i, r := range s.replies {
+		if r.id == messageID {
+			s.replies = append(s.replies[:i], s.replies[i+1:]...)
+			break
+		}
+	}
+	s.Unlock()
+
+	return s.Service.DeleteMessage(channel, messageID)
+}
+
+// finish is called once every plugin has handled the message.
+// Previous replies that weren't replaced are deleted, and the new replies are remembered.
+func (s *replyService) finish() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.done = true
+
+	for _, r := range s.previous {
+		s.Service.DeleteMessage(s.message.Channel(), r.id)
+	}
+	s.previous = nil
+
+	s.tracker.set(s.message.MessageID(), &replyEntry{
+		content: s.message.RawMessage(),
+		replies: s.replies,
+		expires: time.Now().Add(s.window),
+	})
+}
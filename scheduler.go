@@ -0,0 +1,462 @@
+package rikka
+
+import (
+	"container/heap"
+	"encoding/json"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrInvalidJob is returned when a job can't be scheduled.
+var ErrInvalidJob = errors.New("invalid job")
+
+// JobKind is how often a job runs.
+type JobKind string
+
+const (
+	// JobOnce runs once at its time.
+	JobOnce JobKind = "once"
+	// JobInterval runs every interval.
+	JobInterval JobKind = "interval"
+	// JobCron runs whenever its cron expression matches, in its location.
+	JobCron JobKind = "cron"
+)
+
+// CatchUp is what happens to runs of a job that were missed, eg. while the bot wasn't running.
+type CatchUp string
+
+const (
+	// CatchUpOnce runs a late job once, no matter how many runs were missed.
+	CatchUpOnce CatchUp = "once"
+	// CatchUpAll runs a late job once for every run that was missed, up to maxCatchUpRuns.
+	CatchUpAll CatchUp = "all"
+	// CatchUpSkip drops missed runs, late one-shot jobs never run.
+	CatchUpSkip CatchUp = "skip"
+)
+
+// A job is late when it runs this long after its time.
+const catchUpGrace = time.Minute
+
+// The most missed runs of a job that are run with CatchUpAll.
+const maxCatchUpRuns = 100
+
+// JobFunc is the handler of a job, it is called each time the job runs.
+// Jobs can be scheduled again from their handler, eg. to run a one-shot job again at a different time.
+type JobFunc func(job *Job)
+
+// A Job is a unit of work that runs at a time.
+// Jobs are saved with the scheduler, so they run after a restart.
+type Job struct {
+	// ID identifies the job, scheduling a job with the ID of an existing job replaces it.
+	ID string
+	// Handler is the name of the handler that runs the job.
+	Handler string
+	Kind    JobKind
+	// Time is when the job runs next.
+	Time     time.Time
+	Interval time.Duration
+	Cron     string
+	// Location is the time zone cron expressions are matched in, UTC if empty.
+	Location string
+	CatchUp  CatchUp
+	// Payload is data for the handler, it is saved with the job.
+	Payload json.RawMessage
+
+	index int
+}
+
+// SetPayload sets the payload of a job to v encoded as JSON.
+func (j *Job) SetPayload(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	j.Payload = b
+	return nil
+}
+
+// Decode decodes the payload of a job into v.
+func (j *Job) Decode(v interface{}) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+func (j *Job) location() *time.Location {
+	if j.Location == "" {
+		return time.UTC
+	}
+	if l, err := LoadLocation(j.Location); err == nil {
+		return l
+	}
+	return time.UTC
+}
+
+// next returns the run of a recurring job after a time, or the zero time for one-shot jobs.
+func (j *Job) next(after time.Time) time.Time {
+	switch j.Kind {
+	case JobInterval:
+		return after.Add(j.Interval)
+	case JobCron:
+		c, err := ParseCron(j.Cron)
+		if err != nil {
+			return time.Time{}
+		}
+		return c.Next(after.In(j.location()))
+	}
+	return time.Time{}
+}
+
+type jobHeap []*Job
+
+func (h jobHeap) Len() int           { return len(h) }
+func (h jobHeap) Less(i, j int) bool { return h[i].Time.Before(h[j].Time) }
+func (h jobHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *jobHeap) Push(x interface{}) {
+	j := x.(*Job)
+	j.index = len(*h)
+	*h = append(*h, j)
+}
+
+func (h *jobHeap) Pop() interface{} {
+	old := *h
+	j := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	j.index = -1
+	return j
+}
+
+type byJobTime []*Job
+
+func (j byJobTime) Len() int           { return len(j) }
+func (j byJobTime) Swap(a, b int)      { j[a], j[b] = j[b], j[a] }
+func (j byJobTime) Less(a, b int) bool { return j[a].Time.Before(j[b].Time) }
+
+// Scheduler runs jobs at their time.
+// It keeps the jobs in a heap ordered by time, and sleeps until the first one is due.
+type Scheduler struct {
+	sync.Mutex
+
+	handlers map[string]JobFunc
+	jobs     jobHeap
+	ids      map[string]*Job
+	// orphans are jobs that were due before their handler was registered.
+	orphans map[string]*Job
+	wake    chan struct{}
+	running bool
+}
+
+// NewScheduler creates a new scheduler, it runs jobs once Run is called.
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		handlers: map[string]JobFunc{},
+		ids:      map[string]*Job{},
+		orphans:  map[string]*Job{},
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Handle registers the handler for jobs with a handler name.
+func (s *Scheduler) Handle(name string, f JobFunc) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.handlers[name] = f
+	for id, j := range s.orphans {
+		if j.Handler == name {
+			delete(s.orphans, id)
+			heap.Push(&s.jobs, j)
+		}
+	}
+	s.notify()
+}
+
+// remove removes a job, the lock must be held.
+func (s *Scheduler) remove(id string) *Job {
+	j := s.ids[id]
+	if j == nil {
+		return nil
+	}
+	delete(s.ids, id)
+	if _, ok := s.orphans[id]; ok {
+		delete(s.orphans, id)
+	} else if j.index >= 0 {
+		heap.Remove(&s.jobs, j.index)
+	}
+	return j
+}
+
+// Schedule adds a job, replacing any job with the same ID.
+// Recurring jobs without a time first run after their interval, or when their cron expression next matches.
+func (s *Scheduler) Schedule(job *Job) error {
+	if job.ID == "" || job.Handler == "" {
+		return ErrInvalidJob
+	}
+	if job.Kind == "" {
+		job.Kind = JobOnce
+	}
+	if job.CatchUp == "" {
+		job.CatchUp = CatchUpOnce
+	}
+
+	switch job.Kind {
+	case JobOnce:
+		if job.Time.IsZero() {
+			return ErrInvalidJob
+		}
+	case JobInterval:
+		if job.Interval <= 0 {
+			return ErrInvalidJob
+		}
+	case JobCron:
+		if _, err := ParseCron(job.Cron); err != nil {
+			return err
+		}
+	default:
+		return ErrInvalidJob
+	}
+	if job.Time.IsZero() {
+		job.Time = job.next(time.Now())
+		if job.Time.IsZero() {
+			return ErrInvalidJob
+		}
+	}
+
+	j := *job
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.remove(j.ID)
+	s.ids[j.ID] = &j
+	heap.Push(&s.jobs, &j)
+	s.notify()
+	return nil
+}
+
+// Cancel removes a job, it returns whether the job existed.
+func (s *Scheduler) Cancel(id string) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.remove(id) == nil {
+		return false
+	}
+	s.notify()
+	return true
+}
+
+// Job returns a copy of a job, or nil if it doesn't exist.
+func (s *Scheduler) Job(id string) *Job {
+	s.Lock()
+	defer s.Unlock()
+
+	j := s.ids[id]
+	if j == nil {
+		return nil
+	}
+	c := *j
+	return &c
+}
+
+// Jobs returns copies of the jobs of a handler, ordered by time.
+func (s *Scheduler) Jobs(handler string) []*Job {
+	s.Lock()
+	defer s.Unlock()
+
+	jobs := []*Job{}
+	for _, j := range s.ids {
+		if j.Handler == handler {
+			c := *j
+			jobs = append(jobs, &c)
+		}
+	}
+	sort.Sort(byJobTime(jobs))
+	return jobs
+}
+
+// due removes the first job if it is due, and reschedules it if it recurs.
+// It returns the job and the number of times it should run, or the time until the first job is due.
+func (s *Scheduler) due(now time.Time) (*Job, JobFunc, int, time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+
+	if len(s.jobs) == 0 {
+		return nil, nil, 0, -1
+	}
+	j := s.jobs[0]
+	if d := j.Time.Sub(now); d > 0 {
+		return nil, nil, 0, d
+	}
+	heap.Pop(&s.jobs)
+
+	f := s.handlers[j.Handler]
+	if f == nil {
+		// The plugin of the job may not have loaded yet, it runs when its handler is registered.
+		s.orphans[j.ID] = j
+		return nil, nil, 0, 0
+	}
+
+	runs := 1
+	late := now.Sub(j.Time) > catchUpGrace
+	run := *j
+
+	if j.Kind == JobOnce {
+		delete(s.ids, j.ID)
+		if late && j.CatchUp == CatchUpSkip {
+			runs = 0
+		}
+		return &run, f, runs, 0
+	}
+
+	next := j.next(j.Time)
+	if late {
+		missed := 0
+		for !next.IsZero() && !next.After(now) {
+			missed++
+			next = j.next(next)
+			if missed >= maxCatchUpRuns {
+				next = j.next(now)
+				break
+			}
+		}
+		switch j.CatchUp {
+		case CatchUpAll:
+			runs += missed
+		case CatchUpSkip:
+			runs = 0
+		}
+	}
+
+	if next.IsZero() {
+		delete(s.ids, j.ID)
+	} else {
+		j.Time = next
+		heap.Push(&s.jobs, j)
+	}
+	return &run, f, runs, 0
+}
+
+// Run runs jobs as they become due, it blocks forever.
+func (s *Scheduler) Run() {
+	s.Lock()
+	if s.running {
+		s.Unlock()
+		return
+	}
+	s.running = true
+	s.Unlock()
+
+	timer := time.NewTimer(time.Hour)
+	for {
+		j, f, runs, wait := s.due(time.Now())
+		if j != nil {
+			go func() {
+				defer MessageRecover()
+				for i := 0; i < runs; i++ {
+					f(j)
+				}
+			}()
+			continue
+		}
+		if wait == 0 {
+			continue
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		if wait < 0 {
+			// There are no jobs, the scheduler sleeps until one is added.
+			wait = time.Hour
+		}
+		timer.Reset(wait)
+
+		select {
+		case <-timer.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// Name returns the name of the plugin.
+func (s *Scheduler) Name() string {
+	return "Scheduler"
+}
+
+// Load will load the saved jobs, jobs that were already scheduled are kept.
+func (s *Scheduler) Load(bot *Bot, service Service, data []byte) error {
+	if data == nil {
+		return nil
+	}
+
+	jobs := []*Job{}
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		log.Println("Error loading data", err)
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	for _, j := range jobs {
+		if _, ok := s.ids[j.ID]; ok {
+			continue
+		}
+		s.ids[j.ID] = j
+		heap.Push(&s.jobs, j)
+	}
+	s.notify()
+	return nil
+}
+
+// Save will save the jobs to a byte array.
+func (s *Scheduler) Save() ([]byte, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	jobs := make([]*Job, 0, len(s.ids))
+	for _, j := range s.ids {
+		jobs = append(jobs, j)
+	}
+	sort.Sort(byJobTime(jobs))
+	return json.Marshal(jobs)
+}
+
+// Help returns a list of help strings that are printed when the user requests them.
+func (s *Scheduler) Help(bot *Bot, service Service, message Message, detailed bool) []string {
+	return nil
+}
+
+// Message handler.
+func (s *Scheduler) Message(bot *Bot, service Service, message Message) {}
+
+// Stats will return the stats for a plugin.
+func (s *Scheduler) Stats(bot *Bot, service Service, message Message) []string {
+	return nil
+}
+
+// Scheduler returns the scheduler of a service.
+func (b *Bot) Scheduler(service Service) *Scheduler {
+	if s := b.Services[service.Name()]; s != nil {
+		return s.scheduler
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package seenplugin
+
+import "github.com/ThyLeader/rikka"
+
+func init() {
+	rikka.RegisterMessages("en", map[string]string{
+		"seen.help":  "See the last time a user has typed in this guild",
+		"seen.never": "%s (`%s`) has not sent a message yet or there was an error",
+		"seen.seen":  "%s was last seen here %s (%s)",
+	})
+
+	rikka.RegisterMessages("es", map[string]string{
+		"seen.help":  "Muestra la última vez que un usuario escribió en este servidor",
+		"seen.never": "%s (`%s`) aún no ha enviado mensajes o hubo un error",
+		"seen.seen":  "%s fue visto aquí por última vez %s (%s)",
+	})
+}
@@ -46,7 +46,7 @@ func (p *seenPlugin) Message(bot *rikka.Bot, service rikka.Service, message rikk
 
 	mentions := message.Mentions()
 	if len(mentions) > 1 {
-		service.SendMessage(message.Channel(), "Please only mention one user")
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.mentions.one"))
 		return
 	}
 	var id, name string
@@ -64,7 +64,7 @@ func (p *seenPlugin) Message(bot *rikka.Bot, service rikka.Service, message rikk
 			id = parts[0]
 			m, err := service.Member(message.GuildID(), id)
 			if err != nil {
-				service.SendMessage(message.Channel(), "There was an error!\n"+err.Error())
+				service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", "\n"+err.Error()))
 				log.Println(err.Error())
 				return
 			}
@@ -74,7 +74,7 @@ func (p *seenPlugin) Message(bot *rikka.Bot, service rikka.Service, message rikk
 			id = message.UserID()
 			name = message.UserName()
 		default:
-			service.SendMessage(message.Channel(), "Please only search for one user at a time")
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "error.search.one"))
 			return
 		}
 	}
@@ -84,13 +84,13 @@ func (p *seenPlugin) Message(bot *rikka.Bot, service rikka.Service, message rikk
 		return
 	}
 
-	lastSeen := p.getLastSeen(message.GuildID(), id)
-	if lastSeen == "" {
-		service.SendMessage(message.Channel(), fmt.Sprintf("%s (`%s`) has not sent a message yet or there was an error", name, id))
+	lastSeen, ok := p.getLastSeen(message.GuildID(), id)
+	if !ok {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "seen.never", name, id))
 		return
 	}
 
-	service.SendMessage(message.Channel(), fmt.Sprintf("%s was last seen here %s", name, lastSeen))
+	service.SendMessage(message.Channel(), bot.Translate(service, message, "seen.seen", name, humanize.Time(lastSeen), bot.FormatTime(service, message, lastSeen)))
 }
 
 func seenKey(gID string, uID string) string {
@@ -98,30 +98,34 @@ func seenKey(gID string, uID string) string {
 }
 
 func (p *seenPlugin) Update(bot *rikka.Bot, service rikka.Service, message rikka.Message) {
-	err := client.Set(seenKey(message.GuildID(), message.UserID()), time.Now().Format(time.UnixDate), 0).Err()
+	err := client.Set(seenKey(message.GuildID(), message.UserID()), time.Now().Format(time.RFC3339), 0).Err()
 	if err != nil {
 		fmt.Println("Error updating seen - ", err.Error())
 	}
 }
 
-func (p *seenPlugin) getLastSeen(gID, uID string) string {
+func (p *seenPlugin) getLastSeen(gID, uID string) (time.Time, bool) {
 	s := client.Get(seenKey(gID, uID)).Val()
 	if s == "" {
-		return s
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		// Older entries were stored in the server's local time.
+		t, err = time.ParseInLocation(time.UnixDate, s, time.Local)
 	}
-	t, err := time.Parse(time.UnixDate, s)
 	if err != nil {
 		fmt.Println("Error parsing last seen - ", err.Error())
-		return ""
+		return time.Time{}, false
 	}
-	return humanize.Time(t)
+	return t, true
 }
 
 func (p *seenPlugin) Help(bot *rikka.Bot, service rikka.Service, message rikka.Message, detailed bool) []string {
 	if detailed {
 		return nil
 	}
-	return rikka.CommandHelp(service, "seen", "[@username]", "See the last time a user has typed in this guild")
+	return rikka.CommandHelp(service, "seen", "[@username]", bot.Translate(service, message, "seen.help"))
 }
 
 func (p *seenPlugin) Name() string {
@@ -0,0 +1,294 @@
+package rikka
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// MaxMessageLength is the longest message Discord accepts, longer messages are split before sending.
+const MaxMessageLength = 2000
+
+// The number of times a send is attempted before giving up on transient errors.
+const sendAttempts = 3
+
+// ErrEmptyChannel is returned when a message is sent without a channel.
+var ErrEmptyChannel = errors.New("empty channel")
+
+type sendResult struct {
+	message *discordgo.Message
+	err     error
+}
+
+type sendRequest struct {
+	// origin is the ID of the message being replied to, or empty for messages that aren't replies.
+	origin  string
+	content string
+	embed   *discordgo.MessageEmbed
+	result  chan sendResult
+}
+
+// sendQueue delivers outbound messages in order, one channel at a time.
+// While a channel is waiting on its rate limit bucket, text messages queued for it with the same origin are coalesced into as few messages as possible,
+// so a message never holds the replies to more than one message.
+type sendQueue struct {
+	sync.Mutex
+
+	discord  *Discord
+	channels map[string][]*sendRequest
+}
+
+func newSendQueue(d *Discord) *sendQueue {
+	return &sendQueue{
+		discord:  d,
+		channels: make(map[string][]*sendRequest),
+	}
+}
+
+// send queues a message or an embed and waits for it to be delivered.
+// If the message had to be split, the last message sent is returned.
+func (q *sendQueue) send(channel, origin, content string, embed *discordgo.MessageEmbed) (*discordgo.Message, error) {
+	r := &sendRequest{
+		origin:  origin,
+		content: content,
+		embed:   embed,
+		result:  make(chan sendResult, 1),
+	}
+
+	q.Lock()
+	_, running := q.channels[channel]
+	q.channels[channel] = append(q.channels[channel], r)
+	q.Unlock()
+
+	if !running {
+		go q.run(channel)
+	}
+
+	res := <-r.result
+	return res.message, res.err
+}
+
+func (q *sendQueue) run(channel string) {
+	for {
+		q.Lock()
+		pending := q.channels[channel]
+		if len(pending) == 0 {
+			delete(q.channels, channel)
+			q.Unlock()
+			return
+		}
+		q.channels[channel] = []*sendRequest{}
+		q.Unlock()
+
+		for _, batch := range coalesce(pending) {
+			m, err := q.safeDeliver(channel, batch[0].content, batch[0].embed)
+			for _, r := range batch {
+				r.result <- sendResult{m, err}
+			}
+		}
+	}
+}
+
+// coalesce groups consecutive text messages with the same origin that fit in a single message.
+func coalesce(pending []*sendRequest) [][]*sendRequest {
+	batches := [][]*sendRequest{}
+	for _, r := range pending {
+		if n := len(batches); n > 0 && r.embed == nil {
+			last := batches[n-1]
+			if last[0].embed == nil && last[0].origin == r.origin && len(last[0].content)+1+len(r.content) <= MaxMessageLength {
+				last[0].content += "\n" + r.content
+				batches[n-1] = append(last, r)
+				continue
+			}
+		}
+		batches = append(batches, []*sendRequest{r})
+	}
+	return batches
+}
+
+// safeDeliver delivers a message, a panic while delivering it is returned as an error so the queue of the channel keeps running.
+func (q *sendQueue) safeDeliver(channel, content string, embed *discordgo.MessageEmbed) (m *discordgo.Message, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Println("Recovered:", string(debug.Stack()))
+			err = fmt.Errorf("sending message: %v", r)
+		}
+	}()
+	return q.deliver(channel, content, embed)
+}
+
+func (q *sendQueue) deliver(channel, content string, embed *discordgo.MessageEmbed) (*discordgo.Message, error) {
+	session := q.discord.SessionForChannel(channel)
+
+	if embed != nil {
+		return retrySend(func() (*discordgo.Message, error) {
+			return session.ChannelMessageSendEmbed(channel, embed)
+		})
+	}
+
+	var m *discordgo.Message
+	for _, part := range splitMessage(content, MaxMessageLength) {
+		part := part
+		var err error
+		if m, err = retrySend(func() (*discordgo.Message, error) {
+			return session.ChannelMessageSend(channel, part)
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// retrySend retries a send when Discord rate limits it or fails with a server error.
+func retrySend(send func() (*discordgo.Message, error)) (m *discordgo.Message, err error) {
+	for i := 1; i <= sendAttempts; i++ {
+		if m, err = send(); err == nil {
+			return m, nil
+		}
+
+		restErr, ok := err.(*discordgo.RESTError)
+		if !ok || restErr.Response == nil {
+			return nil, err
+		}
+
+		switch code := restErr.Response.StatusCode; {
+		case code == http.StatusTooManyRequests:
+			wait := time.Second
+			rl := discordgo.TooManyRequests{}
+			if json.Unmarshal(restErr.ResponseBody, &rl) == nil && rl.RetryAfter > 0 {
+				wait = rl.RetryAfter * time.Millisecond
+			}
+			time.Sleep(wait)
+		case code >= 500:
+			time.Sleep(time.Duration(i) * time.Second)
+		default:
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// splitMessage splits content into messages no longer than limit.
+// Messages are split between lines where possible, and code blocks that are split are closed and reopened in the next message.
+func splitMessage(content string, limit int) []string {
+	if len(content) <= limit {
+		return []string{content}
+	}
+
+	parts := []string{}
+	current := ""
+	// Whether a code block is open.
+	block := false
+	// The opening line of the open code block that each message starts with, eg. "```go".
+	// It's empty when no code block is open, or when the open code block is sent without fences.
+	fence := ""
+
+	flush := func() {
+		if fence != "" {
+			current += "\n```"
+		}
+		parts = append(parts, current)
+		current = fence
+	}
+
+	add := func(line string) {
+		if current != "" {
+			current += "\n"
+		}
+		current += line
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		toggles := strings.Count(line, "```")%2 == 1
+		// Whether the line leaves a code block open, so the message it ends in needs room to close it.
+		open := fence != "" && !toggles || !block && toggles
+		for {
+			room := limit - len(current)
+			if current != "" {
+				room--
+			}
+			if open {
+				room -= len("\n```")
+			}
+
+			if len(line) <= room {
+				break
+			}
+
+			if current != fence {
+				flush()
+				continue
+			}
+
+			// A code block whose opening line doesn't fit in a message with room to close it is sent without fences.
+			if open && fence == "" {
+				open = false
+				continue
+			}
+
+			// The line doesn't fit in a message of its own, the part that is split off only needs room to close a code block that was already open.
+			room = limit - len(current)
+			if current != "" {
+				room--
+			}
+			if fence != "" {
+				room -= len("\n```")
+			}
+			if room < 1 {
+				room = 1
+			}
+			n := room
+			for n > 0 && !utf8.RuneStart(line[n]) {
+				n--
+			}
+			if n <= 0 {
+				n = room
+			}
+			add(line[:n])
+			line = line[n:]
+			flush()
+		}
+		add(line)
+
+		if toggles {
+			block = !block
+			fence = ""
+			if block && open {
+				fence = codeFence(line, limit)
+			}
+		}
+	}
+
+	if current != "" && current != fence {
+		if fence != "" {
+			current += "\n```"
+		}
+		parts = append(parts, current)
+	}
+	return parts
+}
+
+// codeFence returns the fence that reopens a code block in the messages it's split across, eg. "```go" for a block opened by "```go".
+// The language is left out if it would leave no room in a message, and the block is sent without fences if even "```" would.
+func codeFence(line string, limit int) string {
+	fences := []string{"```"}
+	if t := strings.TrimSpace(line); strings.HasPrefix(t, "```") && !strings.Contains(t, " ") {
+		fences = []string{t, "```"}
+	}
+	for _, fence := range fences {
+		// A message that reopens the block needs room for a character and to close it again.
+		if len(fence)+len("\n\n```") < limit {
+			return fence
+		}
+	}
+	return ""
+}
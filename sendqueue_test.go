@@ -0,0 +1,84 @@
+package rikka
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		limit   int
+		parts   []string
+	}{
+		{"short", "hello", 10, []string{"hello"}},
+		{"lines", "aaaa\nbbbb\ncccc", 10, []string{"aaaa\nbbbb", "cccc"}},
+		{"long line", "aaaaaaaaaaaaaaa", 10, []string{"aaaaaaaaaa", "aaaaa"}},
+		{"code block", "```\naaaa\nbbbb\n```", 12, []string{"```\naaaa\n```", "```\nbbbb\n```"}},
+		{"code block language", "```go\naa\nbb\n```", 14, []string{"```go\naa\n```", "```go\nbb\n```"}},
+		{"runes", "ééééé", 5, []string{"éé", "éé", "é"}},
+		{"closing fence", "```\naaaa\n```", 12, []string{"```\naaaa\n```"}},
+		{"long language", "```aaaaaaa\nbb\n```", 15, []string{"```aaaaaaa\n```", "```\nbb\n```"}},
+		{"long fence", "```aaaaaaaa\nbbbbbb\n```", 12, []string{"```aaaaaaaa", "bbbbbb\n```"}},
+		{"fence over limit", "```aaaaaaaaaa\nbb\n```", 10, []string{"```aaaaaaa", "aaa\nbb\n```"}},
+	}
+
+	for _, test := range tests {
+		parts := splitMessage(test.content, test.limit)
+		if strings.Join(parts, "|") != strings.Join(test.parts, "|") {
+			t.Errorf("%s: splitMessage = %q, want %q", test.name, parts, test.parts)
+		}
+		for _, part := range parts {
+			if len(part) > test.limit {
+				t.Errorf("%s: part %q is longer than %d", test.name, part, test.limit)
+			}
+		}
+	}
+}
+
+func TestSplitMessageLongFence(t *testing.T) {
+	content := "```" + strings.Repeat("x", 1995) + "\n" + strings.Repeat("y", 3000) + "\n```"
+	want := []string{"```" + strings.Repeat("x", 1995), strings.Repeat("y", 2000), strings.Repeat("y", 1000) + "\n```"}
+	if parts := splitMessage(content, MaxMessageLength); strings.Join(parts, "|") != strings.Join(want, "|") {
+		t.Errorf("splitMessage split the message into parts of %d bytes, want %d", partLengths(parts), partLengths(want))
+	}
+}
+
+func partLengths(parts []string) []int {
+	lengths := []int{}
+	for _, part := range parts {
+		lengths = append(lengths, len(part))
+	}
+	return lengths
+}
+
+func TestCoalesce(t *testing.T) {
+	pending := []*sendRequest{
+		{origin: "1", content: "a"},
+		{origin: "1", content: "b"},
+		{origin: "2", content: "c"},
+		{origin: "1", content: "d"},
+		{origin: "1", content: "e", embed: nil},
+		{content: "f"},
+		{content: "g"},
+	}
+
+	batches := coalesce(pending)
+	contents := []string{}
+	for _, batch := range batches {
+		contents = append(contents, batch[0].content)
+	}
+	want := []string{"a\nb", "c", "d\ne", "f\ng"}
+	if strings.Join(contents, "|") != strings.Join(want, "|") {
+		t.Errorf("coalesce = %q, want %q", contents, want)
+	}
+}
+
+func TestCoalesceLimit(t *testing.T) {
+	long := strings.Repeat("a", MaxMessageLength-1)
+	batches := coalesce([]*sendRequest{{content: long}, {content: "b"}, {content: "c"}})
+	if len(batches) != 2 || batches[0][0].content != long || batches[1][0].content != "b\nc" {
+		t.Errorf("coalesce joined messages past MaxMessageLength")
+	}
+}
@@ -0,0 +1,31 @@
+package statsplugin
+
+import "github.com/ThyLeader/rikka"
+
+func init() {
+	rikka.RegisterMessages("en", map[string]string{
+		"stats.help":         "Lists bot statistics.",
+		"stats.title":        "Bot stats",
+		"stats.version":      "GoLang | DiscordGo",
+		"stats.uptime":       "Uptime",
+		"stats.memory":       "Memory used",
+		"stats.memory.value": "%s / %s (%s garbage collected)",
+		"stats.tasks":        "Concurrent tasks",
+		"stats.counts":       "Users | Channels | Guilds",
+		"stats.shards":       "Total Shards | Current Shard",
+		"stats.error":        ":octagonal_sign: : Error getting Bot info - %s",
+	})
+
+	rikka.RegisterMessages("es", map[string]string{
+		"stats.help":         "Muestra las estadísticas del bot.",
+		"stats.title":        "Estadísticas del bot",
+		"stats.version":      "GoLang | DiscordGo",
+		"stats.uptime":       "Tiempo activo",
+		"stats.memory":       "Memoria usada",
+		"stats.memory.value": "%s / %s (%s recolectados)",
+		"stats.tasks":        "Tareas concurrentes",
+		"stats.counts":       "Usuarios | Canales | Servidores",
+		"stats.shards":       "Shards totales | Shard actual",
+		"stats.error":        ":octagonal_sign: : Error al obtener la información del bot - %s",
+	})
+}
@@ -23,27 +23,23 @@ func getDurationString(duration time.Duration) string {
 
 // StatsCommand returns bot statistics.
 func StatsCommand(bot *rikka.Bot, service rikka.Service, message rikka.Message, command string, parts []string) {
-	var users, channels int
-	discord := service.(*rikka.Discord)
+	discord := rikka.DiscordService(service)
 	stats := runtime.MemStats{}
 	runtime.ReadMemStats(&stats)
 
-	for _, e := range discord.Session.State.Ready.Guilds {
-		users += e.MemberCount
-		channels += len(e.Channels)
-	}
+	users, channels := discord.MemberCount()
 
 	guilds := service.ChannelCount()
 
 	embed := &discordgo.MessageEmbed{
-		Title: "Bot stats",
+		Title: bot.Translate(service, message, "stats.title"),
 		Fields: []*discordgo.MessageEmbedField{
-			&discordgo.MessageEmbedField{Name: "GoLang | DiscordGo", Value: fmt.Sprintf("%s | %s", runtime.Version(), discordgo.VERSION), Inline: true},
-			&discordgo.MessageEmbedField{Name: "Uptime", Value: fmt.Sprintf("%s", getDurationString(time.Now().Sub(statsStartTime))), Inline: true},
-			&discordgo.MessageEmbedField{Name: "Memory used", Value: fmt.Sprintf("%s / %s (%s garbage collected)", humanize.Bytes(stats.Alloc), humanize.Bytes(stats.Sys), humanize.Bytes(stats.TotalAlloc)), Inline: true},
-			&discordgo.MessageEmbedField{Name: "Concurrent tasks", Value: fmt.Sprintf("%d", runtime.NumGoroutine()), Inline: true},
-			&discordgo.MessageEmbedField{Name: "Users | Channels | Guilds", Value: fmt.Sprintf("%d | %d | %d", users, channels, guilds), Inline: true},
-			&discordgo.MessageEmbedField{Name: "Total Shards | Current Shard", Value: fmt.Sprintf("%d | %d", discord.Session.ShardCount, discord.Session.ShardID+1), Inline: true},
+			&discordgo.MessageEmbedField{Name: bot.Translate(service, message, "stats.version"), Value: fmt.Sprintf("%s | %s", runtime.Version(), discordgo.VERSION), Inline: true},
+			&discordgo.MessageEmbedField{Name: bot.Translate(service, message, "stats.uptime"), Value: fmt.Sprintf("%s", getDurationString(time.Now().Sub(statsStartTime))), Inline: true},
+			&discordgo.MessageEmbedField{Name: bot.Translate(service, message, "stats.memory"), Value: bot.Translate(service, message, "stats.memory.value", humanize.Bytes(stats.Alloc), humanize.Bytes(stats.Sys), humanize.Bytes(stats.TotalAlloc)), Inline: true},
+			&discordgo.MessageEmbedField{Name: bot.Translate(service, message, "stats.tasks"), Value: fmt.Sprintf("%d", runtime.NumGoroutine()), Inline: true},
+			&discordgo.MessageEmbedField{Name: bot.Translate(service, message, "stats.counts"), Value: fmt.Sprintf("%d | %d | %d", users, channels, guilds), Inline: true},
+			&discordgo.MessageEmbedField{Name: bot.Translate(service, message, "stats.shards"), Value: fmt.Sprintf("%d | %d", discord.Shards, discord.ShardForGuild(message.GuildID())+1), Inline: true},
 		},
 		Thumbnail: &discordgo.MessageEmbedThumbnail{
 			URL: discordgo.EndpointUserAvatar(discord.Session.State.User.ID, discord.Session.State.User.Avatar),
@@ -52,11 +48,11 @@ func StatsCommand(bot *rikka.Bot, service rikka.Service, message rikka.Message,
 		Timestamp: fmt.Sprintf("%s", time.Now().Format(time.RFC3339)),
 	}
 
-	_, err := discord.Session.ChannelMessageSendEmbed(message.Channel(), embed)
+	_, err := service.SendMessageEmbed(message.Channel(), embed)
 	if err != nil {
-		service.SendMessage(message.Channel(), ":octagonal_sign: : Error getting Bot info - "+err.Error())
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "stats.error", err.Error()))
 	}
 }
 
 // StatsHelp is the help for the stats command.
-var StatsHelp = rikka.NewCommandHelp("", "Lists bot statistics.")
+var StatsHelp = rikka.NewCommandHelp("", "stats.help")
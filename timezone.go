@@ -0,0 +1,75 @@
+package rikka
+
+import (
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// TimeFormat is the layout times are shown to users in.
+const TimeFormat = "Mon 2006-01-02 15:04 MST"
+
+var locationsMutex sync.Mutex
+var locations = map[string]*time.Location{}
+
+// LoadLocation returns the location for an IANA time zone name like "Europe/Madrid".
+// Locations are cached, and the server's local zone is not accepted as it differs between machines.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" || strings.EqualFold(name, "local") {
+		return nil, ErrUnknownTimezone
+	}
+
+	locationsMutex.Lock()
+	defer locationsMutex.Unlock()
+
+	if l, ok := locations[name]; ok {
+		return l, nil
+	}
+	// Zone names are case sensitive, so names typed in lower case are also tried in their usual casing.
+	for _, n := range []string{name, titleZone(name), strings.ToUpper(name)} {
+		if l, err := time.LoadLocation(n); err == nil {
+			locations[name] = l
+			return l, nil
+		}
+	}
+	return nil, ErrUnknownTimezone
+}
+
+// titleZone capitalizes each word of a zone name, eg. "america/new_york" becomes "America/New_York".
+func titleZone(name string) string {
+	b := []byte(strings.ToLower(name))
+	for i := range b {
+		if i == 0 || b[i-1] == '/' || b[i-1] == '_' || b[i-1] == '-' {
+			b[i] = byte(unicode.ToUpper(rune(b[i])))
+		}
+	}
+	return string(b)
+}
+
+// Location returns the time zone selected for a user in a guild.
+// A user's own time zone takes priority over the guild time zone, which takes priority over UTC.
+func (b *Bot) Location(service Service, guildID, userID string) *time.Location {
+	s := b.Services[service.Name()]
+	if s == nil || s.timezone == nil {
+		return time.UTC
+	}
+	if l, err := LoadLocation(s.timezone.timezone(guildID, userID)); err == nil {
+		return l
+	}
+	return time.UTC
+}
+
+// MessageLocation returns the time zone of the sender of a message.
+func (b *Bot) MessageLocation(service Service, message Message) *time.Location {
+	guildID := ""
+	if !service.IsPrivate(message) {
+		guildID = message.GuildID()
+	}
+	return b.Location(service, guildID, message.UserID())
+}
+
+// FormatTime formats a time in the time zone of the sender of a message.
+func (b *Bot) FormatTime(service Service, message Message, t time.Time) string {
+	return t.In(b.MessageLocation(service, message)).Format(TimeFormat)
+}
@@ -0,0 +1,168 @@
+package rikka
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrUnknownTimezone is returned when a time zone name isn't a known IANA time zone.
+var ErrUnknownTimezone = errors.New("unknown time zone")
+
+type timezonePlugin struct {
+	sync.RWMutex
+
+	Guilds map[string]string
+	// Users is only read to move user timezones from older saves into Redis, where they are shared by every process.
+	Users map[string]string `json:",omitempty"`
+
+	users *userPreferences
+}
+
+// Name returns the name of the plugin.
+func (p *timezonePlugin) Name() string {
+	return "Timezone"
+}
+
+func (p *timezonePlugin) timezone(guildID, userID string) string {
+	p.RLock()
+	defer p.RUnlock()
+
+	if z, ok := p.users.get(userID); ok {
+		return z
+	}
+	if z, ok := p.Guilds[guildID]; ok && guildID != "" {
+		return z
+	}
+	return "UTC"
+}
+
+// Help returns a list of help strings that are printed when the user requests them.
+func (p *timezonePlugin) Help(bot *Bot, service Service, message Message, detailed bool) []string {
+	help := CommandHelp(service, "timezone", "[zone]", bot.Translate(service, message, "timezone.help"))
+	if detailed {
+		help = append(help, []string{
+			CommandHelp(service, "timezone", "reset", bot.Translate(service, message, "timezone.help.reset"))[0],
+			CommandHelp(service, "timezone", "guild <zone|reset>", bot.Translate(service, message, "timezone.help.guild"))[0],
+			bot.Translate(service, message, "timezone.examples"),
+		}...)
+	}
+	return help
+}
+
+func (p *timezonePlugin) set(zones map[string]string, id, zone string) {
+	p.Lock()
+	defer p.Unlock()
+
+	if zone == "" {
+		delete(zones, id)
+		return
+	}
+	zones[id] = zone
+}
+
+// Message handler.
+func (p *timezonePlugin) Message(bot *Bot, service Service, message Message) {
+	defer MessageRecover()
+
+	if service.IsMe(message) {
+		return
+	}
+
+	if !MatchesCommand(service, "timezone", message) && !MatchesCommand(service, "tz", message) {
+		return
+	}
+
+	_, parts := ParseCommand(service, message)
+
+	if len(parts) == 0 {
+		l := bot.MessageLocation(service, message)
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "timezone.current", l.String(), time.Now().In(l).Format(TimeFormat)))
+		return
+	}
+
+	guild := false
+	if strings.ToLower(parts[0]) == "guild" || strings.ToLower(parts[0]) == "server" {
+		guild = true
+		parts = parts[1:]
+	}
+
+	if len(parts) != 1 {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "timezone.usage", service.CommandPrefix()))
+		return
+	}
+
+	zone := parts[0]
+	if strings.ToLower(zone) == "reset" {
+		zone = ""
+	} else {
+		l, err := LoadLocation(zone)
+		if err != nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "timezone.unknown", zone))
+			return
+		}
+		// The canonical name is stored, so "utc" is saved as "UTC".
+		zone = l.String()
+	}
+
+	if !guild {
+		if err := p.users.set(service, message.UserID(), zone); err != nil {
+			service.SendMessage(message.Channel(), bot.Translate(service, message, "error.generic", err.Error()))
+			return
+		}
+		l := bot.MessageLocation(service, message)
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "timezone.set.user", l.String(), time.Now().In(l).Format(TimeFormat)))
+		return
+	}
+
+	if service.IsPrivate(message) {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "error.private"))
+		return
+	}
+
+	if !service.IsModerator(message) {
+		service.SendMessage(message.Channel(), bot.Translate(service, message, "timezone.moderator"))
+		return
+	}
+
+	p.set(p.Guilds, message.GuildID(), zone)
+	service.SendMessage(message.Channel(), bot.Translate(service, message, "timezone.set.guild", bot.Location(service, message.GuildID(), "").String()))
+}
+
+// Load will load plugin state from a byte array.
+func (p *timezonePlugin) Load(bot *Bot, service Service, data []byte) error {
+	if data != nil {
+		if err := json.Unmarshal(data, p); err != nil {
+			log.Println("Error loading data", err)
+		}
+	}
+
+	p.Lock()
+	p.users.migrate(p.Users)
+	p.Unlock()
+	p.users.load(service)
+	return nil
+}
+
+// Save will save plugin state to a byte array.
+func (p *timezonePlugin) Save() ([]byte, error) {
+	p.RLock()
+	defer p.RUnlock()
+	return json.Marshal(p)
+}
+
+// Stats will return the stats for a plugin.
+func (p *timezonePlugin) Stats(bot *Bot, service Service, message Message) []string {
+	return nil
+}
+
+func newTimezonePlugin() *timezonePlugin {
+	return &timezonePlugin{
+		Guilds: make(map[string]string),
+		Users:  make(map[string]string),
+		users:  newUserPreferences("timezone:users"),
+	}
+}